@@ -4,7 +4,6 @@ package app
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"time"
 	"weather/client/config"
 	"weather/client/engine"
@@ -35,16 +34,7 @@ func (a *App) Run() error {
 	defer cancel()
 
 	// Use SSE client transport
-	// transport := mcp.NewSSEClientTransport("http://localhost:8080/mcp/stream", &mcp.SSEClientTransportOptions{})
-
-	// Create a command-based transport to run the MCP server as a child process via stdin/stdout
-	cmd := exec.Command("npx", "-y", "mcp-echarts")
-	echartsTransport := mcp.NewCommandTransport(cmd)
-
-	echartsSession, err := a.client.Connect(ctx, echartsTransport)
-	if err != nil {
-		return fmt.Errorf("connect failed: %w", err)
-	}
+	// transport := mcp.NewSSEClientTransport("http://localhost:8080/mcp/sse", &mcp.SSEClientTransportOptions{})
 
 	// Create a streamable client transport to communicate with the MCP server
 	waetherTransport := mcp.NewStreamableClientTransport(
@@ -58,7 +48,6 @@ func (a *App) Run() error {
 	}
 
 	a.mcps = map[string]*mcp.ClientSession{
-		"echarts": echartsSession,
 		"weather": weatherSession,
 	}
 
@@ -201,7 +201,7 @@ func ToTypedContents(content mcp.Content) (string, error) {
 	case *mcp.TextContent:
 		return v.Text, nil
 	case *mcp.ImageContent:
-		url, err := ImageContentToURL(v.Data)
+		url, err := ImageContentToURL(v.Data, v.MIMEType)
 		if err != nil {
 			return "", err
 		}
@@ -211,8 +211,12 @@ func ToTypedContents(content mcp.Content) (string, error) {
 	}
 }
 
-func ImageContentToURL(data []byte) (string, error) {
-	filename := fmt.Sprintf("chart_%d.png", time.Now().Unix())
+func ImageContentToURL(data []byte, mimeType string) (string, error) {
+	ext := "png"
+	if mimeType == "image/svg+xml" {
+		ext = "svg"
+	}
+	filename := fmt.Sprintf("chart_%d.%s", time.Now().UnixNano(), ext)
 
 	if err := os.MkdirAll("./static/images", 0755); err != nil {
 		return "", fmt.Errorf("failed to create directory: %w", err)
@@ -0,0 +1,130 @@
+// Package activity scores hourly forecasts against the weather limits of an
+// outdoor activity, such as the wind limit for flying a drone, and groups the
+// hours into go and no-go windows.
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Profile is the set of weather limits an activity can tolerate. Nil limits
+// and an empty MaxLightningRisk are not checked. Temperature limits apply to
+// the apparent (feels-like) temperature when it is known.
+type Profile struct {
+	Name               string   `mapstructure:"name" json:"name"`
+	Description        string   `mapstructure:"description" json:"description,omitempty"`
+	MinTempF           *float64 `mapstructure:"min_temp_f" json:"minTempF,omitempty"`
+	MaxTempF           *float64 `mapstructure:"max_temp_f" json:"maxTempF,omitempty"`
+	MaxWindMph         *float64 `mapstructure:"max_wind_mph" json:"maxWindMph,omitempty"`
+	MaxGustMph         *float64 `mapstructure:"max_gust_mph" json:"maxGustMph,omitempty"`
+	MaxPrecipChance    *float64 `mapstructure:"max_precip_chance" json:"maxPrecipChance,omitempty"`
+	MinVisibilityMiles *float64 `mapstructure:"min_visibility_miles" json:"minVisibilityMiles,omitempty"`
+	MaxLightningRisk   string   `mapstructure:"max_lightning_risk" json:"maxLightningRisk,omitempty"`
+}
+
+func limit(v float64) *float64 { return &v }
+
+// Defaults returns the built-in profiles.
+func Defaults() []Profile {
+	return []Profile{
+		{
+			Name:             "running",
+			Description:      "Outdoor running",
+			MinTempF:         limit(20),
+			MaxTempF:         limit(90),
+			MaxWindMph:       limit(25),
+			MaxPrecipChance:  limit(50),
+			MaxLightningRisk: "none",
+		},
+		{
+			Name:             "cycling",
+			Description:      "Road cycling",
+			MinTempF:         limit(35),
+			MaxTempF:         limit(95),
+			MaxWindMph:       limit(20),
+			MaxGustMph:       limit(30),
+			MaxPrecipChance:  limit(30),
+			MaxLightningRisk: "none",
+		},
+		{
+			Name:             "picnic",
+			Description:      "Picnic or outdoor gathering",
+			MinTempF:         limit(60),
+			MaxTempF:         limit(90),
+			MaxWindMph:       limit(15),
+			MaxPrecipChance:  limit(20),
+			MaxLightningRisk: "none",
+		},
+		{
+			Name:               "drone",
+			Description:        "Small drone flying (FAA Part 107 visibility)",
+			MinTempF:           limit(32),
+			MaxTempF:           limit(104),
+			MaxWindMph:         limit(20),
+			MaxGustMph:         limit(25),
+			MaxPrecipChance:    limit(20),
+			MinVisibilityMiles: limit(3),
+			MaxLightningRisk:   "none",
+		},
+		{
+			Name:             "crane",
+			Description:      "Construction crane lifts",
+			MaxWindMph:       limit(20),
+			MaxGustMph:       limit(30),
+			MaxLightningRisk: "none",
+		},
+	}
+}
+
+// LoadProfiles reads profiles from a YAML, TOML or JSON file with a
+// top-level "profiles" list, for example:
+//
+//	profiles:
+//	  - name: kite-surfing
+//	    min_temp_f: 55
+//	    max_gust_mph: 35
+//	    max_lightning_risk: none
+func LoadProfiles(path string) ([]Profile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("activity: reading %s: %w", path, err)
+	}
+
+	var profiles []Profile
+	if err := v.UnmarshalKey("profiles", &profiles); err != nil {
+		return nil, fmt.Errorf("activity: decoding %s: %w", path, err)
+	}
+	var errs []error
+	for i := range profiles {
+		if err := profiles[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("profile %d: %w", i+1, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("activity: %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// Validate checks that a profile has a name, a known lightning risk and
+// consistent limits, and normalizes its name to lower case.
+func (p *Profile) Validate() error {
+	p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+	if p.Name == "" {
+		return errors.New("missing name")
+	}
+	if p.MaxLightningRisk != "" {
+		if _, ok := parseRisk(p.MaxLightningRisk); !ok {
+			return fmt.Errorf("%s: max_lightning_risk must be none, low, moderate or high, not %q", p.Name, p.MaxLightningRisk)
+		}
+	}
+	if p.MinTempF != nil && p.MaxTempF != nil && *p.MinTempF > *p.MaxTempF {
+		return fmt.Errorf("%s: min_temp_f is above max_temp_f", p.Name)
+	}
+	return nil
+}
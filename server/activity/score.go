@@ -0,0 +1,199 @@
+package activity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+)
+
+// Risk is a level of lightning risk.
+type Risk int
+
+const (
+	RiskNone Risk = iota
+	RiskLow
+	RiskModerate
+	RiskHigh
+)
+
+var riskNames = [...]string{"none", "low", "moderate", "high"}
+
+func (r Risk) String() string { return riskNames[r] }
+
+func parseRisk(s string) (Risk, bool) {
+	i := slices.Index(riskNames[:], strings.ToLower(strings.TrimSpace(s)))
+	return Risk(i), i >= 0
+}
+
+// LightningRisk estimates the lightning risk of an hour from its short
+// forecast: "Slight Chance Thunderstorms" is low, "Chance", "Isolated" or
+// "Scattered" thunderstorms moderate, and anything more certain high. A
+// forecast that changes, such as "Slight Chance T-storms then Chance
+// Thunderstorms", takes the highest risk of its parts.
+func LightningRisk(shortForecast string) Risk {
+	risk := RiskNone
+	for _, part := range strings.Split(strings.ToLower(shortForecast), " then ") {
+		risk = max(risk, clauseRisk(part))
+	}
+	return risk
+}
+
+// clauseRisk is the lightning risk of one part of a lower-cased short
+// forecast.
+func clauseRisk(s string) Risk {
+	if !strings.Contains(s, "thunder") && !strings.Contains(s, "t-storm") {
+		return RiskNone
+	}
+	switch {
+	case strings.Contains(s, "slight chance"):
+		return RiskLow
+	case strings.Contains(s, "chance"), strings.Contains(s, "isolated"), strings.Contains(s, "scattered"):
+		return RiskModerate
+	}
+	return RiskHigh
+}
+
+// Reason is a limit an hour exceeds. Limit is the profile setting, such as
+// "max_wind_mph"; Detail describes the forecast value.
+type Reason struct {
+	Limit  string `json:"limit"`
+	Detail string `json:"detail"`
+}
+
+// Hour is the verdict for one forecast hour. Unknown lists the limits that
+// could not be checked because the forecast lacks that element.
+type Hour struct {
+	Start   time.Time `json:"start"`
+	Go      bool      `json:"go"`
+	Reasons []Reason  `json:"reasons,omitempty"`
+	Unknown []string  `json:"unknown,omitempty"`
+}
+
+// Check evaluates one forecast hour against the profile, returning why it is
+// a no-go (nothing when it is a go) and which limits could not be checked.
+func (p Profile) Check(h dtos.HourlyPeriod) (reasons []Reason, unknown []string) {
+	exceeds := func(limit, format string, args ...any) {
+		reasons = append(reasons, Reason{Limit: limit, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	temp, label := h.Temperature, "temperature"
+	if h.ApparentTemperature != nil {
+		temp, label = *h.ApparentTemperature, "feels like"
+	}
+	if p.MinTempF != nil && temp < *p.MinTempF {
+		exceeds("min_temp_f", "%s %.0f°F below %.0f°F", label, temp, *p.MinTempF)
+	}
+	if p.MaxTempF != nil && temp > *p.MaxTempF {
+		exceeds("max_temp_f", "%s %.0f°F above %.0f°F", label, temp, *p.MaxTempF)
+	}
+	if p.MaxWindMph != nil && h.WindSpeed > *p.MaxWindMph {
+		exceeds("max_wind_mph", "wind %.0f mph above %.0f mph", h.WindSpeed, *p.MaxWindMph)
+	}
+	if p.MaxGustMph != nil {
+		// Without a gust forecast the sustained wind is the best lower bound.
+		gust := h.WindSpeed
+		if h.WindGust != nil {
+			gust = *h.WindGust
+		}
+		if gust > *p.MaxGustMph {
+			exceeds("max_gust_mph", "gusts %.0f mph above %.0f mph", gust, *p.MaxGustMph)
+		}
+	}
+	if p.MaxPrecipChance != nil {
+		switch pop := h.ProbabilityOfPrecipitation; {
+		case pop == nil:
+			unknown = append(unknown, "max_precip_chance")
+		case *pop > *p.MaxPrecipChance:
+			exceeds("max_precip_chance", "precipitation chance %.0f%% above %.0f%%", *pop, *p.MaxPrecipChance)
+		}
+	}
+	if p.MinVisibilityMiles != nil {
+		switch vis := h.Visibility; {
+		case vis == nil:
+			unknown = append(unknown, "min_visibility_miles")
+		case *vis < *p.MinVisibilityMiles:
+			exceeds("min_visibility_miles", "visibility %.1f mi below %.0f mi", *vis, *p.MinVisibilityMiles)
+		}
+	}
+	if allowed, ok := parseRisk(p.MaxLightningRisk); ok {
+		if risk := LightningRisk(h.ShortForecast); risk > allowed {
+			exceeds("max_lightning_risk", "%s lightning risk (%s)", risk, h.ShortForecast)
+		}
+	}
+	return reasons, unknown
+}
+
+// Score evaluates each hour starting within [from, to).
+func (p Profile) Score(periods []dtos.HourlyPeriod, from, to time.Time) []Hour {
+	var hours []Hour
+	for _, h := range periods {
+		start, err := time.Parse(time.RFC3339, h.StartTime)
+		if err != nil || start.Before(from) || !start.Before(to) {
+			continue
+		}
+		reasons, unknown := p.Check(h)
+		hours = append(hours, Hour{Start: start, Go: len(reasons) == 0, Reasons: reasons, Unknown: unknown})
+	}
+	return hours
+}
+
+// Window is a run of consecutive hours with the same verdict. Reasons holds
+// the first occurrence of each limit exceeded in the window.
+type Window struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Go      bool      `json:"go"`
+	Reasons []Reason  `json:"reasons,omitempty"`
+}
+
+// Windows groups scored hours into go and no-go windows.
+func Windows(hours []Hour) []Window {
+	var out []Window
+	for _, h := range hours {
+		end := h.Start.Add(time.Hour)
+		if n := len(out); n > 0 && out[n-1].Go == h.Go && out[n-1].End.Equal(h.Start) {
+			out[n-1].End = end
+			out[n-1].Reasons = mergeReasons(out[n-1].Reasons, h.Reasons)
+			continue
+		}
+		out = append(out, Window{Start: h.Start, End: end, Go: h.Go, Reasons: mergeReasons(nil, h.Reasons)})
+	}
+	return out
+}
+
+// mergeReasons adds the reasons for limits not yet listed.
+func mergeReasons(have, add []Reason) []Reason {
+	for _, r := range add {
+		if !slices.ContainsFunc(have, func(h Reason) bool { return h.Limit == r.Limit }) {
+			have = append(have, r)
+		}
+	}
+	return have
+}
+
+// Report is the scored forecast for an activity over a window.
+type Report struct {
+	Profile   Profile  `json:"profile"`
+	Source    string   `json:"source"`
+	Windows   []Window `json:"windows"`
+	Hours     []Hour   `json:"hours"`
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+// Evaluate scores the hours of a forecast starting within [from, to) and
+// groups them into windows. Unchecked lists the limits the forecast had no
+// data for in any hour.
+func (p Profile) Evaluate(source string, periods []dtos.HourlyPeriod, from, to time.Time) *Report {
+	r := &Report{Profile: p, Source: source, Hours: p.Score(periods, from, to)}
+	r.Windows = Windows(r.Hours)
+	for _, h := range r.Hours {
+		for _, u := range h.Unknown {
+			if !slices.Contains(r.Unchecked, u) {
+				r.Unchecked = append(r.Unchecked, u)
+			}
+		}
+	}
+	return r
+}
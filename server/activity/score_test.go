@@ -0,0 +1,142 @@
+package activity
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"weather/server/dtos"
+)
+
+func TestLightningRisk(t *testing.T) {
+	tests := []struct {
+		forecast string
+		want     Risk
+	}{
+		{"Sunny", RiskNone},
+		{"Chance Rain Showers", RiskNone},
+		{"Slight Chance Showers And Thunderstorms", RiskLow},
+		{"Slight Chance T-storms", RiskLow},
+		{"Chance Showers And Thunderstorms", RiskModerate},
+		{"Isolated Thunderstorms", RiskModerate},
+		{"Scattered T-storms", RiskModerate},
+		{"Showers And Thunderstorms Likely", RiskHigh},
+		{"Thunderstorms", RiskHigh},
+		// A changing forecast takes its highest part.
+		{"Slight Chance T-storms then Chance Thunderstorms", RiskModerate},
+		{"Slight Chance Rain Showers then Thunderstorms", RiskHigh},
+		{"Chance Thunderstorms then Sunny", RiskModerate},
+	}
+	for _, tt := range tests {
+		if got := LightningRisk(tt.forecast); got != tt.want {
+			t.Errorf("LightningRisk(%q) = %s, want %s", tt.forecast, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := Profile{
+		MinTempF:           limit(32),
+		MaxTempF:           limit(95),
+		MaxWindMph:         limit(20),
+		MaxGustMph:         limit(25),
+		MaxPrecipChance:    limit(30),
+		MinVisibilityMiles: limit(3),
+		MaxLightningRisk:   "low",
+	}
+	// fine is an hour within every limit.
+	fine := func() dtos.HourlyPeriod {
+		return dtos.HourlyPeriod{
+			Temperature:                70,
+			WindSpeed:                  10,
+			WindGust:                   limit(15),
+			ProbabilityOfPrecipitation: limit(10),
+			Visibility:                 limit(10),
+			ShortForecast:              "Slight Chance T-storms",
+		}
+	}
+	tests := []struct {
+		name    string
+		edit    func(*dtos.HourlyPeriod)
+		reasons []string
+		unknown []string
+	}{
+		{"within limits", func(h *dtos.HourlyPeriod) {}, nil, nil},
+		{"cold", func(h *dtos.HourlyPeriod) { h.Temperature = 31 }, []string{"min_temp_f"}, nil},
+		{"hot", func(h *dtos.HourlyPeriod) { h.Temperature = 96 }, []string{"max_temp_f"}, nil},
+		// Temperature limits apply to the apparent temperature when known.
+		{"feels hot", func(h *dtos.HourlyPeriod) { h.Temperature, h.ApparentTemperature = 92, limit(104) }, []string{"max_temp_f"}, nil},
+		{"feels fine", func(h *dtos.HourlyPeriod) { h.Temperature, h.ApparentTemperature = 30, limit(33) }, nil, nil},
+		{"at the limits", func(h *dtos.HourlyPeriod) {
+			h.Temperature, h.WindSpeed, h.WindGust, h.ProbabilityOfPrecipitation, h.Visibility = 95, 20, limit(25), limit(30), limit(3)
+		}, nil, nil},
+		{"windy", func(h *dtos.HourlyPeriod) { h.WindSpeed, h.WindGust = 21, limit(24) }, []string{"max_wind_mph"}, nil},
+		{"gusty", func(h *dtos.HourlyPeriod) { h.WindGust = limit(26) }, []string{"max_gust_mph"}, nil},
+		// Without a gust forecast the sustained wind stands in for it.
+		{"no gust forecast", func(h *dtos.HourlyPeriod) { h.WindSpeed, h.WindGust = 26, nil }, []string{"max_wind_mph", "max_gust_mph"}, nil},
+		{"no gust forecast, light wind", func(h *dtos.HourlyPeriod) { h.WindGust = nil }, nil, nil},
+		{"wet", func(h *dtos.HourlyPeriod) { h.ProbabilityOfPrecipitation = limit(40) }, []string{"max_precip_chance"}, nil},
+		{"foggy", func(h *dtos.HourlyPeriod) { h.Visibility = limit(0.5) }, []string{"min_visibility_miles"}, nil},
+		{"stormy", func(h *dtos.HourlyPeriod) { h.ShortForecast = "Slight Chance T-storms then Chance Thunderstorms" }, []string{"max_lightning_risk"}, nil},
+		{"no data", func(h *dtos.HourlyPeriod) { h.ProbabilityOfPrecipitation, h.Visibility = nil, nil }, nil, []string{"max_precip_chance", "min_visibility_miles"}},
+	}
+	for _, tt := range tests {
+		h := fine()
+		tt.edit(&h)
+		reasons, unknown := p.Check(h)
+		var limits []string
+		for _, r := range reasons {
+			limits = append(limits, r.Limit)
+		}
+		if !slices.Equal(limits, tt.reasons) {
+			t.Errorf("%s: reasons = %q, want %q", tt.name, limits, tt.reasons)
+		}
+		if !slices.Equal(unknown, tt.unknown) {
+			t.Errorf("%s: unknown = %q, want %q", tt.name, unknown, tt.unknown)
+		}
+	}
+
+	// An empty or unknown lightning limit is not checked.
+	h := fine()
+	h.ShortForecast = "Thunderstorms"
+	for _, risk := range []string{"", "extreme"} {
+		if reasons, _ := (Profile{MaxLightningRisk: risk}).Check(h); len(reasons) != 0 {
+			t.Errorf("MaxLightningRisk %q: reasons = %v", risk, reasons)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 8, 5, hour, 0, 0, 0, time.UTC) }
+	windy := []Reason{{Limit: "max_wind_mph", Detail: "wind 25 mph above 20 mph"}}
+	gusty := []Reason{{Limit: "max_gust_mph", Detail: "gusts 35 mph above 30 mph"}}
+	windier := []Reason{{Limit: "max_wind_mph", Detail: "wind 30 mph above 20 mph"}}
+
+	hours := []Hour{
+		{Start: at(6), Go: true},
+		{Start: at(7), Go: true},
+		{Start: at(8), Reasons: windy},
+		{Start: at(9), Reasons: slices.Concat(windier, gusty)},
+		{Start: at(10), Go: true},
+		// A gap in the hours starts a new window even with the same verdict.
+		{Start: at(12), Go: true},
+	}
+	want := []Window{
+		{Start: at(6), End: at(8), Go: true},
+		{Start: at(8), End: at(10), Reasons: slices.Concat(windy, gusty)},
+		{Start: at(10), End: at(11), Go: true},
+		{Start: at(12), End: at(13), Go: true},
+	}
+	got := Windows(hours)
+	if len(got) != len(want) {
+		t.Fatalf("Windows = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.Start.Equal(w.Start) || !g.End.Equal(w.End) || g.Go != w.Go || !slices.Equal(g.Reasons, w.Reasons) {
+			t.Errorf("window %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if Windows(nil) != nil {
+		t.Error("Windows(nil) is not empty")
+	}
+}
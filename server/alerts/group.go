@@ -0,0 +1,124 @@
+package alerts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/vtec"
+)
+
+// Event gathers every alert message referring to the same VTEC-tracked
+// hazard, so that updates (NEW, CON, EXT, CAN, EXP...) are reported once.
+type Event struct {
+	Key      string     `json:"key"`
+	Name     string     `json:"name"`
+	Office   string     `json:"office"`
+	ETN      int        `json:"etn"`
+	Action   string     `json:"action"`
+	Actions  []string   `json:"actions"`
+	Ends     *time.Time `json:"ends,omitempty"`
+	Partial  bool       `json:"partial,omitempty"`
+	AlertIDs []string   `json:"alertIds"`
+	Summary  string     `json:"summary"`
+}
+
+// GroupEvents groups alerts by VTEC event. Alerts without a P-VTEC string
+// are not tracked and are left out of the result.
+func GroupEvents(features []dtos.Feature) []Event {
+	type segment struct {
+		v    vtec.VTEC
+		sent time.Time
+	}
+
+	var (
+		order    []string
+		segments = make(map[string][]segment)
+		ids      = make(map[string][]string)
+	)
+	var (
+		msgs  []vtec.Message
+		owner []string
+	)
+	for _, f := range features {
+		// Unparseable sent times sort first, as the oldest messages.
+		sent, _ := time.Parse(time.RFC3339, f.Sent)
+		onset, _ := time.Parse(time.RFC3339, f.Onset)
+		for _, v := range vtec.ParseAll(f.Parameters["VTEC"]) {
+			msgs = append(msgs, vtec.Message{VTEC: v, Sent: sent, Onset: onset})
+			owner = append(owner, f.ID)
+		}
+	}
+	for i, key := range vtec.EventKeys(msgs) {
+		if _, ok := segments[key]; !ok {
+			order = append(order, key)
+		}
+		segments[key] = append(segments[key], segment{v: msgs[i].VTEC, sent: msgs[i].Sent})
+		if !slices.Contains(ids[key], owner[i]) {
+			ids[key] = append(ids[key], owner[i])
+		}
+	}
+
+	events := make([]Event, 0, len(order))
+	for _, key := range order {
+		segs := segments[key]
+		slices.SortStableFunc(segs, func(a, b segment) int { return a.sent.Compare(b.sent) })
+
+		first, latest := segs[0].v, segs[len(segs)-1].v
+		e := Event{
+			Key:      key,
+			Name:     first.Name(),
+			Office:   first.Office,
+			ETN:      first.ETN,
+			Action:   latest.Action,
+			AlertIDs: ids[key],
+		}
+
+		var active, ended bool
+		for _, s := range segs {
+			if !slices.Contains(e.Actions, s.v.Action) {
+				e.Actions = append(e.Actions, s.v.Action)
+			}
+			if s.v.IsTerminal() {
+				ended = true
+				continue
+			}
+			active = true
+			if s.v.End != nil && (e.Ends == nil || s.v.End.After(*e.Ends)) {
+				e.Ends = s.v.End
+			}
+		}
+		if active && latest.IsTerminal() {
+			// The newest message ended the event for some zones only;
+			// report what still applies to the rest.
+			for i := len(segs) - 1; i >= 0; i-- {
+				if !segs[i].v.IsTerminal() {
+					e.Action = segs[i].v.Action
+					break
+				}
+			}
+		}
+		e.Partial = active && ended
+		e.Summary = summarize(e, segs[len(segs)-1].sent.Location())
+		events = append(events, e)
+	}
+	return events
+}
+
+// summarize describes an event, giving its end in loc, the offset the
+// alerts were sent in.
+func summarize(e Event, loc *time.Location) string {
+	desc := vtec.VTEC{Action: e.Action}.ActionDescription()
+	s := fmt.Sprintf("%s #%d from %s %s", e.Name, e.ETN, e.Office, desc)
+	if e.Ends != nil && !(vtec.VTEC{Action: e.Action}).IsTerminal() {
+		s += " until " + localTime(*e.Ends, loc, e.Office)
+	}
+	if e.Partial {
+		s += " (cancelled or expired for part of the area)"
+	}
+	if len(e.Actions) > 1 {
+		s += "; updates: " + strings.Join(e.Actions, ", ")
+	}
+	return s
+}
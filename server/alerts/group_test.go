@@ -0,0 +1,73 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"weather/server/dtos"
+)
+
+func alert(id, sent, vtec string) dtos.Feature {
+	return dtos.Feature{ID: id, AlertProperties: dtos.AlertProperties{
+		Sent:       sent,
+		Parameters: map[string][]string{"VTEC": {vtec}},
+	}}
+}
+
+func TestGroupEvents(t *testing.T) {
+	features := []dtos.Feature{
+		alert("a", "2025-08-05T13:00:00-05:00", "/O.NEW.KEWX.SV.W.0012.250805T1800Z-250805T2300Z/"),
+		// Sent at 19:10Z, after the CON below even though it sorts first
+		// as a string.
+		alert("b", "2025-08-05T14:10:00-05:00", "/O.EXT.KEWX.SV.W.0012.000000T0000Z-250806T0000Z/"),
+		alert("c", "2025-08-05T15:00:00-04:00", "/O.CON.KEWX.SV.W.0012.000000T0000Z-250805T2300Z/"),
+		// The same ETN a year earlier is a different event.
+		alert("d", "2024-08-05T13:00:00-05:00", "/O.NEW.KEWX.SV.W.0012.240805T1800Z-240805T2300Z/"),
+	}
+	events := GroupEvents(features)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+
+	e := events[0]
+	if e.Key != "2025.KEWX.SV.W.0012" {
+		t.Errorf("Key = %q", e.Key)
+	}
+	if e.Action != "EXT" {
+		t.Errorf("Action = %s, want EXT from the latest message", e.Action)
+	}
+	if want := "until Tue Aug 5 7:00 PM CDT"; !strings.Contains(e.Summary, want) {
+		t.Errorf("Summary = %q, want it to contain %q", e.Summary, want)
+	}
+	if got := strings.Join(e.AlertIDs, ","); got != "a,b,c" {
+		t.Errorf("AlertIDs = %s, want a,b,c", got)
+	}
+
+	if events[1].Key != "2024.KEWX.SV.W.0012" {
+		t.Errorf("second event Key = %q", events[1].Key)
+	}
+}
+
+func TestGroupEventsAcrossNewYear(t *testing.T) {
+	features := []dtos.Feature{
+		alert("a", "2025-12-31T20:00:00Z", "/O.NEW.KBOU.WS.W.0090.251231T2200Z-260101T1800Z/"),
+		alert("b", "2026-01-01T02:00:00Z", "/O.CON.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/"),
+	}
+	events := GroupEvents(features)
+	if len(events) != 1 || events[0].Key != "2025.KBOU.WS.W.0090" || len(events[0].AlertIDs) != 2 {
+		t.Errorf("GroupEvents = %+v, want one 2025 event with both alerts", events)
+	}
+}
+
+func TestLocalTimeOffices(t *testing.T) {
+	features := []dtos.Feature{
+		alert("a", "2025-08-05T11:00:00-07:00", "/O.NEW.KPSR.EH.W.0003.250805T1800Z-250806T0300Z/"),
+		alert("b", "2025-08-05T11:00:00-07:00", "/O.NEW.KLOX.EH.W.0004.250805T1800Z-250806T0300Z/"),
+		alert("c", "2025-01-05T11:00:00Z", "/O.NEW.KLOX.WI.Y.0001.250105T1800Z-250106T0300Z/"),
+	}
+	want := []string{"until Tue Aug 5 8:00 PM MST", "until Tue Aug 5 8:00 PM PDT", "until Mon Jan 6 3:00 AM UTC"}
+	for i, e := range GroupEvents(features) {
+		if !strings.Contains(e.Summary, want[i]) {
+			t.Errorf("Summary = %q, want it to contain %q", e.Summary, want[i])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"time"
+	"weather/server/tz"
+)
+
+// usZones are the timezones NWS offices issue alerts in, tried in order when
+// naming an offset. Where two share an offset, the more populous comes first.
+var usZones = []string{
+	"America/New_York", "America/Chicago", "America/Denver", "America/Los_Angeles",
+	"America/Phoenix", "America/Anchorage", "Pacific/Honolulu", "America/Puerto_Rico",
+	"Pacific/Guam", "Pacific/Pago_Pago",
+}
+
+// officeZones names the timezone of offices whose offset is shared with a
+// zone earlier in usZones for part of the year.
+var officeZones = map[string]string{
+	"KPSR": "America/Phoenix",
+	"KTWC": "America/Phoenix",
+	"KFGZ": "America/Phoenix",
+	"TJSJ": "America/Puerto_Rico",
+}
+
+// localTime formats t in loc, a fixed offset taken from an alert, naming the
+// offset with the abbreviation of the US timezone using it at t, e.g.
+// "Tue Aug 5 7:00 PM CDT" rather than "-0500".
+func localTime(t time.Time, loc *time.Location, office string) string {
+	const layout = "Mon Jan 2 3:04 PM MST"
+	t = t.In(loc)
+	_, offset := t.Zone()
+	if offset == 0 {
+		return t.UTC().Format(layout)
+	}
+
+	names := usZones
+	if name, ok := officeZones[office]; ok {
+		names = append([]string{name}, usZones...)
+	}
+	for _, name := range names {
+		zone := tz.Load(name)
+		if _, off := t.In(zone).Zone(); off == offset {
+			return t.In(zone).Format(layout)
+		}
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,128 @@
+// Package alerts implements processing of NWS alerts beyond what the raw
+// GeoJSON feed provides.
+package alerts
+
+import (
+	"context"
+	"errors"
+	"weather/server/dtos"
+	"weather/server/geo"
+	"weather/server/nws"
+	"weather/server/pool"
+)
+
+// Coverage describes whether an alert applies to a specific point.
+type Coverage string
+
+const (
+	CoverageInside  Coverage = "inside"
+	CoverageOutside Coverage = "outside"
+	CoverageUnknown Coverage = "unknown"
+)
+
+// zoneWorkers bounds the zone geometry fetches made at once for one alert;
+// the NWS limiter bounds them across the whole server.
+const zoneWorkers = 4
+
+type zoneResult struct {
+	inside bool
+	err    error
+}
+
+// ZoneGeometryFunc fetches the geometry of an affected zone by URL.
+type ZoneGeometryFunc func(ctx context.Context, zoneURL string) (*dtos.Geometry, error)
+
+// Matcher checks whether alerts cover a point.
+type Matcher struct {
+	zoneGeometry ZoneGeometryFunc
+}
+
+// NewMatcher creates a Matcher that falls back to the cached NWS zone
+// geometry when an alert carries no polygon of its own.
+func NewMatcher() *Matcher {
+	return &Matcher{zoneGeometry: nws.ZoneGeometry}
+}
+
+// Match reports whether the alert covers the point. Storm-based alerts are
+// checked against their own polygon; zone-based alerts (null geometry) are
+// checked against each affected zone's shape.
+func (m *Matcher) Match(ctx context.Context, f dtos.Feature, lat, lon float64) (Coverage, error) {
+	if f.Geometry != nil {
+		inside, err := containsPoint(f.Geometry, lat, lon)
+		if err != nil {
+			return CoverageUnknown, err
+		}
+		return coverage(inside), nil
+	}
+
+	if len(f.AffectedZones) == 0 {
+		return CoverageUnknown, nil
+	}
+
+	// Statewide alerts list dozens of zones, so their geometry is fetched
+	// concurrently, and the remaining fetches are abandoned as soon as one
+	// zone contains the point.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]zoneResult, len(f.AffectedZones))
+	inside := false
+	err := pool.Run(ctx, len(f.AffectedZones), zoneWorkers,
+		func(i int) {
+			g, err := m.zoneGeometry(ctx, f.AffectedZones[i])
+			if err != nil || g == nil {
+				results[i] = zoneResult{err: err}
+				return
+			}
+			in, err := containsPoint(g, lat, lon)
+			results[i] = zoneResult{inside: in, err: err}
+		},
+		func(i int) {
+			if results[i].inside && !inside {
+				inside = true
+				cancel()
+			}
+		})
+	if inside {
+		return CoverageInside, nil
+	}
+	if err != nil {
+		return CoverageUnknown, err
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	if len(errs) > 0 {
+		// Some zones could not be checked, so the point may still be covered.
+		return CoverageUnknown, errors.Join(errs...)
+	}
+	return CoverageOutside, nil
+}
+
+func containsPoint(g *dtos.Geometry, lat, lon float64) (bool, error) {
+	if g.Type == "GeometryCollection" {
+		for i := range g.Geometries {
+			inside, err := containsPoint(&g.Geometries[i], lat, lon)
+			if err != nil || inside {
+				return inside, err
+			}
+		}
+		return false, nil
+	}
+
+	shape, err := geo.ParseShape(g.Type, g.Coordinates)
+	if err != nil {
+		return false, err
+	}
+	return shape.Contains(lat, lon), nil
+}
+
+func coverage(inside bool) Coverage {
+	if inside {
+		return CoverageInside
+	}
+	return CoverageOutside
+}
@@ -0,0 +1,81 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weather/server/dtos"
+)
+
+// square returns a 1° square polygon with its south-west corner at lat, lon.
+func square(lat, lon float64) *dtos.Geometry {
+	coords := fmt.Sprintf("[[[%g,%g],[%g,%g],[%g,%g],[%g,%g],[%g,%g]]]",
+		lon, lat, lon+1, lat, lon+1, lat+1, lon, lat+1, lon, lat)
+	return &dtos.Geometry{Type: "Polygon", Coordinates: json.RawMessage(coords)}
+}
+
+func TestMatchZonesConcurrently(t *testing.T) {
+	// 30 zones in a row along latitude 30; the point is in zone 20.
+	var zones []string
+	for i := range 30 {
+		zones = append(zones, fmt.Sprintf("https://api.weather.gov/zones/forecast/TXZ%03d", i))
+	}
+	var inFlight, maxInFlight, calls atomic.Int32
+	var mu sync.Mutex
+	m := &Matcher{zoneGeometry: func(ctx context.Context, url string) (*dtos.Geometry, error) {
+		calls.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		mu.Lock()
+		if n > maxInFlight.Load() {
+			maxInFlight.Store(n)
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+
+		var i int
+		fmt.Sscanf(url[len(url)-3:], "%d", &i)
+		return square(30, -120+float64(i)), nil
+	}}
+
+	f := dtos.Feature{AlertProperties: dtos.AlertProperties{AffectedZones: zones}}
+	got, err := m.Match(context.Background(), f, 30.5, -99.5)
+	if err != nil || got != CoverageInside {
+		t.Fatalf("Match = %v, %v; want inside", got, err)
+	}
+	if maxInFlight.Load() < 2 {
+		t.Errorf("zone fetches ran one at a time")
+	}
+	if calls.Load() == int32(len(zones)) {
+		t.Errorf("all %d zones fetched; want the rest abandoned after the match", len(zones))
+	}
+}
+
+func TestMatchZonesOutsideAndErrors(t *testing.T) {
+	zoneErr := errors.New("zone unavailable")
+	tests := []struct {
+		name    string
+		geom    func(url string) (*dtos.Geometry, error)
+		want    Coverage
+		wantErr bool
+	}{
+		{"outside", func(string) (*dtos.Geometry, error) { return square(40, -100), nil }, CoverageOutside, false},
+		{"errors", func(string) (*dtos.Geometry, error) { return nil, zoneErr }, CoverageUnknown, true},
+		{"no geometry", func(string) (*dtos.Geometry, error) { return nil, nil }, CoverageOutside, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Matcher{zoneGeometry: func(_ context.Context, url string) (*dtos.Geometry, error) { return tt.geom(url) }}
+			f := dtos.Feature{AlertProperties: dtos.AlertProperties{AffectedZones: []string{"a", "b", "c"}}}
+			got, err := m.Match(context.Background(), f, 30.5, -99.5)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("Match = %v, %v; want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// SynodicMonth is the mean length of a lunation in days.
+const SynodicMonth = 29.530588853
+
+// MoonPhase describes the moon at an instant.
+type MoonPhase struct {
+	// Angle is the moon's elongation from the sun in degrees along the
+	// ecliptic: 0 is new, 90 first quarter, 180 full and 270 last quarter.
+	Angle float64
+	// Illumination is the illuminated fraction of the disc, 0 to 1.
+	Illumination float64
+	// Age is the approximate days since new moon.
+	Age  float64
+	Name string
+}
+
+// MoonTimes are the moonrise and moonset on one day. The moon rises or sets
+// at most once a day, and some days it does neither; missing events are the
+// zero time.
+type MoonTimes struct {
+	Rise time.Time
+	Set  time.Time
+	// AlwaysUp and AlwaysDown mark days the moon stays above or below the
+	// horizon.
+	AlwaysUp   bool
+	AlwaysDown bool
+}
+
+// Phase returns the moon phase at t.
+func Phase(t time.Time) MoonPhase {
+	jd := julianDay(t)
+	_, _, sunLon := solarPosition(jd)
+	moonLon, _, _ := moonPosition(jd)
+
+	angle := normalize(moonLon - sunLon)
+	return MoonPhase{
+		Angle:        angle,
+		Illumination: (1 - math.Cos(rad(angle))) / 2,
+		Age:          angle / 360 * SynodicMonth,
+		Name:         phaseName(angle),
+	}
+}
+
+// phaseName names the eighth of the lunation an elongation falls in.
+func phaseName(angle float64) string {
+	names := [...]string{
+		"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous",
+		"Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent",
+	}
+	return names[int(normalize(angle+22.5)/45)%8]
+}
+
+// moonStep is the interval moonrise and moonset are searched at.
+const moonStep = 10 * time.Minute
+
+// Moon returns the moonrise and moonset at a location on date's calendar
+// day, in date's location. The moon's altitude is sampled every ten minutes
+// from midnight to midnight and each horizon crossing interpolated.
+func Moon(date time.Time, lat, lon float64) MoonTimes {
+	loc := date.Location()
+	y, m, d := date.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	end := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+
+	var mt MoonTimes
+	prev := moonHeight(start, lat, lon)
+	up := prev > 0
+	sawUp, sawDown := up, !up
+	for t := start; t.Before(end); {
+		next := t.Add(moonStep)
+		if next.After(end) {
+			next = end
+		}
+		h := moonHeight(next, lat, lon)
+		if (prev <= 0) != (h <= 0) {
+			at := t.Add(time.Duration(float64(next.Sub(t)) * prev / (prev - h))).Round(time.Minute)
+			if h > 0 && mt.Rise.IsZero() {
+				mt.Rise = at
+			} else if h <= 0 && mt.Set.IsZero() {
+				mt.Set = at
+			}
+		}
+		if h > 0 {
+			sawUp = true
+		} else {
+			sawDown = true
+		}
+		prev, t = h, next
+	}
+	mt.AlwaysUp = !sawDown
+	mt.AlwaysDown = !sawUp
+	return mt
+}
+
+// moonHeight returns the moon's altitude at t relative to the altitude its
+// upper limb touches the horizon at, in degrees; it is positive while the
+// moon is up.
+func moonHeight(t time.Time, lat, lon float64) float64 {
+	jd := julianDay(t)
+	eclLon, eclLat, dist := moonPosition(jd)
+
+	obl := rad(obliquity((jd - 2451545) / 36525))
+	l, b := rad(eclLon), rad(eclLat)
+	ra := math.Atan2(math.Sin(l)*math.Cos(obl)-math.Tan(b)*math.Sin(obl), math.Cos(l))
+	dec := math.Asin(math.Sin(b)*math.Cos(obl) + math.Cos(b)*math.Sin(obl)*math.Sin(l))
+
+	gmst := normalize(280.46061837 + 360.98564736629*(jd-2451545))
+	ha := rad(gmst+lon) - ra
+	latR := rad(lat)
+	alt := deg(math.Asin(math.Sin(latR)*math.Sin(dec) + math.Cos(latR)*math.Cos(dec)*math.Cos(ha)))
+
+	// Horizon altitude allowing for parallax, refraction and semi-diameter.
+	parallax := deg(math.Asin(6378.14 / dist))
+	return alt - (0.7275*parallax - 0.5667)
+}
+
+// moonPosition returns the moon's geocentric ecliptic longitude and latitude
+// in degrees and its distance in km, from the largest terms of the lunar
+// theory in Meeus, Astronomical Algorithms ch. 47.
+func moonPosition(jd float64) (lon, lat, dist float64) {
+	t := (jd - 2451545) / 36525
+	lp := 218.3164477 + 481267.88123421*t // mean longitude
+	d := rad(297.8501921 + 445267.1114034*t)
+	m := rad(357.5291092 + 35999.0502909*t)
+	mp := rad(134.9633964 + 477198.8675055*t)
+	f := rad(93.2720950 + 483202.0175233*t)
+
+	lon = lp +
+		6.288774*math.Sin(mp) +
+		1.274027*math.Sin(2*d-mp) +
+		0.658314*math.Sin(2*d) +
+		0.213618*math.Sin(2*mp) -
+		0.185116*math.Sin(m) -
+		0.114332*math.Sin(2*f) +
+		0.058793*math.Sin(2*d-2*mp) +
+		0.057066*math.Sin(2*d-m-mp) +
+		0.053322*math.Sin(2*d+mp) +
+		0.045758*math.Sin(2*d-m)
+	lat = 5.128122*math.Sin(f) +
+		0.280602*math.Sin(mp+f) +
+		0.277693*math.Sin(mp-f) +
+		0.173237*math.Sin(2*d-f)
+	dist = 385000.56 -
+		20905.355*math.Cos(mp) -
+		3699.111*math.Cos(2*d-mp) -
+		2955.968*math.Cos(2*d) -
+		569.925*math.Cos(2*mp)
+	return normalize(lon), lat, dist
+}
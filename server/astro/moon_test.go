@@ -0,0 +1,57 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPhase(t *testing.T) {
+	tests := []struct {
+		at    string
+		name  string
+		angle float64
+	}{
+		// The full moon of the total lunar eclipse of 7 September 2025.
+		{"2025-09-07T18:09:00Z", "Full Moon", 180},
+		// The new moon of the partial solar eclipse of 21 September 2025.
+		{"2025-09-21T19:54:00Z", "New Moon", 0},
+		{"2025-09-29T23:54:00Z", "First Quarter", 90},
+		{"2025-09-14T10:33:00Z", "Last Quarter", 270},
+	}
+	for _, tt := range tests {
+		at, err := time.Parse(time.RFC3339, tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := Phase(at)
+		if p.Name != tt.name {
+			t.Errorf("Phase(%s) = %s, want %s", tt.at, p.Name, tt.name)
+		}
+		// Elongation changes about half a degree an hour.
+		if diff := math.Abs(math.Remainder(p.Angle-tt.angle, 360)); diff > 1 {
+			t.Errorf("Phase(%s) angle = %.2f, want %v", tt.at, p.Angle, tt.angle)
+		}
+		if want := (1 - math.Cos(rad(tt.angle))) / 2; math.Abs(p.Illumination-want) > 0.01 {
+			t.Errorf("Phase(%s) illumination = %.3f, want %.3f", tt.at, p.Illumination, want)
+		}
+	}
+}
+
+func TestMoonTimes(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A full moon rises around sunset and sets around sunrise.
+	mt := Moon(time.Date(2025, 9, 7, 0, 0, 0, 0, chicago), 30.2672, -97.7431)
+	if mt.AlwaysUp || mt.AlwaysDown {
+		t.Fatalf("AlwaysUp %v, AlwaysDown %v at Austin", mt.AlwaysUp, mt.AlwaysDown)
+	}
+	if mt.Rise.IsZero() || mt.Rise.Hour() < 18 || mt.Rise.Hour() > 20 {
+		t.Errorf("moonrise = %s, want the evening", mt.Rise.Format("15:04 MST"))
+	}
+	if mt.Set.IsZero() || mt.Set.Hour() < 6 || mt.Set.Hour() > 8 {
+		t.Errorf("moonset = %s, want the morning", mt.Set.Format("15:04 MST"))
+	}
+}
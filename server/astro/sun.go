@@ -0,0 +1,187 @@
+// Package astro computes sun and moon events locally: sunrise, sunset,
+// twilight, solar noon, moon phase and moonrise/moonset. The formulas are the
+// NOAA solar calculator equations and a low-precision lunar theory, accurate
+// to about a minute for the sun and a few minutes for the moon.
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Solar zenith angles, in degrees, that define each event. Sunrise and sunset
+// allow for refraction and the sun's semi-diameter.
+const (
+	zenithSunrise      = 90.833
+	zenithCivil        = 96
+	zenithNautical     = 102
+	zenithAstronomical = 108
+)
+
+// SunTimes are the solar events on one day. Events that do not happen that
+// day, such as sunset during polar day or civil dusk at midsummer far north,
+// are the zero time.
+type SunTimes struct {
+	Sunrise          time.Time
+	Sunset           time.Time
+	SolarNoon        time.Time
+	CivilDawn        time.Time
+	CivilDusk        time.Time
+	NauticalDawn     time.Time
+	NauticalDusk     time.Time
+	AstronomicalDawn time.Time
+	AstronomicalDusk time.Time
+	DayLength        time.Duration
+	// AlwaysUp and AlwaysDown mark polar day and polar night.
+	AlwaysUp   bool
+	AlwaysDown bool
+}
+
+// Sun returns the solar events at a location on date's calendar day, in
+// date's location.
+func Sun(date time.Time, lat, lon float64) SunTimes {
+	loc := date.Location()
+	y, m, d := date.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	var s SunTimes
+	noon := solarNoon(midnight, lon)
+	s.SolarNoon = noon.In(loc)
+
+	var up, down bool
+	s.Sunrise, s.Sunset, up, down = sunEvent(noon, lat, zenithSunrise)
+	s.CivilDawn, s.CivilDusk, _, _ = sunEvent(noon, lat, zenithCivil)
+	s.NauticalDawn, s.NauticalDusk, _, _ = sunEvent(noon, lat, zenithNautical)
+	s.AstronomicalDawn, s.AstronomicalDusk, _, _ = sunEvent(noon, lat, zenithAstronomical)
+	for _, t := range []*time.Time{
+		&s.Sunrise, &s.Sunset, &s.CivilDawn, &s.CivilDusk,
+		&s.NauticalDawn, &s.NauticalDusk, &s.AstronomicalDawn, &s.AstronomicalDusk,
+	} {
+		if !t.IsZero() {
+			*t = t.In(loc)
+		}
+	}
+
+	switch {
+	case up:
+		s.AlwaysUp = true
+		s.DayLength = 24 * time.Hour
+	case down:
+		s.AlwaysDown = true
+	default:
+		s.DayLength = s.Sunset.Sub(s.Sunrise)
+	}
+	return s
+}
+
+// solarNoon returns the time of solar noon on the UTC day starting at
+// midnight, refined once at the noon itself.
+func solarNoon(midnight time.Time, lon float64) time.Time {
+	t := midnight.Add(12*time.Hour - time.Duration(lon*4*float64(time.Minute)))
+	for range 2 {
+		_, eqTime, _ := solarPosition(julianDay(t))
+		t = midnight.Add(time.Duration((720 - 4*lon - eqTime) * float64(time.Minute)))
+	}
+	return t
+}
+
+// sunEvent returns the morning and evening times the sun crosses zenith
+// around noon. If it never does, up or down reports whether the sun stays
+// above or below it all day.
+func sunEvent(noon time.Time, lat, zenith float64) (rise, set time.Time, up, down bool) {
+	rise, up, down = crossing(noon, lat, zenith, -1)
+	if up || down {
+		return time.Time{}, time.Time{}, up, down
+	}
+	set, up, down = crossing(noon, lat, zenith, 1)
+	if up || down {
+		return time.Time{}, time.Time{}, up, down
+	}
+	return rise, set, false, false
+}
+
+// crossing finds one zenith crossing, before noon when sign is -1 and after
+// it when sign is 1, re-evaluating the sun's position at the estimate.
+func crossing(noon time.Time, lat, zenith, sign float64) (t time.Time, up, down bool) {
+	_, noonEq, _ := solarPosition(julianDay(noon))
+	t = noon
+	for range 3 {
+		decl, eqTime, _ := solarPosition(julianDay(t))
+		ha, ok, above := hourAngle(lat, decl, zenith)
+		if !ok {
+			return time.Time{}, above, !above
+		}
+		// The sun moves 4 minutes of time per degree of hour angle.
+		offset := sign*ha*4 + (noonEq - eqTime)
+		t = noon.Add(time.Duration(offset * float64(time.Minute)))
+	}
+	return t, false, false
+}
+
+// hourAngle returns the sun's hour angle in degrees at zenith. ok is false
+// when the sun does not reach zenith that day; above then reports whether it
+// stays above it.
+func hourAngle(lat, decl, zenith float64) (ha float64, ok, above bool) {
+	latR, declR := rad(lat), rad(decl)
+	cosHA := math.Cos(rad(zenith))/(math.Cos(latR)*math.Cos(declR)) - math.Tan(latR)*math.Tan(declR)
+	switch {
+	case cosHA > 1:
+		return 0, false, false
+	case cosHA < -1:
+		return 0, false, true
+	}
+	return deg(math.Acos(cosHA)), true, false
+}
+
+// solarPosition returns the sun's declination and apparent ecliptic
+// longitude in degrees, and the equation of time in minutes, at a Julian day.
+func solarPosition(jd float64) (decl, eqTime, longitude float64) {
+	t := (jd - 2451545) / 36525
+
+	meanLong := math.Mod(280.46646+t*(36000.76983+t*0.0003032), 360)
+	meanAnom := 357.52911 + t*(35999.05029-0.0001537*t)
+	ecc := 0.016708634 - t*(0.000042037+0.0000001267*t)
+
+	mr := rad(meanAnom)
+	center := math.Sin(mr)*(1.914602-t*(0.004817+0.000014*t)) +
+		math.Sin(2*mr)*(0.019993-0.000101*t) +
+		math.Sin(3*mr)*0.000289
+	trueLong := meanLong + center
+	omega := 125.04 - 1934.136*t
+	appLong := trueLong - 0.00569 - 0.00478*math.Sin(rad(omega))
+
+	obliq := obliquity(t) + 0.00256*math.Cos(rad(omega))
+	decl = deg(math.Asin(math.Sin(rad(obliq)) * math.Sin(rad(appLong))))
+
+	y := math.Pow(math.Tan(rad(obliq)/2), 2)
+	l0 := rad(meanLong)
+	eqTime = 4 * deg(y*math.Sin(2*l0)-
+		2*ecc*math.Sin(mr)+
+		4*ecc*y*math.Sin(mr)*math.Cos(2*l0)-
+		0.5*y*y*math.Sin(4*l0)-
+		1.25*ecc*ecc*math.Sin(2*mr))
+	return decl, eqTime, normalize(appLong)
+}
+
+// obliquity returns the mean obliquity of the ecliptic in degrees at t
+// Julian centuries from J2000.
+func obliquity(t float64) float64 {
+	return 23 + (26+(21.448-t*(46.815+t*(0.00059-t*0.001813)))/60)/60
+}
+
+// julianDay returns the Julian day number of t.
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixMilli())/86400000 + 2440587.5
+}
+
+func rad(d float64) float64 { return d * math.Pi / 180 }
+func deg(r float64) float64 { return r * 180 / math.Pi }
+
+// normalize wraps an angle in degrees into [0, 360).
+func normalize(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
@@ -0,0 +1,67 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+// within reports whether got is within tolerance of the wall-clock time
+// hh:mm on got's day.
+func within(got time.Time, hh, mm int, tolerance time.Duration) bool {
+	y, m, d := got.Date()
+	want := time.Date(y, m, d, hh, mm, 0, 0, got.Location())
+	diff := got.Sub(want)
+	return diff >= -tolerance && diff <= tolerance
+}
+
+func TestSunAustin(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOAA solar calculator, Austin TX on the 2026 June solstice.
+	s := Sun(time.Date(2026, 6, 21, 0, 0, 0, 0, chicago), 30.2672, -97.7431)
+	if !within(s.Sunrise, 6, 29, 2*time.Minute) {
+		t.Errorf("sunrise = %s, want about 06:29 CDT", s.Sunrise.Format("15:04 MST"))
+	}
+	if !within(s.Sunset, 20, 36, 2*time.Minute) {
+		t.Errorf("sunset = %s, want about 20:36 CDT", s.Sunset.Format("15:04 MST"))
+	}
+	if !within(s.SolarNoon, 13, 32, 2*time.Minute) {
+		t.Errorf("solar noon = %s, want about 13:32 CDT", s.SolarNoon.Format("15:04 MST"))
+	}
+	if s.AlwaysUp || s.AlwaysDown {
+		t.Errorf("AlwaysUp %v, AlwaysDown %v at Austin", s.AlwaysUp, s.AlwaysDown)
+	}
+	if d := s.DayLength; d < 14*time.Hour || d > 14*time.Hour+10*time.Minute {
+		t.Errorf("day length = %s, want about 14h06m", d)
+	}
+	// Twilight events bracket the sunrise in order.
+	if !(s.AstronomicalDawn.Before(s.NauticalDawn) && s.NauticalDawn.Before(s.CivilDawn) && s.CivilDawn.Before(s.Sunrise)) {
+		t.Errorf("dawns out of order: %s %s %s %s", s.AstronomicalDawn, s.NauticalDawn, s.CivilDawn, s.Sunrise)
+	}
+}
+
+func TestSunPolar(t *testing.T) {
+	// Tromsø, Norway, north of the Arctic Circle.
+	const lat, lon = 69.6492, 18.9553
+	day := Sun(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), lat, lon)
+	if !day.AlwaysUp || day.AlwaysDown || day.DayLength != 24*time.Hour {
+		t.Errorf("midsummer: AlwaysUp %v, AlwaysDown %v, day length %s", day.AlwaysUp, day.AlwaysDown, day.DayLength)
+	}
+	if !day.Sunrise.IsZero() || !day.Sunset.IsZero() {
+		t.Errorf("midsummer: sunrise %s, sunset %s, want none", day.Sunrise, day.Sunset)
+	}
+	if day.SolarNoon.IsZero() {
+		t.Error("midsummer: no solar noon")
+	}
+
+	night := Sun(time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC), lat, lon)
+	if !night.AlwaysDown || night.AlwaysUp || night.DayLength != 0 {
+		t.Errorf("midwinter: AlwaysUp %v, AlwaysDown %v, day length %s", night.AlwaysUp, night.AlwaysDown, night.DayLength)
+	}
+	// The sun stays below the horizon but still brings civil twilight.
+	if night.CivilDawn.IsZero() || night.CivilDusk.IsZero() {
+		t.Errorf("midwinter: civil dawn %s, dusk %s, want both", night.CivilDawn, night.CivilDusk)
+	}
+}
@@ -0,0 +1,64 @@
+package aviation
+
+// Flight categories, from best to worst.
+const (
+	VFR  = "VFR"
+	MVFR = "MVFR"
+	IFR  = "IFR"
+	LIFR = "LIFR"
+)
+
+// FlightCategory derives the FAA flight category from the ceiling (feet AGL,
+// nil for no ceiling) and visibility. The worse of the two decides. It
+// returns "" when neither value is known; skyKnown reports whether a missing
+// ceiling means the sky was reported without one.
+func FlightCategory(ceiling *int, vis *Visibility, skyKnown bool) string {
+	if ceiling == nil && !skyKnown && vis == nil {
+		return ""
+	}
+
+	cat := VFR
+	if ceiling != nil {
+		cat = worse(cat, ceilingCategory(*ceiling))
+	}
+	if vis != nil {
+		cat = worse(cat, visibilityCategory(vis))
+	}
+	return cat
+}
+
+func ceilingCategory(feet int) string {
+	switch {
+	case feet < 500:
+		return LIFR
+	case feet < 1000:
+		return IFR
+	case feet <= 3000:
+		return MVFR
+	default:
+		return VFR
+	}
+}
+
+func visibilityCategory(v *Visibility) string {
+	miles := v.StatuteMiles
+	switch {
+	case miles < 1 || (v.LessThan && miles <= 1):
+		return LIFR
+	case miles < 3:
+		return IFR
+	case miles <= 5 && !v.GreaterThan:
+		return MVFR
+	default:
+		return VFR
+	}
+}
+
+var categoryRank = map[string]int{VFR: 0, MVFR: 1, IFR: 2, LIFR: 3}
+
+func worse(a, b string) string {
+	if categoryRank[b] > categoryRank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,247 @@
+// Package aviation decodes METAR observations and TAF forecasts into
+// structured wind, visibility, weather and cloud fields, and derives the FAA
+// flight category.
+package aviation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"weather/server/weathercalc"
+)
+
+var (
+	windPattern       = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS|KMH)$`)
+	windVarPattern    = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visSMPattern      = regexp.MustCompile(`^([PM])?(\d+ \d/\d{1,2}|\d/\d{1,2}|\d+)SM$`)
+	visMetersPattern  = regexp.MustCompile(`^(\d{4})(NDV)?$`)
+	cloudPattern      = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	weatherPattern    = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*)$`)
+	fractionPattern   = regexp.MustCompile(`^\d/\d{1,2}SM$`)
+	wholeMilesPattern = regexp.MustCompile(`^\d$`)
+)
+
+// Wind is a surface wind report. Speeds are in Unit (usually knots).
+type Wind struct {
+	Direction    *int   `json:"direction,omitempty"`
+	Variable     bool   `json:"variable,omitempty"`
+	Speed        int    `json:"speed"`
+	Gust         *int   `json:"gust,omitempty"`
+	Unit         string `json:"unit"`
+	VariableFrom *int   `json:"variableFrom,omitempty"`
+	VariableTo   *int   `json:"variableTo,omitempty"`
+}
+
+// Visibility is the prevailing visibility in statute miles. GreaterThan and
+// LessThan mark reports such as "P6SM" and "M1/4SM".
+type Visibility struct {
+	StatuteMiles float64 `json:"statuteMiles"`
+	GreaterThan  bool    `json:"greaterThan,omitempty"`
+	LessThan     bool    `json:"lessThan,omitempty"`
+}
+
+// Cloud is a sky condition layer. Cover is FEW, SCT, BKN, OVC or VV
+// (vertical visibility into an obscured sky); Height is in feet AGL.
+type Cloud struct {
+	Cover  string `json:"cover"`
+	Height *int   `json:"height,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// Weather is a present or forecast weather group, e.g. "-TSRA".
+type Weather struct {
+	Raw         string `json:"raw"`
+	Description string `json:"description"`
+}
+
+// Conditions are the fields shared by METAR reports and TAF change groups.
+type Conditions struct {
+	Wind           *Wind       `json:"wind,omitempty"`
+	Visibility     *Visibility `json:"visibility,omitempty"`
+	Weather        []Weather   `json:"weather,omitempty"`
+	Clouds         []Cloud     `json:"clouds,omitempty"`
+	SkyClear       bool        `json:"skyClear,omitempty"`
+	CAVOK          bool        `json:"cavok,omitempty"`
+	Ceiling        *int        `json:"ceiling,omitempty"`
+	FlightCategory string      `json:"flightCategory,omitempty"`
+}
+
+// parseToken decodes tok into c, reporting whether it was recognised.
+func (c *Conditions) parseToken(tok string) bool {
+	switch tok {
+	case "CAVOK":
+		c.CAVOK = true
+		c.SkyClear = true
+		c.Visibility = &Visibility{StatuteMiles: 6, GreaterThan: true}
+		return true
+	case "SKC", "CLR", "NSC", "NCD":
+		c.SkyClear = true
+		return true
+	case "NSW":
+		c.Weather = append(c.Weather, Weather{Raw: tok, Description: "no significant weather"})
+		return true
+	}
+
+	if m := windPattern.FindStringSubmatch(tok); m != nil {
+		w := &Wind{Unit: m[4]}
+		if m[1] == "VRB" {
+			w.Variable = true
+		} else {
+			w.Direction = atoi(m[1])
+		}
+		w.Speed = *atoi(m[2])
+		if m[3] != "" {
+			w.Gust = atoi(m[3])
+		}
+		c.Wind = w
+		return true
+	}
+	if m := windVarPattern.FindStringSubmatch(tok); m != nil && c.Wind != nil {
+		c.Wind.VariableFrom = atoi(m[1])
+		c.Wind.VariableTo = atoi(m[2])
+		return true
+	}
+	if m := visSMPattern.FindStringSubmatch(tok); m != nil {
+		miles, err := parseMiles(m[2])
+		if err != nil {
+			return false
+		}
+		c.Visibility = &Visibility{StatuteMiles: miles, GreaterThan: m[1] == "P", LessThan: m[1] == "M"}
+		return true
+	}
+	if m := visMetersPattern.FindStringSubmatch(tok); m != nil && c.Visibility == nil {
+		meters := *atoi(m[1])
+		// 9999 means 10 km or more.
+		c.Visibility = &Visibility{StatuteMiles: weathercalc.MetersToMiles(float64(meters)), GreaterThan: meters == 9999}
+		return true
+	}
+	if m := cloudPattern.FindStringSubmatch(tok); m != nil {
+		layer := Cloud{Cover: m[1], Type: m[3]}
+		if m[2] != "///" {
+			h := *atoi(m[2]) * 100
+			layer.Height = &h
+		}
+		c.Clouds = append(c.Clouds, layer)
+		return true
+	}
+	if desc, ok := describeWeather(tok); ok {
+		c.Weather = append(c.Weather, Weather{Raw: tok, Description: desc})
+		return true
+	}
+	return false
+}
+
+// finish derives the ceiling and flight category once all tokens are parsed.
+func (c *Conditions) finish() {
+	c.Ceiling = nil
+	for _, l := range c.Clouds {
+		if (l.Cover == "BKN" || l.Cover == "OVC" || l.Cover == "VV") && l.Height != nil {
+			if c.Ceiling == nil || *l.Height < *c.Ceiling {
+				h := *l.Height
+				c.Ceiling = &h
+			}
+		}
+	}
+	c.FlightCategory = FlightCategory(c.Ceiling, c.Visibility, c.SkyClear || len(c.Clouds) > 0)
+}
+
+// tokenize splits a report into groups, joining split visibilities such as
+// "1 1/2SM" into a single token.
+func tokenize(raw string) []string {
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(raw), "="))
+	out := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		if i+1 < len(fields) && wholeMilesPattern.MatchString(fields[i]) && fractionPattern.MatchString(fields[i+1]) {
+			out = append(out, fields[i]+" "+fields[i+1])
+			i++
+			continue
+		}
+		out = append(out, fields[i])
+	}
+	return out
+}
+
+func parseMiles(s string) (float64, error) {
+	whole, frac, hasFrac := strings.Cut(s, " ")
+	if !hasFrac {
+		if !strings.Contains(s, "/") {
+			return strconv.ParseFloat(s, 64)
+		}
+		whole, frac = "0", s
+	}
+	w, err := strconv.ParseFloat(whole, 64)
+	if err != nil {
+		return 0, err
+	}
+	num, den, _ := strings.Cut(frac, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, err
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0, fmt.Errorf("aviation: invalid visibility %q", s)
+	}
+	return w + n/d, nil
+}
+
+func atoi(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+var (
+	weatherIntensity  = map[string]string{"-": "light", "+": "heavy", "VC": "in the vicinity"}
+	weatherDescriptor = map[string]string{
+		"MI": "shallow", "PR": "partial", "BC": "patches of", "DR": "low drifting",
+		"BL": "blowing", "SH": "showers", "TS": "thunderstorm", "FZ": "freezing",
+	}
+	weatherPhenomena = map[string]string{
+		"DZ": "drizzle", "RA": "rain", "SN": "snow", "SG": "snow grains", "IC": "ice crystals",
+		"PL": "ice pellets", "GR": "hail", "GS": "small hail", "UP": "unknown precipitation",
+		"BR": "mist", "FG": "fog", "FU": "smoke", "VA": "volcanic ash", "DU": "dust",
+		"SA": "sand", "HZ": "haze", "PY": "spray", "PO": "dust whirls", "SQ": "squalls",
+		"FC": "funnel cloud", "SS": "sandstorm", "DS": "duststorm",
+	}
+)
+
+// describeWeather turns a weather group into words, e.g. "-TSRA" becomes
+// "light thunderstorm with rain".
+func describeWeather(tok string) (string, bool) {
+	m := weatherPattern.FindStringSubmatch(tok)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return "", false
+	}
+
+	var phenomena []string
+	for i := 0; i+2 <= len(m[3]); i += 2 {
+		phenomena = append(phenomena, weatherPhenomena[m[3][i:i+2]])
+	}
+	what := strings.Join(phenomena, " and ")
+
+	var words []string
+	if m[1] == "-" || m[1] == "+" {
+		words = append(words, weatherIntensity[m[1]])
+	}
+	switch {
+	case m[2] == "SH" && what != "":
+		words = append(words, what, "showers")
+	case m[2] == "TS" && what != "":
+		words = append(words, "thunderstorm with", what)
+	case m[2] != "":
+		words = append(words, weatherDescriptor[m[2]])
+		if what != "" {
+			words = append(words, what)
+		}
+	default:
+		words = append(words, what)
+	}
+	if m[1] == "VC" {
+		words = append(words, weatherIntensity["VC"])
+	}
+	return strings.Join(words, " "), true
+}
@@ -0,0 +1,158 @@
+package aviation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"weather/server/weathercalc"
+)
+
+var (
+	stationPattern   = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	issuedPattern    = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	tempPattern      = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterPattern = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	rvrPattern       = regexp.MustCompile(`^R\d{2}[LRC]?/`)
+)
+
+// METAR is a decoded surface observation. Temperatures are in °C and the
+// altimeter setting in inches of mercury.
+type METAR struct {
+	Raw         string    `json:"raw"`
+	Type        string    `json:"type"`
+	Station     string    `json:"station"`
+	Observed    time.Time `json:"observed"`
+	Auto        bool      `json:"auto,omitempty"`
+	Corrected   bool      `json:"corrected,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	DewPoint    *float64  `json:"dewPoint,omitempty"`
+	Altimeter   *float64  `json:"altimeter,omitempty"`
+	Remarks     string    `json:"remarks,omitempty"`
+	Unparsed    []string  `json:"unparsed,omitempty"`
+	Conditions
+}
+
+// DecodeMETAR decodes a raw METAR or SPECI report. The report only carries
+// the day of month, so ref (typically the current time) supplies the month
+// and year.
+func DecodeMETAR(raw string, ref time.Time) (*METAR, error) {
+	toks := tokenize(raw)
+	m := &METAR{Raw: strings.Join(strings.Fields(raw), " "), Type: "METAR"}
+
+	if len(toks) > 0 && (toks[0] == "METAR" || toks[0] == "SPECI") {
+		m.Type = toks[0]
+		toks = toks[1:]
+	}
+	if len(toks) < 2 || !stationPattern.MatchString(toks[0]) {
+		return nil, fmt.Errorf("aviation: invalid METAR %q: missing station", raw)
+	}
+	m.Station = toks[0]
+
+	t, err := parseIssued(toks[1], ref)
+	if err != nil {
+		return nil, fmt.Errorf("aviation: invalid METAR %q: %w", raw, err)
+	}
+	m.Observed = t
+
+body:
+	for i, tok := range toks[2:] {
+		switch tok {
+		case "RMK":
+			m.Remarks = strings.Join(toks[2+i+1:], " ")
+			break body
+		case "NOSIG", "BECMG", "TEMPO":
+			// A trend forecast follows; it is not part of the observation.
+			break body
+		case "AUTO":
+			m.Auto = true
+		case "COR":
+			m.Corrected = true
+		default:
+			if !m.parseToken(tok) {
+				m.Unparsed = append(m.Unparsed, tok)
+			}
+		}
+	}
+	m.finish()
+	return m, nil
+}
+
+func (m *METAR) parseToken(tok string) bool {
+	if rvrPattern.MatchString(tok) {
+		// Runway visual range is not decoded.
+		return true
+	}
+	if g := tempPattern.FindStringSubmatch(tok); g != nil {
+		m.Temperature = parseTemp(g[1])
+		m.DewPoint = parseTemp(g[2])
+		return true
+	}
+	if g := altimeterPattern.FindStringSubmatch(tok); g != nil {
+		v, _ := strconv.ParseFloat(g[2], 64)
+		if g[1] == "A" {
+			v /= 100
+		} else {
+			v = weathercalc.HPaToInHg(v)
+		}
+		m.Altimeter = &v
+		return true
+	}
+	return m.Conditions.parseToken(tok)
+}
+
+// parseTemp parses a METAR temperature such as "21" or "M05".
+func parseTemp(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	neg := strings.HasPrefix(s, "M")
+	v, err := strconv.ParseFloat(strings.TrimPrefix(s, "M"), 64)
+	if err != nil {
+		return nil
+	}
+	if neg {
+		v = -v
+	}
+	return &v
+}
+
+// parseIssued parses a "DDHHMMZ" time group.
+func parseIssued(s string, ref time.Time) (time.Time, error) {
+	g := issuedPattern.FindStringSubmatch(s)
+	if g == nil {
+		return time.Time{}, fmt.Errorf("invalid time group %q", s)
+	}
+	day, _ := strconv.Atoi(g[1])
+	hour, _ := strconv.Atoi(g[2])
+	minute, _ := strconv.Atoi(g[3])
+	return resolveDay(day, hour, minute, ref)
+}
+
+// resolveDay places a day-of-month and time in the month, of the one before,
+// at and after ref, that yields the time closest to ref. Hour 24 means
+// midnight at the end of the day.
+func resolveDay(day, hour, minute int, ref time.Time) (time.Time, error) {
+	if day < 1 || day > 31 || hour > 24 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid day/time %02d%02d%02d", day, hour, minute)
+	}
+	ref = ref.UTC()
+
+	var best time.Time
+	for _, offset := range []int{-1, 0, 1} {
+		first := time.Date(ref.Year(), ref.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		// Skip months that do not have this day.
+		if first.AddDate(0, 0, day-1).Month() != first.Month() {
+			continue
+		}
+		t := first.AddDate(0, 0, day-1).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		if best.IsZero() || t.Sub(ref).Abs() < best.Sub(ref).Abs() {
+			best = t
+		}
+	}
+	if best.IsZero() {
+		return time.Time{}, fmt.Errorf("invalid day %02d", day)
+	}
+	return best, nil
+}
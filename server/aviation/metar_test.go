@@ -0,0 +1,164 @@
+package aviation
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+var ref = time.Date(2025, 8, 5, 20, 0, 0, 0, time.UTC)
+
+func intPtr(n int) *int { return &n }
+
+func TestDecodeMETAR(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		category string
+		wind     Wind
+		miles    float64
+		ceiling  *int
+		weather  string
+	}{
+		{
+			name:     "VFR",
+			raw:      "KAUS 051853Z 18012KT 10SM FEW045 SCT250 34/21 A2998 RMK AO2",
+			category: VFR,
+			wind:     Wind{Direction: intPtr(180), Speed: 12, Unit: "KT"},
+			miles:    10,
+		},
+		{
+			name:     "MVFR",
+			raw:      "KAUS 051853Z 20008KT 4SM HZ BKN025 30/22 A2995",
+			category: MVFR,
+			wind:     Wind{Direction: intPtr(200), Speed: 8, Unit: "KT"},
+			miles:    4,
+			ceiling:  intPtr(2500),
+			weather:  "haze",
+		},
+		{
+			name:     "IFR gusting",
+			raw:      "KSFO 051856Z 27015G25KT 1 1/2SM BR OVC008 16/14 A2990",
+			category: IFR,
+			wind:     Wind{Direction: intPtr(270), Speed: 15, Gust: intPtr(25), Unit: "KT"},
+			miles:    1.5,
+			ceiling:  intPtr(800),
+			weather:  "mist",
+		},
+		{
+			name:     "LIFR vertical visibility",
+			raw:      "KSFO 051856Z VRB03KT 1/4SM FG VV002 13/13 A2992",
+			category: LIFR,
+			wind:     Wind{Variable: true, Speed: 3, Unit: "KT"},
+			miles:    0.25,
+			ceiling:  intPtr(200),
+			weather:  "fog",
+		},
+		{
+			name:     "variable direction",
+			raw:      "KDEN 051853Z 24015G28KT 210V270 10SM -TSRA BKN080CB 28/12 A3010",
+			category: VFR,
+			wind:     Wind{Direction: intPtr(240), Speed: 15, Gust: intPtr(28), Unit: "KT", VariableFrom: intPtr(210), VariableTo: intPtr(270)},
+			miles:    10,
+			ceiling:  intPtr(8000),
+			weather:  "light thunderstorm with rain",
+		},
+		{
+			name:     "ceiling at 500 feet is IFR",
+			raw:      "KAUS 051853Z 00000KT 10SM OVC005 20/19 A3000",
+			category: IFR,
+			wind:     Wind{Direction: intPtr(0), Speed: 0, Unit: "KT"},
+			miles:    10,
+			ceiling:  intPtr(500),
+		},
+		{
+			name:     "CAVOK",
+			raw:      "EGLL 051850Z 24010KT CAVOK 22/12 Q1015",
+			category: VFR,
+			wind:     Wind{Direction: intPtr(240), Speed: 10, Unit: "KT"},
+			miles:    6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := DecodeMETAR(tt.raw, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.FlightCategory != tt.category {
+				t.Errorf("FlightCategory = %s, want %s", m.FlightCategory, tt.category)
+			}
+			if !equalWind(m.Wind, tt.wind) {
+				t.Errorf("Wind = %s, want %s", describeWind(m.Wind), describeWind(&tt.wind))
+			}
+			if m.Visibility == nil || m.Visibility.StatuteMiles != tt.miles {
+				t.Errorf("Visibility = %+v, want %g miles", m.Visibility, tt.miles)
+			}
+			if !equalInt(m.Ceiling, tt.ceiling) {
+				t.Errorf("Ceiling = %v, want %v", deref(m.Ceiling), deref(tt.ceiling))
+			}
+			var weather string
+			if len(m.Weather) > 0 {
+				weather = m.Weather[0].Description
+			}
+			if weather != tt.weather {
+				t.Errorf("Weather = %q, want %q", weather, tt.weather)
+			}
+			if len(m.Unparsed) > 0 {
+				t.Errorf("Unparsed = %v", m.Unparsed)
+			}
+		})
+	}
+}
+
+func TestDecodeMETARFields(t *testing.T) {
+	m, err := DecodeMETAR("METAR KAUS 051853Z AUTO 18012KT 10SM CLR M02/M05 A2998 RMK AO2 SLP151", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Station != "KAUS" || !m.Auto || !m.Observed.Equal(time.Date(2025, 8, 5, 18, 53, 0, 0, time.UTC)) {
+		t.Errorf("header = %s auto=%v %s", m.Station, m.Auto, m.Observed)
+	}
+	if m.Temperature == nil || *m.Temperature != -2 || m.DewPoint == nil || *m.DewPoint != -5 {
+		t.Errorf("temperature = %v/%v, want -2/-5", deref(m.Temperature), deref(m.DewPoint))
+	}
+	if m.Altimeter == nil || math.Abs(*m.Altimeter-29.98) > 1e-9 {
+		t.Errorf("Altimeter = %v, want 29.98", deref(m.Altimeter))
+	}
+	if m.Remarks != "AO2 SLP151" || !m.SkyClear {
+		t.Errorf("remarks = %q, sky clear %v", m.Remarks, m.SkyClear)
+	}
+
+	for _, raw := range []string{"", "KAUS", "kaus 051853Z 18012KT", "KAUS 05185Z 18012KT"} {
+		if _, err := DecodeMETAR(raw, ref); err == nil {
+			t.Errorf("DecodeMETAR(%q) succeeded", raw)
+		}
+	}
+}
+
+func equalWind(got *Wind, want Wind) bool {
+	return got != nil && got.Variable == want.Variable && got.Speed == want.Speed && got.Unit == want.Unit &&
+		equalInt(got.Direction, want.Direction) && equalInt(got.Gust, want.Gust) &&
+		equalInt(got.VariableFrom, want.VariableFrom) && equalInt(got.VariableTo, want.VariableTo)
+}
+
+func describeWind(w *Wind) string {
+	if w == nil {
+		return "none"
+	}
+	return fmt.Sprintf("dir=%v vrb=%v speed=%d gust=%v %s from=%v to=%v",
+		deref(w.Direction), w.Variable, w.Speed, deref(w.Gust), w.Unit, deref(w.VariableFrom), deref(w.VariableTo))
+}
+
+func equalInt(a, b *int) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+// deref returns *p, or nil so missing values print as <nil>.
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
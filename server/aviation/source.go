@@ -0,0 +1,76 @@
+package aviation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"weather/server/logger"
+)
+
+// DefaultTAFURL is the Aviation Weather Center's TAF data API.
+const DefaultTAFURL = "https://aviationweather.gov/api/data/taf"
+
+// ErrNoTAF is returned when the source has no current TAF for a station.
+var ErrNoTAF = errors.New("aviation: no TAF available")
+
+// TAFSource fetches raw TAFs from an API compatible with the Aviation Weather
+// Center's /api/data/taf endpoint (ids and format=raw query parameters).
+type TAFSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewTAFSource creates a TAF source for the API at baseURL, or the Aviation
+// Weather Center when baseURL is empty.
+func NewTAFSource(baseURL string) *TAFSource {
+	if baseURL == "" {
+		baseURL = DefaultTAFURL
+	}
+	return &TAFSource{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Fetch returns the latest raw TAF for an ICAO station identifier.
+func (s *TAFSource) Fetch(ctx context.Context, station string) (string, error) {
+	u := s.baseURL + "?" + url.Values{"ids": {station}, "format": {"raw"}}.Encode()
+
+	log := logger.FromContext(ctx)
+	log.Debug("TAF request", "url", u)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/plain")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Error("TAF request failed", "url", u, "error", err)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return "", fmt.Errorf("%w for %s", ErrNoTAF, station)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Error("TAF source returned an error status", "url", u, "status", resp.StatusCode)
+		return "", fmt.Errorf("aviation: unexpected status %s for %s", resp.Status, u)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	raw := strings.TrimSpace(string(body))
+	if raw == "" {
+		return "", fmt.Errorf("%w for %s", ErrNoTAF, station)
+	}
+	return raw, nil
+}
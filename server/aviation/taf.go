@@ -0,0 +1,196 @@
+package aviation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change group types. GroupBase is the initial forecast before any change group.
+const (
+	GroupBase  = "BASE"
+	GroupFrom  = "FM"
+	GroupTempo = "TEMPO"
+	GroupBecmg = "BECMG"
+	GroupProb  = "PROB"
+)
+
+var (
+	periodPattern = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	fromPattern   = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	probPattern   = regexp.MustCompile(`^PROB(\d{2})$`)
+	shearPattern  = regexp.MustCompile(`^WS\d{3}/\d{5}KT$`)
+)
+
+// TAF is a decoded terminal aerodrome forecast.
+type TAF struct {
+	Raw       string        `json:"raw"`
+	Station   string        `json:"station"`
+	Issued    time.Time     `json:"issued"`
+	Amended   bool          `json:"amended,omitempty"`
+	Corrected bool          `json:"corrected,omitempty"`
+	ValidFrom time.Time     `json:"validFrom"`
+	ValidTo   time.Time     `json:"validTo"`
+	Groups    []ChangeGroup `json:"groups"`
+}
+
+// ChangeGroup is one part of a TAF: the base forecast or an FM, TEMPO,
+// BECMG or PROB group. Conditions only hold what the group forecasts;
+// TEMPO, BECMG and PROB groups modify the prevailing forecast.
+type ChangeGroup struct {
+	Type        string    `json:"type"`
+	Probability int       `json:"probability,omitempty"`
+	Tempo       bool      `json:"tempo,omitempty"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	WindShear   string    `json:"windShear,omitempty"`
+	Unparsed    []string  `json:"unparsed,omitempty"`
+	Conditions
+}
+
+// DecodeTAF decodes a raw TAF. As with DecodeMETAR, ref supplies the month
+// and year the report's day-of-month times fall in.
+func DecodeTAF(raw string, ref time.Time) (*TAF, error) {
+	toks := tokenize(raw)
+	t := &TAF{Raw: strings.Join(strings.Fields(raw), " ")}
+
+	if len(toks) > 0 && toks[0] == "TAF" {
+		toks = toks[1:]
+	}
+	for len(toks) > 0 && (toks[0] == "AMD" || toks[0] == "COR") {
+		t.Amended = t.Amended || toks[0] == "AMD"
+		t.Corrected = t.Corrected || toks[0] == "COR"
+		toks = toks[1:]
+	}
+	if len(toks) < 3 || !stationPattern.MatchString(toks[0]) {
+		return nil, fmt.Errorf("aviation: invalid TAF %q: missing station", raw)
+	}
+	t.Station = toks[0]
+
+	issued, err := parseIssued(toks[1], ref)
+	if err != nil {
+		return nil, fmt.Errorf("aviation: invalid TAF %q: %w", raw, err)
+	}
+	t.Issued = issued
+
+	from, to, err := parsePeriod(toks[2], issued)
+	if err != nil {
+		return nil, fmt.Errorf("aviation: invalid TAF %q: %w", raw, err)
+	}
+	t.ValidFrom, t.ValidTo = from, to
+
+	group := &ChangeGroup{Type: GroupBase, From: from, To: to}
+	toks = toks[3:]
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		if tok == "RMK" {
+			break
+		}
+
+		next, consumed, err := t.startGroup(toks[i:], issued)
+		if err != nil {
+			return nil, fmt.Errorf("aviation: invalid TAF %q: %w", raw, err)
+		}
+		if next != nil {
+			t.addGroup(group)
+			group = next
+			i += consumed - 1
+			continue
+		}
+
+		switch {
+		case shearPattern.MatchString(tok):
+			group.WindShear = tok
+		case !group.parseToken(tok):
+			group.Unparsed = append(group.Unparsed, tok)
+		}
+	}
+	t.addGroup(group)
+
+	// FM groups and the base forecast last until the next FM group.
+	var prev *ChangeGroup
+	for i := range t.Groups {
+		g := &t.Groups[i]
+		if g.Type != GroupBase && g.Type != GroupFrom {
+			continue
+		}
+		if prev != nil {
+			prev.To = g.From
+		}
+		prev = g
+	}
+	return t, nil
+}
+
+// startGroup checks whether toks begins a new change group. It returns the
+// group and the number of tokens its header used, or nil if it does not.
+func (t *TAF) startGroup(toks []string, issued time.Time) (*ChangeGroup, int, error) {
+	tok := toks[0]
+	if m := fromPattern.FindStringSubmatch(tok); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		hour, _ := strconv.Atoi(m[2])
+		minute, _ := strconv.Atoi(m[3])
+		from, err := resolveDay(day, hour, minute, issued)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &ChangeGroup{Type: GroupFrom, From: from, To: t.ValidTo}, 1, nil
+	}
+
+	g := &ChangeGroup{}
+	n := 1
+	switch {
+	case tok == GroupTempo:
+		g.Type, g.Tempo = GroupTempo, true
+	case tok == GroupBecmg:
+		g.Type = GroupBecmg
+	case probPattern.MatchString(tok):
+		g.Type = GroupProb
+		g.Probability, _ = strconv.Atoi(tok[4:])
+		if len(toks) > n && toks[n] == GroupTempo {
+			g.Tempo = true
+			n++
+		}
+	default:
+		return nil, 0, nil
+	}
+
+	if len(toks) <= n {
+		return nil, 0, fmt.Errorf("%s group without a period", tok)
+	}
+	from, to, err := parsePeriod(toks[n], issued)
+	if err != nil {
+		return nil, 0, err
+	}
+	g.From, g.To = from, to
+	return g, n + 1, nil
+}
+
+func (t *TAF) addGroup(g *ChangeGroup) {
+	g.finish()
+	t.Groups = append(t.Groups, *g)
+}
+
+// parsePeriod parses a "DDHH/DDHH" validity period.
+func parsePeriod(s string, ref time.Time) (time.Time, time.Time, error) {
+	m := periodPattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q", s)
+	}
+	d1, _ := strconv.Atoi(m[1])
+	h1, _ := strconv.Atoi(m[2])
+	d2, _ := strconv.Atoi(m[3])
+	h2, _ := strconv.Atoi(m[4])
+
+	from, err := resolveDay(d1, h1, 0, ref)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := resolveDay(d2, h2, 0, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,88 @@
+package aviation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeTAF(t *testing.T) {
+	raw := `TAF AMD KAUS 051720Z 0518/0624 18010KT P6SM SCT040 BKN250
+  PROB30 0520/0524 VRB20G35KT 2SM +TSRA BKN020CB
+  FM060200 16006KT P6SM SKC
+  TEMPO 0608/0612 3SM BR BKN008
+  BECMG 0614/0616 20012G20KT P6SM FEW050
+  PROB40 TEMPO 0618/0622 1/2SM FG OVC003=`
+
+	taf, err := DecodeTAF(raw, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC) }
+	if taf.Station != "KAUS" || !taf.Amended || !taf.Issued.Equal(time.Date(2025, 8, 5, 17, 20, 0, 0, time.UTC)) {
+		t.Errorf("header = %s amended=%v issued %s", taf.Station, taf.Amended, taf.Issued)
+	}
+	if !taf.ValidFrom.Equal(at(5, 18)) || !taf.ValidTo.Equal(at(7, 0)) {
+		t.Errorf("valid %s to %s", taf.ValidFrom, taf.ValidTo)
+	}
+
+	want := []struct {
+		typ         string
+		probability int
+		tempo       bool
+		from, to    time.Time
+		category    string
+	}{
+		// The base forecast runs until the first FM group.
+		{GroupBase, 0, false, at(5, 18), at(6, 2), VFR},
+		{GroupProb, 30, false, at(5, 20), at(6, 0), IFR},
+		{GroupFrom, 0, false, at(6, 2), at(7, 0), VFR},
+		{GroupTempo, 0, true, at(6, 8), at(6, 12), IFR},
+		{GroupBecmg, 0, false, at(6, 14), at(6, 16), VFR},
+		{GroupProb, 40, true, at(6, 18), at(6, 22), LIFR},
+	}
+	if len(taf.Groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(taf.Groups), len(want))
+	}
+	for i, w := range want {
+		g := taf.Groups[i]
+		if g.Type != w.typ || g.Probability != w.probability || g.Tempo != w.tempo {
+			t.Errorf("group %d = %s prob %d tempo %v, want %s prob %d tempo %v", i, g.Type, g.Probability, g.Tempo, w.typ, w.probability, w.tempo)
+		}
+		if !g.From.Equal(w.from) || !g.To.Equal(w.to) {
+			t.Errorf("group %d (%s) runs %s to %s, want %s to %s", i, g.Type, g.From, g.To, w.from, w.to)
+		}
+		if g.FlightCategory != w.category {
+			t.Errorf("group %d (%s) category = %s, want %s", i, g.Type, g.FlightCategory, w.category)
+		}
+		if len(g.Unparsed) > 0 {
+			t.Errorf("group %d (%s) unparsed %v", i, g.Type, g.Unparsed)
+		}
+	}
+
+	prob30 := taf.Groups[1]
+	if w := prob30.Wind; w == nil || !w.Variable || w.Speed != 20 || w.Gust == nil || *w.Gust != 35 {
+		t.Errorf("PROB30 wind = %s", describeWind(w))
+	}
+	if len(prob30.Clouds) != 1 || prob30.Clouds[0].Type != "CB" {
+		t.Errorf("PROB30 clouds = %+v", prob30.Clouds)
+	}
+	if !taf.Groups[2].SkyClear {
+		t.Error("FM group is not sky clear")
+	}
+	if w := taf.Groups[4].Wind; w == nil || w.Gust == nil || *w.Gust != 20 {
+		t.Errorf("BECMG wind = %s", describeWind(w))
+	}
+}
+
+func TestDecodeTAFErrors(t *testing.T) {
+	for _, raw := range []string{
+		"TAF KAUS 051720Z",
+		"TAF KAUS 051720Z 0518-0624 18010KT",
+		"TAF KAUS 051720Z 0518/0624 18010KT P6SM SKC TEMPO",
+		"TAF KAUS 051720Z 0518/0624 18010KT P6SM SKC FM329900 BKN010",
+	} {
+		if _, err := DecodeTAF(raw, ref); err == nil {
+			t.Errorf("DecodeTAF(%q) succeeded", raw)
+		}
+	}
+}
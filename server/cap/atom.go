@@ -0,0 +1,130 @@
+package cap
+
+import "strings"
+
+// AtomNamespace is the Atom 1.0 XML namespace.
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+type (
+	// atomEntry is an entry of a CAP Atom feed. It either carries a whole
+	// CAP alert as its content or, as in the NWS feeds, the alert's fields
+	// flattened into cap-namespaced elements of the entry itself.
+	atomEntry struct {
+		ID      string `xml:"http://www.w3.org/2005/Atom id"`
+		Title   string `xml:"http://www.w3.org/2005/Atom title"`
+		Summary string `xml:"http://www.w3.org/2005/Atom summary"`
+		Author  struct {
+			Name string `xml:"name"`
+		} `xml:"http://www.w3.org/2005/Atom author"`
+		Content struct {
+			Alert *Alert `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+		} `xml:"http://www.w3.org/2005/Atom content"`
+
+		Identifier  string       `xml:"identifier"`
+		Sender      string       `xml:"sender"`
+		Sent        string       `xml:"sent"`
+		Status      string       `xml:"status"`
+		MsgType     string       `xml:"msgType"`
+		Event       string       `xml:"event"`
+		Urgency     string       `xml:"urgency"`
+		Severity    string       `xml:"severity"`
+		Certainty   string       `xml:"certainty"`
+		Effective   string       `xml:"effective"`
+		Onset       string       `xml:"onset"`
+		Expires     string       `xml:"expires"`
+		SenderName  string       `xml:"senderName"`
+		Headline    string       `xml:"headline"`
+		Description string       `xml:"description"`
+		Instruction string       `xml:"instruction"`
+		AreaDesc    string       `xml:"areaDesc"`
+		Polygon     []string     `xml:"polygon"`
+		Geocode     []nameValues `xml:"geocode"`
+		Parameter   []nameValues `xml:"parameter"`
+	}
+
+	// nameValues is a flattened geocode or parameter, which may list several
+	// names each followed by its value.
+	nameValues struct {
+		ValueName []string `xml:"valueName"`
+		Value     []string `xml:"value"`
+	}
+)
+
+// alert returns the CAP alert an entry describes, if any. Feeds with no
+// active alerts may hold a placeholder entry with no CAP fields.
+func (e atomEntry) alert() (Alert, bool) {
+	if e.Content.Alert != nil {
+		return *e.Content.Alert, true
+	}
+	if e.Event == "" {
+		return Alert{}, false
+	}
+
+	id := e.Identifier
+	if id == "" {
+		id = e.ID
+	}
+	sender := e.Sender
+	if sender == "" {
+		sender = e.Author.Name
+	}
+	info := Info{
+		Event:       e.Event,
+		Urgency:     e.Urgency,
+		Severity:    e.Severity,
+		Certainty:   e.Certainty,
+		Effective:   e.Effective,
+		Onset:       e.Onset,
+		Expires:     e.Expires,
+		SenderName:  e.SenderName,
+		Headline:    defaultValue(e.Headline, e.Title),
+		Description: defaultValue(e.Description, e.Summary),
+		Instruction: e.Instruction,
+		Parameter:   pairs(e.Parameter, false),
+		Area: []Area{{
+			AreaDesc: e.AreaDesc,
+			Polygon:  nonEmpty(e.Polygon),
+			Geocode:  pairs(e.Geocode, true),
+		}},
+	}
+	return Alert{
+		Identifier: id,
+		Sender:     sender,
+		Sent:       e.Sent,
+		Status:     e.Status,
+		MsgType:    e.MsgType,
+		Info:       []Info{info},
+	}, true
+}
+
+// pairs flattens geocodes or parameters into one name-value pair per value.
+// With split, a value listing several space-separated codes, as older feeds
+// do for geocodes, becomes one pair per code.
+func pairs(nvs []nameValues, split bool) []NameValue {
+	var out []NameValue
+	for _, nv := range nvs {
+		for i, name := range nv.ValueName {
+			if i >= len(nv.Value) {
+				break
+			}
+			values := []string{strings.TrimSpace(nv.Value[i])}
+			if split {
+				values = strings.Fields(nv.Value[i])
+			}
+			for _, v := range values {
+				out = append(out, NameValue{ValueName: name, Value: v})
+			}
+		}
+	}
+	return out
+}
+
+func nonEmpty(ss []string) []string {
+	var out []string
+	for _, s := range ss {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
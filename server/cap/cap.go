@@ -0,0 +1,81 @@
+// Package cap reads and writes OASIS Common Alerting Protocol (CAP) 1.2
+// documents, converting them to and from the GeoJSON alert model in dtos.
+package cap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Namespace is the CAP 1.2 XML namespace.
+const Namespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+// MediaType is the media type NWS uses for CAP documents.
+const MediaType = "application/cap+xml"
+
+type (
+	Alert struct {
+		XMLName    xml.Name `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+		Identifier string   `xml:"identifier"`
+		Sender     string   `xml:"sender"`
+		Sent       string   `xml:"sent"`
+		Status     string   `xml:"status"`
+		MsgType    string   `xml:"msgType"`
+		Source     string   `xml:"source,omitempty"`
+		Scope      string   `xml:"scope"`
+		Code       []string `xml:"code,omitempty"`
+		Note       string   `xml:"note,omitempty"`
+		References string   `xml:"references,omitempty"`
+		Info       []Info   `xml:"info"`
+	}
+
+	Info struct {
+		Language     string      `xml:"language,omitempty"`
+		Category     []string    `xml:"category"`
+		Event        string      `xml:"event"`
+		ResponseType []string    `xml:"responseType,omitempty"`
+		Urgency      string      `xml:"urgency"`
+		Severity     string      `xml:"severity"`
+		Certainty    string      `xml:"certainty"`
+		EventCode    []NameValue `xml:"eventCode,omitempty"`
+		Effective    string      `xml:"effective,omitempty"`
+		Onset        string      `xml:"onset,omitempty"`
+		Expires      string      `xml:"expires,omitempty"`
+		SenderName   string      `xml:"senderName,omitempty"`
+		Headline     string      `xml:"headline,omitempty"`
+		Description  string      `xml:"description,omitempty"`
+		Instruction  string      `xml:"instruction,omitempty"`
+		Web          string      `xml:"web,omitempty"`
+		Parameter    []NameValue `xml:"parameter,omitempty"`
+		Area         []Area      `xml:"area"`
+	}
+
+	Area struct {
+		AreaDesc string      `xml:"areaDesc"`
+		Polygon  []string    `xml:"polygon,omitempty"`
+		Circle   []string    `xml:"circle,omitempty"`
+		Geocode  []NameValue `xml:"geocode,omitempty"`
+	}
+
+	NameValue struct {
+		ValueName string `xml:"valueName"`
+		Value     string `xml:"value"`
+	}
+)
+
+// timeLayout is the CAP dateTime format: RFC 3339 with an explicit offset
+// and without fractional seconds.
+const timeLayout = "2006-01-02T15:04:05-07:00"
+
+// formatTime normalizes an RFC 3339 timestamp to the CAP dateTime format.
+// Values that cannot be parsed are passed through unchanged.
+func formatTime(s string) string {
+	if s == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Format(timeLayout)
+}
@@ -0,0 +1,289 @@
+package cap
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"weather/server/dtos"
+)
+
+// defaultSender is the sender given to exported alerts that lack one; it is
+// the sender NWS uses on its CAP messages.
+const defaultSender = "w-nws.webmaster@noaa.gov"
+
+// Parse decodes CAP 1.2 alerts into features. The input may be a single
+// alert, several alerts one after another or wrapped in another element, or
+// an Atom feed whose entries carry alerts, either whole or flattened into
+// the entry as NWS feeds do. Each alert's first info block (preferring
+// English) becomes the feature's properties and every area contributes to
+// the geometry and geocodes.
+func Parse(r io.Reader) ([]dtos.Feature, error) {
+	var (
+		features []dtos.Feature
+		found    bool
+	)
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cap: decoding alerts: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var a Alert
+		switch start.Name {
+		case xml.Name{Space: AtomNamespace, Local: "feed"}:
+			// A feed with no active alerts is still a valid answer.
+			found = true
+			continue
+		case xml.Name{Space: Namespace, Local: "alert"}:
+			if err := d.DecodeElement(&a, &start); err != nil {
+				return nil, fmt.Errorf("cap: decoding alert: %w", err)
+			}
+		case xml.Name{Space: AtomNamespace, Local: "entry"}:
+			var e atomEntry
+			if err := d.DecodeElement(&e, &start); err != nil {
+				return nil, fmt.Errorf("cap: decoding feed entry: %w", err)
+			}
+			if a, ok = e.alert(); !ok {
+				continue
+			}
+		default:
+			continue
+		}
+
+		found = true
+		if len(a.Info) == 0 {
+			return nil, fmt.Errorf("cap: alert %q has no info block", a.Identifier)
+		}
+		f, err := toFeature(a)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, f)
+	}
+	if !found {
+		return nil, errors.New("cap: no CAP alert or Atom feed found")
+	}
+	return features, nil
+}
+
+// Marshal encodes a feature as a CAP 1.2 document.
+func Marshal(f dtos.Feature) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(fromFeature(f)); err != nil {
+		return nil, fmt.Errorf("cap: encoding alert %q: %w", f.ID, err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func toFeature(a Alert) (dtos.Feature, error) {
+	info := a.Info[0]
+	for _, i := range a.Info {
+		if strings.HasPrefix(strings.ToLower(i.Language), "en") {
+			info = i
+			break
+		}
+	}
+
+	f := dtos.Feature{ID: a.Identifier}
+	p := &f.AlertProperties
+	p.Event = info.Event
+	p.Severity = info.Severity
+	p.Urgency = info.Urgency
+	p.Certainty = info.Certainty
+	p.Status = a.Status
+	p.Sender = a.Sender
+	p.SenderName = info.SenderName
+	p.Headline = info.Headline
+	p.Description = info.Description
+	p.Instruction = info.Instruction
+	p.MessageType = a.MsgType
+	// CAP requires sent, but some feeds leave it out; the effective time
+	// is the closest stand-in.
+	p.Sent = defaultValue(a.Sent, info.Effective)
+	if p.Sent == "" {
+		return dtos.Feature{}, fmt.Errorf("cap: alert %q has no sent or effective time", a.Identifier)
+	}
+	p.Effective = info.Effective
+	p.Onset = info.Onset
+	p.Expires = info.Expires
+
+	for _, pv := range info.Parameter {
+		if p.Parameters == nil {
+			p.Parameters = make(map[string][]string)
+		}
+		p.Parameters[pv.ValueName] = append(p.Parameters[pv.ValueName], pv.Value)
+	}
+	if ends := p.Parameters["eventEndingTime"]; len(ends) > 0 {
+		p.Ends = ends[0]
+	}
+
+	var (
+		descs    []string
+		polygons [][][][2]float64
+	)
+	for _, area := range info.Area {
+		descs = append(descs, area.AreaDesc)
+		for _, g := range area.Geocode {
+			switch g.ValueName {
+			case "UGC":
+				p.Geocode.UGC = append(p.Geocode.UGC, g.Value)
+			case "SAME", "FIPS6":
+				// Older feeds call SAME codes FIPS6.
+				p.Geocode.SAME = append(p.Geocode.SAME, g.Value)
+			}
+		}
+		for _, poly := range area.Polygon {
+			ring, err := parsePolygon(poly)
+			if err != nil {
+				return dtos.Feature{}, fmt.Errorf("cap: alert %q: %w", a.Identifier, err)
+			}
+			polygons = append(polygons, [][][2]float64{ring})
+		}
+	}
+	p.AreaDesc = strings.Join(descs, "; ")
+
+	switch len(polygons) {
+	case 0:
+	case 1:
+		coords, _ := json.Marshal(polygons[0])
+		f.Geometry = &dtos.Geometry{Type: "Polygon", Coordinates: coords}
+	default:
+		coords, _ := json.Marshal(polygons)
+		f.Geometry = &dtos.Geometry{Type: "MultiPolygon", Coordinates: coords}
+	}
+	return f, nil
+}
+
+func fromFeature(f dtos.Feature) Alert {
+	p := f.AlertProperties
+
+	info := Info{
+		Language:    "en-US",
+		Category:    []string{"Met"},
+		Event:       p.Event,
+		Urgency:     defaultValue(p.Urgency, "Unknown"),
+		Severity:    defaultValue(p.Severity, "Unknown"),
+		Certainty:   defaultValue(p.Certainty, "Unknown"),
+		Effective:   formatTime(p.Effective),
+		Onset:       formatTime(p.Onset),
+		Expires:     formatTime(p.Expires),
+		SenderName:  p.SenderName,
+		Headline:    p.Headline,
+		Description: p.Description,
+		Instruction: p.Instruction,
+	}
+
+	keys := make([]string, 0, len(p.Parameters))
+	for k := range p.Parameters {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		for _, v := range p.Parameters[k] {
+			info.Parameter = append(info.Parameter, NameValue{ValueName: k, Value: v})
+		}
+	}
+
+	area := Area{AreaDesc: defaultValue(p.AreaDesc, "Unknown")}
+	for _, code := range p.Geocode.SAME {
+		area.Geocode = append(area.Geocode, NameValue{ValueName: "SAME", Value: code})
+	}
+	for _, code := range p.Geocode.UGC {
+		area.Geocode = append(area.Geocode, NameValue{ValueName: "UGC", Value: code})
+	}
+	area.Polygon = formatPolygons(f.Geometry)
+	info.Area = []Area{area}
+
+	return Alert{
+		Identifier: f.ID,
+		Sender:     defaultValue(p.Sender, defaultSender),
+		Sent:       formatTime(defaultValue(p.Sent, p.Effective)),
+		Status:     defaultValue(p.Status, "Actual"),
+		MsgType:    defaultValue(p.MessageType, "Alert"),
+		Scope:      "Public",
+		Info:       []Info{info},
+	}
+}
+
+// parsePolygon converts a CAP polygon ("lat,lon lat,lon ...") into a GeoJSON
+// ring of [lon, lat] positions.
+func parsePolygon(s string) ([][2]float64, error) {
+	var ring [][2]float64
+	for _, pair := range strings.Fields(s) {
+		lat, lon, ok := strings.Cut(pair, ",")
+		if !ok {
+			return nil, fmt.Errorf("invalid polygon point %q", pair)
+		}
+		la, err := strconv.ParseFloat(lat, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polygon latitude %q", lat)
+		}
+		lo, err := strconv.ParseFloat(lon, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polygon longitude %q", lon)
+		}
+		ring = append(ring, [2]float64{lo, la})
+	}
+	if len(ring) < 4 {
+		return nil, fmt.Errorf("polygon needs at least 4 points, got %d", len(ring))
+	}
+	return ring, nil
+}
+
+// formatPolygons converts the outer rings of a GeoJSON Polygon or
+// MultiPolygon into CAP polygons.
+func formatPolygons(g *dtos.Geometry) []string {
+	if g == nil {
+		return nil
+	}
+
+	var polys [][][][2]float64
+	switch g.Type {
+	case "Polygon":
+		var p [][][2]float64
+		if json.Unmarshal(g.Coordinates, &p) == nil {
+			polys = append(polys, p)
+		}
+	case "MultiPolygon":
+		_ = json.Unmarshal(g.Coordinates, &polys)
+	}
+
+	var out []string
+	for _, p := range polys {
+		if len(p) == 0 {
+			continue
+		}
+		pairs := make([]string, 0, len(p[0]))
+		for _, pos := range p[0] {
+			pairs = append(pairs, strconv.FormatFloat(pos[1], 'f', -1, 64)+","+strconv.FormatFloat(pos[0], 'f', -1, 64))
+		}
+		out = append(out, strings.Join(pairs, " "))
+	}
+	return out
+}
+
+func defaultValue(s, fallback string) string {
+	if strings.TrimSpace(s) == "" {
+		return fallback
+	}
+	return s
+}
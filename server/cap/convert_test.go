@@ -0,0 +1,159 @@
+package cap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func capAlert(id, sender, sent string) string {
+	return fmt.Sprintf(`<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+  <identifier>%s</identifier>
+  <sender>%s</sender>
+  %s
+  <status>Actual</status>
+  <msgType>Alert</msgType>
+  <scope>Public</scope>
+  <info>
+    <language>en-US</language>
+    <category>Met</category>
+    <event>Flood Warning</event>
+    <urgency>Immediate</urgency>
+    <severity>Severe</severity>
+    <certainty>Likely</certainty>
+    <effective>2025-08-05T13:00:00-05:00</effective>
+    <expires>2025-08-05T19:00:00-05:00</expires>
+    <area>
+      <areaDesc>Travis</areaDesc>
+      <geocode><valueName>UGC</valueName><value>TXZ192</value></geocode>
+    </area>
+  </info>
+</alert>`, id, sender, sent)
+}
+
+const nwsFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:cap="urn:oasis:names:tc:emergency:cap:1.2">
+  <id>https://api.weather.gov/alerts/active?area=TX</id>
+  <title>Current watches, warnings, and advisories for Texas</title>
+  <entry>
+    <id>urn:oid:2.49.0.1.840.0.1</id>
+    <title>Heat Advisory issued August 5 at 1:00PM CDT</title>
+    <summary>Heat index values up to 110 expected.</summary>
+    <author><name>w-nws.webmaster@noaa.gov</name></author>
+    <cap:event>Heat Advisory</cap:event>
+    <cap:sent>2025-08-05T13:00:00-05:00</cap:sent>
+    <cap:effective>2025-08-05T13:00:00-05:00</cap:effective>
+    <cap:expires>2025-08-05T20:00:00-05:00</cap:expires>
+    <cap:status>Actual</cap:status>
+    <cap:msgType>Alert</cap:msgType>
+    <cap:urgency>Expected</cap:urgency>
+    <cap:severity>Moderate</cap:severity>
+    <cap:certainty>Likely</cap:certainty>
+    <cap:areaDesc>Travis; Hays</cap:areaDesc>
+    <cap:polygon></cap:polygon>
+    <cap:geocode>
+      <valueName>FIPS6</valueName><value>048453 048209</value>
+      <valueName>UGC</valueName><value>TXZ192 TXZ191</value>
+    </cap:geocode>
+    <cap:parameter><valueName>VTEC</valueName><value>/O.NEW.KEWX.HT.Y.0007.250805T1800Z-250806T0100Z/</value></cap:parameter>
+  </entry>
+  <entry>
+    <id>urn:oid:2.49.0.1.840.0.2</id>
+    <title>Flood Warning</title>
+    <content type="application/cap+xml">` + "%s" + `</content>
+  </entry>
+</feed>`
+
+func TestParse(t *testing.T) {
+	one := capAlert("a", "w-nws.webmaster@noaa.gov", "<sent>2025-08-05T13:00:00-05:00</sent>")
+	two := capAlert("b", "alerts@example.org", "<sent>2025-08-05T14:00:00-05:00</sent>")
+	tests := []struct {
+		name    string
+		input   string
+		ids     []string
+		senders []string
+	}{
+		{"single", one, []string{"a"}, []string{"w-nws.webmaster@noaa.gov"}},
+		{"concatenated", one + "\n" + two, []string{"a", "b"}, []string{"w-nws.webmaster@noaa.gov", "alerts@example.org"}},
+		{"wrapped", "<alerts>" + one + two + "</alerts>", []string{"a", "b"}, []string{"w-nws.webmaster@noaa.gov", "alerts@example.org"}},
+		{"atom", fmt.Sprintf(nwsFeed, two), []string{"urn:oid:2.49.0.1.840.0.1", "b"}, []string{"w-nws.webmaster@noaa.gov", "alerts@example.org"}},
+		{"empty feed", `<feed xmlns="http://www.w3.org/2005/Atom"><title>No alerts</title></feed>`, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(features) != len(tt.ids) {
+				t.Fatalf("got %d features, want %d", len(features), len(tt.ids))
+			}
+			for i, f := range features {
+				if f.ID != tt.ids[i] || f.Sender != tt.senders[i] {
+					t.Errorf("feature %d = %s from %s, want %s from %s", i, f.ID, f.Sender, tt.ids[i], tt.senders[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseAtomFlattened(t *testing.T) {
+	features, err := Parse(strings.NewReader(fmt.Sprintf(nwsFeed, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(features) != 1 {
+		t.Fatalf("got %d features, want 1", len(features))
+	}
+	f := features[0]
+	if f.Event != "Heat Advisory" || f.Headline != "Heat Advisory issued August 5 at 1:00PM CDT" || f.Description != "Heat index values up to 110 expected." {
+		t.Errorf("feature = %+v", f.AlertProperties)
+	}
+	if got := strings.Join(f.Geocode.SAME, " "); got != "048453 048209" {
+		t.Errorf("SAME = %s", got)
+	}
+	if got := strings.Join(f.Geocode.UGC, " "); got != "TXZ192 TXZ191" {
+		t.Errorf("UGC = %s", got)
+	}
+	if v := f.Parameters["VTEC"]; len(v) != 1 || !strings.HasPrefix(v[0], "/O.NEW.KEWX.HT.Y.0007") {
+		t.Errorf("VTEC = %v", v)
+	}
+	if f.Geometry != nil {
+		t.Errorf("empty polygon gave geometry %+v", f.Geometry)
+	}
+}
+
+func TestParseSent(t *testing.T) {
+	features, err := Parse(strings.NewReader(capAlert("a", "x@example.org", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := features[0].Sent; got != "2025-08-05T13:00:00-05:00" {
+		t.Errorf("Sent = %q, want the effective time", got)
+	}
+
+	noTimes := strings.Replace(capAlert("a", "x@example.org", ""), "<effective>2025-08-05T13:00:00-05:00</effective>", "", 1)
+	if _, err := Parse(strings.NewReader(noTimes)); err == nil {
+		t.Error("Parse accepted an alert with neither sent nor effective")
+	}
+}
+
+func TestMarshalSender(t *testing.T) {
+	features, err := Parse(strings.NewReader(capAlert("a", "alerts@example.org", "<sent>2025-08-05T13:00:00-05:00</sent>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Marshal(features[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc), "<sender>alerts@example.org</sender>") {
+		t.Errorf("Marshal dropped the sender:\n%s", doc)
+	}
+
+	features[0].Sender = ""
+	doc, _ = Marshal(features[0])
+	if !strings.Contains(string(doc), "<sender>"+defaultSender+"</sender>") {
+		t.Errorf("Marshal without a sender:\n%s", doc)
+	}
+}
@@ -0,0 +1,248 @@
+// Package chart renders forecast time series as SVG or PNG images in pure
+// Go, and as text sparklines for terminals. A chart is a stack of panels,
+// each with its own value axis, sharing one time axis.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Colors for series.
+var (
+	Red        = color.RGBA{0xd6, 0x27, 0x28, 0xff}
+	Blue       = color.RGBA{0x1f, 0x77, 0xb4, 0xff}
+	Green      = color.RGBA{0x2c, 0xa0, 0x2c, 0xff}
+	LightGreen = color.RGBA{0x98, 0xdf, 0x8a, 0xff}
+	Orange     = color.RGBA{0xff, 0x7f, 0x0e, 0xff}
+
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	ink        = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	grid       = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+)
+
+// Series is one forecast element plotted on a panel, one value per hour of
+// the chart. NaN values are gaps.
+type Series struct {
+	Name   string
+	Values []float64
+	Color  color.RGBA
+	Bars   bool
+	Dashed bool
+}
+
+// Panel is one plot with its own value axis. When Max > Min the axis has
+// that fixed range, e.g. 0 to 100 for percentages; otherwise it fits the
+// data, including zero when FromZero is set.
+type Panel struct {
+	Title    string
+	Unit     string
+	Min      float64
+	Max      float64
+	FromZero bool
+	Series   []Series
+}
+
+// Chart is hourly data starting at Start, labelled in Location's time.
+type Chart struct {
+	Title    string
+	Start    time.Time
+	Hours    int
+	Location *time.Location
+	Panels   []Panel
+	Width    int
+	Height   int
+}
+
+const (
+	marginLeft   = 52
+	marginRight  = 16
+	marginTop    = 34
+	marginBottom = 28
+	panelGap     = 26
+)
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+type point struct{ x, y float64 }
+
+// canvas is what a chart draws on. Text is positioned by its vertical
+// center; scale 1 text is 7 pixels high.
+type canvas interface {
+	rect(x, y, w, h float64, fill color.RGBA)
+	polyline(pts []point, stroke color.RGBA, width float64, dashed bool)
+	text(x, y float64, s string, scale int, a anchor, fill color.RGBA)
+}
+
+// draw lays out the chart and draws it on c.
+func (ch *Chart) draw(c canvas) {
+	w, h := float64(ch.Width), float64(ch.Height)
+	c.rect(0, 0, w, h, background)
+	c.text(w/2, 14, ch.Title, 2, anchorMiddle, ink)
+	if ch.Hours <= 0 || len(ch.Panels) == 0 {
+		return
+	}
+
+	x0, x1 := float64(marginLeft), w-marginRight
+	hourWidth := (x1 - x0) / float64(ch.Hours)
+	panelHeight := (h - marginTop - marginBottom - panelGap*float64(len(ch.Panels)-1)) / float64(len(ch.Panels))
+
+	// Time axis: vertical grid lines through every panel, labelled below.
+	loc := ch.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	step := timeStep(ch.Hours)
+	for i := 0; i <= ch.Hours; i++ {
+		t := ch.Start.Add(time.Duration(i) * time.Hour).In(loc)
+		if t.Hour()%step != 0 {
+			continue
+		}
+		x := x0 + float64(i)*hourWidth
+		for j := range ch.Panels {
+			top := marginTop + float64(j)*(panelHeight+panelGap)
+			c.polyline([]point{{x, top}, {x, top + panelHeight}}, grid, 1, false)
+		}
+		label := t.Format("3PM")
+		if t.Hour() == 0 {
+			label = t.Format("Mon")
+		}
+		c.text(x, h-marginBottom+12, label, 1, anchorMiddle, ink)
+	}
+
+	for j, p := range ch.Panels {
+		top := marginTop + float64(j)*(panelHeight+panelGap)
+		ch.drawPanel(c, p, x0, x1, top, top+panelHeight, hourWidth)
+	}
+}
+
+func (ch *Chart) drawPanel(c canvas, p Panel, x0, x1, top, bottom, hourWidth float64) {
+	lo, hi := p.Min, p.Max
+	if hi <= lo {
+		lo, hi = dataRange(p.Series)
+		if p.FromZero {
+			lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+		}
+	}
+	ticks, lo, hi := niceTicks(lo, hi, 4)
+	y := func(v float64) float64 { return bottom - (v-lo)/(hi-lo)*(bottom-top) }
+
+	for _, t := range ticks {
+		c.polyline([]point{{x0, y(t)}, {x1, y(t)}}, grid, 1, false)
+		c.text(x0-5, y(t), formatTick(t), 1, anchorEnd, ink)
+	}
+	title := p.Title
+	if p.Unit != "" {
+		title += " (" + p.Unit + ")"
+	}
+	c.text(x0, top-9, title, 1, anchorStart, ink)
+
+	// Legend, right-aligned above the panel.
+	lx := x1
+	for i := len(p.Series) - 1; i >= 0; i-- {
+		s := p.Series[i]
+		c.text(lx, top-9, s.Name, 1, anchorEnd, s.Color)
+		lx -= float64(len([]rune(s.Name))*glyphAdvance + 12)
+	}
+
+	base := y(math.Max(lo, math.Min(0, hi)))
+	for _, s := range p.Series {
+		if s.Bars {
+			for i, v := range s.Values {
+				if i >= ch.Hours || math.IsNaN(v) {
+					continue
+				}
+				x := x0 + float64(i)*hourWidth + hourWidth*0.1
+				top := math.Min(y(v), base)
+				c.rect(x, top, hourWidth*0.8, math.Abs(base-y(v)), s.Color)
+			}
+			continue
+		}
+		var run []point
+		for i, v := range s.Values {
+			if i >= ch.Hours || math.IsNaN(v) {
+				if len(run) > 0 {
+					c.polyline(run, s.Color, 2, s.Dashed)
+				}
+				run = nil
+				continue
+			}
+			run = append(run, point{x0 + (float64(i)+0.5)*hourWidth, y(v)})
+		}
+		if len(run) > 0 {
+			c.polyline(run, s.Color, 2, s.Dashed)
+		}
+	}
+
+	c.polyline([]point{{x0, top}, {x0, bottom}, {x1, bottom}}, ink, 1, false)
+}
+
+// dataRange returns the smallest and largest values of the series.
+func dataRange(series []Series) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+	if math.IsInf(lo, 0) {
+		return 0, 1
+	}
+	return lo, hi
+}
+
+// niceTicks picks about n evenly spaced round tick values covering [lo, hi]
+// and returns them with the widened range.
+func niceTicks(lo, hi float64, n int) ([]float64, float64, float64) {
+	if hi-lo < 1e-9 {
+		lo, hi = lo-1, hi+1
+	}
+	raw := (hi - lo) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag * 10
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+	lo = math.Floor(lo/step) * step
+	hi = math.Ceil(hi/step) * step
+	var ticks []float64
+	for k := 0; lo+float64(k)*step <= hi+step/2; k++ {
+		// Round away float error so 0.1 steps label as 0.3, not 0.30000000000000004.
+		ticks = append(ticks, math.Round((lo+float64(k)*step)*1e6)/1e6)
+	}
+	return ticks, lo, hi
+}
+
+func formatTick(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.Itoa(int(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// timeStep is the spacing of time labels in hours.
+func timeStep(hours int) int {
+	switch {
+	case hours <= 24:
+		return 3
+	case hours <= 48:
+		return 6
+	case hours <= 96:
+		return 12
+	}
+	return 24
+}
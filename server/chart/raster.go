@@ -0,0 +1,93 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"unicode"
+)
+
+// PNGMediaType is the media type of PNG images.
+const PNGMediaType = "image/png"
+
+// PNG renders the chart as a PNG image.
+func (ch *Chart) PNG() ([]byte, error) {
+	c := &rasterCanvas{img: image.NewRGBA(image.Rect(0, 0, ch.Width, ch.Height))}
+	ch.draw(c)
+	var b bytes.Buffer
+	if err := png.Encode(&b, c.img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+func (c *rasterCanvas) rect(x, y, w, h float64, fill color.RGBA) {
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(c.img, r, image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+// polyline strokes each segment by stamping a square of the line width
+// every half pixel. Dashes are 6 pixels on and 4 off, continuing across
+// segments.
+func (c *rasterCanvas) polyline(pts []point, stroke color.RGBA, width float64, dashed bool) {
+	const step = 0.5
+	half := width / 2
+	walked := 0.0
+	for i := 1; i < len(pts); i++ {
+		p, q := pts[i-1], pts[i]
+		dx, dy := q.x-p.x, q.y-p.y
+		length := math.Hypot(dx, dy)
+		for d := 0.0; d <= length; d += step {
+			if dashed && math.Mod(walked+d, 10) >= 6 {
+				continue
+			}
+			t := 0.0
+			if length > 0 {
+				t = d / length
+			}
+			x, y := p.x+dx*t, p.y+dy*t
+			r := image.Rect(int(math.Floor(x-half+0.5)), int(math.Floor(y-half+0.5)), int(math.Floor(x+half+0.5)), int(math.Floor(y+half+0.5)))
+			draw.Draw(c.img, r, image.NewUniform(stroke), image.Point{}, draw.Src)
+		}
+		walked += length
+	}
+}
+
+// text draws s in the built-in bitmap font, each font pixel a scale x scale
+// square.
+func (c *rasterCanvas) text(x, y float64, s string, scale int, a anchor, fill color.RGBA) {
+	runes := []rune(s)
+	width := float64((len(runes)*glyphAdvance - 1) * scale)
+	switch a {
+	case anchorMiddle:
+		x -= width / 2
+	case anchorEnd:
+		x -= width
+	}
+	left := int(math.Round(x))
+	top := int(math.Round(y - float64(glyphHeight*scale)/2))
+	src := image.NewUniform(fill)
+	for i, r := range runes {
+		g, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			continue
+		}
+		gx := left + i*glyphAdvance*scale
+		for row, bits := range g {
+			for col, bit := range bits {
+				if bit != '#' {
+					continue
+				}
+				px, py := gx+col*scale, top+row*scale
+				draw.Draw(c.img, image.Rect(px, py, px+scale, py+scale), src, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
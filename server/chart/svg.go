@@ -0,0 +1,55 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// SVGMediaType is the media type of SVG images.
+const SVGMediaType = "image/svg+xml"
+
+// SVG renders the chart as an SVG document.
+func (ch *Chart) SVG() []byte {
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace">`+"\n",
+		ch.Width, ch.Height, ch.Width, ch.Height)
+	ch.draw(c)
+	c.b.WriteString("</svg>\n")
+	return c.b.Bytes()
+}
+
+type svgCanvas struct {
+	b bytes.Buffer
+}
+
+func (c *svgCanvas) rect(x, y, w, h float64, fill color.RGBA) {
+	fmt.Fprintf(&c.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, hex(fill))
+}
+
+func (c *svgCanvas) polyline(pts []point, stroke color.RGBA, width float64, dashed bool) {
+	coords := make([]string, len(pts))
+	for i, p := range pts {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", p.x, p.y)
+	}
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="6 4"`
+	}
+	fmt.Fprintf(&c.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"%s/>`+"\n",
+		strings.Join(coords, " "), hex(stroke), width, dash)
+}
+
+func (c *svgCanvas) text(x, y float64, s string, scale int, a anchor, fill color.RGBA) {
+	anchors := [...]string{"start", "middle", "end"}
+	fmt.Fprintf(&c.b, `<text x="%.1f" y="%.1f" font-size="%d" fill="%s" text-anchor="%s" dominant-baseline="central">`,
+		x, y, 10*scale, hex(fill), anchors[a])
+	xml.EscapeText(&c.b, []byte(s))
+	c.b.WriteString("</text>\n")
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
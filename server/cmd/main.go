@@ -1,31 +1,71 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"weather/server/config"
 	"weather/server/srv"
+
+	"github.com/spf13/pflag"
 )
 
 func main() {
-	slog.Info("Starting weather MCP server...")
+	// The transport, address and everything else come from flags, WEATHER_*
+	// environment variables or a config file; see config.Load and --help.
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.ListWatches || len(cfg.DeleteWatches) > 0 {
+		if err := manageWatches(cfg, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	slog.SetDefault(cfg.Logger(os.Stderr))
 
-	// Create a new server instance. Tool registration is now handled within NewServer.
-	srv := srv.NewServer()
+	slog.Info("Starting weather MCP server...", "transport", cfg.Transport)
 
-	// To run with stdio transport for command-line usage, comment out the line
-	// above and uncomment the one below:
-	// if err := srv.RunStdio(); err != nil {
-	// 	slog.Error("failed to start server", "error", err)
-	// }
+	// The first SIGINT or SIGTERM shuts down gracefully, a second one exits
+	// at once.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Info("Signal received, shutting down; signal again to force exit", "signal", sig)
+		cancel()
+		<-signals
+		slog.Warn("Second signal received, exiting")
+		os.Exit(1)
+	}()
 
-	// To run with HTTP transport, use this line:
-	if err := srv.RunHTTP(":8080"); err != nil {
+	// Create a new server instance. Tool registration is now handled within NewServer.
+	srv, err := srv.NewServer(cfg)
+	if err != nil {
 		slog.Error("failed to start server", "error", err)
 		os.Exit(1)
 	}
-
-	// To run with SSE HTTP transport
-	// if err := srv.RunSSE(); err != nil {
-	// 	os.Exit(1)
-	// }
+	if err := srv.Run(ctx); err != nil {
+		slog.Error("server stopped with an error", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"weather/server/config"
+	"weather/server/watch"
+)
+
+// manageWatches lists or deletes the watches in the configured store for an
+// administrator, e.g. watches created with an owner token that was lost.
+// The server keeps its own copy of the store, so it must not be running.
+func manageWatches(cfg *config.Config, w io.Writer) error {
+	if cfg.Watch.Store == "" {
+		return errors.New("watch.store is empty, so no watches are stored")
+	}
+	store, err := watch.Open(cfg.Watch.Store)
+	if err != nil {
+		return err
+	}
+	if len(cfg.DeleteWatches) > 0 {
+		missing, err := store.Remove(cfg.DeleteWatches...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Deleted %d watches.\n", len(cfg.DeleteWatches)-len(missing))
+		if len(missing) > 0 {
+			return fmt.Errorf("no watches with IDs %s", strings.Join(missing, ", "))
+		}
+	}
+	if cfg.ListWatches {
+		for _, wt := range store.List() {
+			owner := "session " + wt.SessionID
+			if wt.Owner != "" {
+				owner = "owner token " + wt.Owner[:min(8, len(wt.Owner))]
+			}
+			fmt.Fprintf(w, "%s\t%s\tcreated %s\t%s at %.4f, %.4f", wt.ID, owner, wt.CreatedAt.Format(time.RFC3339), wt.Condition(), wt.Latitude, wt.Longitude)
+			if wt.WebhookURL != "" {
+				fmt.Fprintf(w, "\t%s", wt.WebhookURL)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+// Package config loads the server configuration from command-line flags,
+// WEATHER_* environment variables and an optional YAML, TOML or JSON file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/aviation"
+	"weather/server/nws"
+	"weather/server/provider"
+	"weather/server/watch"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Transports.
+const (
+	TransportStdio      = "stdio"
+	TransportStreamable = "streamable"
+	TransportSSE        = "sse"
+	// TransportServe serves streamable HTTP and SSE on one address, and
+	// stdio as well when Stdio is set.
+	TransportServe = "serve"
+)
+
+var (
+	transports = []string{TransportStdio, TransportStreamable, TransportSSE, TransportServe}
+	logLevels  = []string{"debug", "info", "warn", "error"}
+	logFormats = []string{"text", "json"}
+	// providers are the names fallback may list, as registered by the
+	// server's provider router.
+	providers = []string{"nws", "openmeteo"}
+)
+
+type (
+	Config struct {
+		Transport        string          `mapstructure:"transport"`
+		Addr             string          `mapstructure:"addr"`
+		Path             string          `mapstructure:"path"`
+		SSEPath          string          `mapstructure:"sse_path"`
+		Stdio            bool            `mapstructure:"stdio"`
+		ShutdownGrace    time.Duration   `mapstructure:"shutdown_grace"`
+		NWS              NWSConfig       `mapstructure:"nws"`
+		OpenMeteo        OpenMeteoConfig `mapstructure:"openmeteo"`
+		TAF              TAFConfig       `mapstructure:"taf"`
+		Fallback         []string        `mapstructure:"fallback"`
+		ActivityProfiles string          `mapstructure:"activity_profiles"`
+		GazetteerDir     string          `mapstructure:"gazetteer_dir"`
+		ZonesFile        string          `mapstructure:"zones_file"`
+		Watch            WatchConfig     `mapstructure:"watch"`
+		Log              LogConfig       `mapstructure:"log"`
+
+		// PrintConfig asks the caller to print the configuration and exit.
+		PrintConfig bool `mapstructure:"-"`
+		// ListWatches and DeleteWatches ask the caller to list the stored
+		// watches or delete some by ID, whoever owns them, and exit.
+		ListWatches   bool     `mapstructure:"-"`
+		DeleteWatches []string `mapstructure:"-"`
+	}
+
+	NWSConfig struct {
+		BaseURL           string        `mapstructure:"base_url"`
+		UserAgent         string        `mapstructure:"user_agent"`
+		MaxConcurrent     int           `mapstructure:"max_concurrent"`
+		RequestsPerSecond float64       `mapstructure:"requests_per_second"`
+		PointsTTL         time.Duration `mapstructure:"points_ttl"`
+		ZonesTTL          time.Duration `mapstructure:"zones_ttl"`
+	}
+
+	OpenMeteoConfig struct {
+		BaseURL string `mapstructure:"base_url"`
+	}
+
+	TAFConfig struct {
+		BaseURL string `mapstructure:"base_url"`
+	}
+
+	// WatchConfig configures weather watches. An empty Store keeps watches
+	// in memory only. WebhookAllow lists the private, loopback or link-local
+	// addresses and ranges webhooks may be sent to.
+	WatchConfig struct {
+		Store        string        `mapstructure:"store"`
+		Interval     time.Duration `mapstructure:"interval"`
+		WebhookAllow []string      `mapstructure:"webhook_allow"`
+	}
+
+	LogConfig struct {
+		Level  string `mapstructure:"level"`
+		Format string `mapstructure:"format"`
+	}
+)
+
+// option is a configuration key with its default and help text. Env names
+// older than the WEATHER_ prefix are still honoured.
+type option struct {
+	key       string
+	value     any
+	usage     string
+	legacyEnv string
+}
+
+var options = []option{
+	{"transport", TransportStreamable, "transport to serve: stdio, streamable, sse, or serve for streamable and sse together", ""},
+	{"addr", ":8080", "listen address for the HTTP transports", ""},
+	{"path", "/mcp/stream", "HTTP path of the streamable MCP endpoint", ""},
+	{"sse_path", "/mcp/sse", "HTTP path of the SSE MCP endpoint; messages are POSTed to the same path", ""},
+	{"stdio", false, "with transport serve, also serve stdio", ""},
+	{"shutdown_grace", 10 * time.Second, "how long in-flight calls may finish on shutdown before they are cancelled", ""},
+	{"nws.base_url", nws.DefaultBaseURL, "NWS API base URL", ""},
+	{"nws.user_agent", nws.DefaultUserAgent, "User-Agent sent to the NWS API; include a contact", ""},
+	{"nws.max_concurrent", nws.DefaultMaxConcurrent, "maximum NWS requests in flight", ""},
+	{"nws.requests_per_second", float64(nws.DefaultPerSecond), "maximum NWS requests started per second", ""},
+	{"nws.points_ttl", nws.DefaultPointsTTL, "how long NWS point lookups are cached", ""},
+	{"nws.zones_ttl", nws.DefaultZonesTTL, "how long NWS zone lookups are cached", ""},
+	{"openmeteo.base_url", provider.DefaultOpenMeteoURL, "Open-Meteo API base URL", "OPENMETEO_BASE_URL"},
+	{"taf.base_url", aviation.DefaultTAFURL, "TAF API base URL", "TAF_BASE_URL"},
+	{"fallback", []string{"nws", "openmeteo"}, "provider fallback order; empty disables fallback", ""},
+	{"activity_profiles", "", "file with extra score_activity profiles", "ACTIVITY_PROFILES"},
+	{"gazetteer_dir", "", "directory with Census Bureau gazetteer files to use instead of the embedded sample", ""},
+	{"zones_file", "", "NWS zone-county correlation file to use instead of the embedded sample", ""},
+	{"watch.store", "watches.json", "file watches are saved to; empty keeps them in memory", "WATCH_STORE"},
+	{"watch.interval", 10 * time.Minute, "how often watches are checked", "WATCH_INTERVAL"},
+	{"watch.webhook_allow", []string{}, "private, loopback or link-local addresses or CIDR ranges watch webhooks may be sent to", ""},
+	{"log.level", "info", "log level: debug, info, warn or error", ""},
+	{"log.format", "text", "log format: text or json", ""},
+}
+
+// flagName turns a key such as "nws.base_url" into "nws-base-url".
+func flagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
+
+// Load builds the configuration from, in increasing priority, defaults, the
+// config file, environment variables and the command-line flags in args.
+// The config file is the one named by --config or WEATHER_CONFIG, or else
+// weather.yaml (or .toml, .json) in the working directory if there is one.
+// Environment variables are the keys prefixed with WEATHER_, upper-cased,
+// with dots as underscores, e.g. WEATHER_NWS_USER_AGENT.
+func Load(args []string) (*Config, error) {
+	fs := pflag.NewFlagSet("weather", pflag.ContinueOnError)
+	configFile := fs.String("config", "", "config file (YAML, TOML or JSON)")
+	printConfig := fs.Bool("print-config", false, "print the effective configuration as YAML and exit")
+	listWatches := fs.Bool("list-watches", false, "list every stored watch with its owner and exit; run while the server is stopped")
+	deleteWatches := fs.StringSlice("delete-watch", nil, "delete stored watches by ID, whoever owns them, and exit; run while the server is stopped")
+	for _, o := range options {
+		name := flagName(o.key)
+		switch v := o.value.(type) {
+		case string:
+			fs.String(name, v, o.usage)
+		case bool:
+			fs.Bool(name, v, o.usage)
+		case int:
+			fs.Int(name, v, o.usage)
+		case float64:
+			fs.Float64(name, v, o.usage)
+		case time.Duration:
+			fs.Duration(name, v, o.usage)
+		case []string:
+			fs.StringSlice(name, v, o.usage)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetEnvPrefix("WEATHER")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// An empty WEATHER_FALLBACK disables fallback, so empty values count.
+	v.AllowEmptyEnv(true)
+	for _, o := range options {
+		env := "WEATHER_" + strings.ToUpper(strings.ReplaceAll(o.key, ".", "_"))
+		names := []string{o.key, env}
+		if o.legacyEnv != "" {
+			names = append(names, o.legacyEnv)
+		}
+		if err := v.BindEnv(names...); err != nil {
+			return nil, err
+		}
+		if err := v.BindPFlag(o.key, fs.Lookup(flagName(o.key))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := readConfigFile(v, *configFile); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{PrintConfig: *printConfig, ListWatches: *listWatches, DeleteWatches: *deleteWatches}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	cfg.Fallback = slices.DeleteFunc(cfg.Fallback, func(s string) bool { return strings.TrimSpace(s) == "" })
+	for i := range cfg.Fallback {
+		cfg.Fallback[i] = strings.TrimSpace(cfg.Fallback[i])
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func readConfigFile(v *viper.Viper, path string) error {
+	if path == "" {
+		path = os.Getenv("WEATHER_CONFIG")
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("weather")
+		v.AddConfigPath(".")
+	}
+	err := v.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: reading %s: %w", v.ConfigFileUsed(), err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	bad := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+	if !slices.Contains(transports, c.Transport) {
+		bad("transport must be one of %s, not %q", strings.Join(transports, ", "), c.Transport)
+	}
+	if c.Transport != TransportStdio {
+		if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+			bad("addr %q is not a host:port address", c.Addr)
+		}
+	}
+	if c.Transport == TransportStreamable || c.Transport == TransportServe {
+		if !strings.HasPrefix(c.Path, "/") {
+			bad("path %q must start with /", c.Path)
+		}
+	}
+	if c.Transport == TransportSSE || c.Transport == TransportServe {
+		if !strings.HasPrefix(c.SSEPath, "/") {
+			bad("sse_path %q must start with /", c.SSEPath)
+		}
+	}
+	if c.Transport == TransportServe {
+		if c.SSEPath == c.Path {
+			bad("sse_path and path must differ, both are %q", c.Path)
+		}
+	}
+	if c.ShutdownGrace < 0 {
+		bad("shutdown_grace must not be negative")
+	}
+	for key, u := range map[string]string{
+		"nws.base_url":       c.NWS.BaseURL,
+		"openmeteo.base_url": c.OpenMeteo.BaseURL,
+		"taf.base_url":       c.TAF.BaseURL,
+	} {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			bad("%s %q must be an absolute http or https URL", key, u)
+		}
+	}
+	if strings.TrimSpace(c.NWS.UserAgent) == "" {
+		bad("nws.user_agent must not be empty")
+	}
+	if c.NWS.MaxConcurrent < 1 {
+		bad("nws.max_concurrent must be at least 1")
+	}
+	if c.NWS.RequestsPerSecond <= 0 {
+		bad("nws.requests_per_second must be positive")
+	}
+	if c.NWS.PointsTTL <= 0 || c.NWS.ZonesTTL <= 0 {
+		bad("nws.points_ttl and nws.zones_ttl must be positive")
+	}
+	for _, name := range c.Fallback {
+		if !slices.Contains(providers, name) {
+			bad("fallback must list providers from %s, not %q", strings.Join(providers, ", "), name)
+		}
+	}
+	if c.Watch.Interval <= 0 {
+		bad("watch.interval must be positive")
+	}
+	if _, err := watch.ParseAllowlist(c.Watch.WebhookAllow); err != nil {
+		bad("watch.webhook_allow: %v", err)
+	}
+	if !slices.Contains(logLevels, c.Log.Level) {
+		bad("log.level must be one of %s, not %q", strings.Join(logLevels, ", "), c.Log.Level)
+	}
+	if !slices.Contains(logFormats, c.Log.Format) {
+		bad("log.format must be one of %s, not %q", strings.Join(logFormats, ", "), c.Log.Format)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("config: invalid settings:\n%w", err)
+	}
+	return nil
+}
+
+// Logger returns a logger writing to w in the configured format and level.
+func (c *Config) Logger(w io.Writer) *slog.Logger {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Log.Level))
+	opts := &slog.HandlerOptions{Level: level}
+	if c.Log.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Print writes the configuration as YAML that Load accepts as a config file.
+func (c *Config) Print(w io.Writer) error {
+	v := viper.New()
+	for key, value := range map[string]any{
+		"transport":               c.Transport,
+		"addr":                    c.Addr,
+		"path":                    c.Path,
+		"sse_path":                c.SSEPath,
+		"stdio":                   c.Stdio,
+		"shutdown_grace":          c.ShutdownGrace.String(),
+		"nws.base_url":            c.NWS.BaseURL,
+		"nws.user_agent":          c.NWS.UserAgent,
+		"nws.max_concurrent":      c.NWS.MaxConcurrent,
+		"nws.requests_per_second": c.NWS.RequestsPerSecond,
+		"nws.points_ttl":          c.NWS.PointsTTL.String(),
+		"nws.zones_ttl":           c.NWS.ZonesTTL.String(),
+		"openmeteo.base_url":      c.OpenMeteo.BaseURL,
+		"taf.base_url":            c.TAF.BaseURL,
+		"fallback":                c.Fallback,
+		"activity_profiles":       c.ActivityProfiles,
+		"gazetteer_dir":           c.GazetteerDir,
+		"zones_file":              c.ZonesFile,
+		"watch.store":             c.Watch.Store,
+		"watch.interval":          c.Watch.Interval.String(),
+		"watch.webhook_allow":     c.Watch.WebhookAllow,
+		"log.level":               c.Log.Level,
+		"log.format":              c.Log.Format,
+	} {
+		v.Set(key, value)
+	}
+	v.SetConfigType("yaml")
+	return v.WriteConfigTo(w)
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes a config file named name to a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "weather.yaml", "addr: \":9001\"\nlog:\n  level: debug\n")
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{"default", nil, "", ":8080"},
+		{"file over default", []string{"--config", file}, "", ":9001"},
+		{"env over file", []string{"--config", file}, ":9002", ":9002"},
+		{"flag over env", []string{"--config", file, "--addr", ":9003"}, ":9002", ":9003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("WEATHER_ADDR", tt.env)
+			}
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Addr != tt.want {
+				t.Errorf("Addr = %q, want %q", cfg.Addr, tt.want)
+			}
+		})
+	}
+
+	// WEATHER_CONFIG names the file when --config does not.
+	t.Setenv("WEATHER_CONFIG", file)
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9001" || cfg.Log.Level != "debug" {
+		t.Errorf("with WEATHER_CONFIG: addr %q, log level %q", cfg.Addr, cfg.Log.Level)
+	}
+}
+
+func TestLoadLegacyEnv(t *testing.T) {
+	t.Setenv("OPENMETEO_BASE_URL", "https://meteo.example.com")
+	t.Setenv("WATCH_INTERVAL", "5m")
+	t.Setenv("WATCH_STORE", "legacy.json")
+	t.Setenv("WEATHER_WATCH_STORE", "current.json")
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OpenMeteo.BaseURL != "https://meteo.example.com" || cfg.Watch.Interval != 5*time.Minute {
+		t.Errorf("legacy names ignored: openmeteo %q, interval %s", cfg.OpenMeteo.BaseURL, cfg.Watch.Interval)
+	}
+	if cfg.Watch.Store != "current.json" {
+		t.Errorf("watch.store = %q, want the WEATHER_ name to win over the legacy one", cfg.Watch.Store)
+	}
+}
+
+func TestLoadFallback(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    []string
+		wantErr string
+	}{
+		{"openmeteo, nws", []string{"openmeteo", "nws"}, ""},
+		// An empty value disables fallback.
+		{"", []string{}, ""},
+		{"bogus", nil, `not "bogus"`},
+		{"nws,darksky", nil, `not "darksky"`},
+	}
+	for _, tt := range tests {
+		t.Setenv("WEATHER_FALLBACK", tt.env)
+		cfg, err := Load(nil)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WEATHER_FALLBACK=%q: error = %v, want %s", tt.env, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("WEATHER_FALLBACK=%q: %v", tt.env, err)
+			continue
+		}
+		if len(cfg.Fallback) != len(tt.want) || (len(tt.want) > 0 && !reflect.DeepEqual(cfg.Fallback, tt.want)) {
+			t.Errorf("WEATHER_FALLBACK=%q: fallback = %q, want %q", tt.env, cfg.Fallback, tt.want)
+		}
+	}
+}
+
+func TestValidateReportsEverySetting(t *testing.T) {
+	_, err := Load([]string{"--transport", "carrier-pigeon", "--log-level", "loud", "--nws-max-concurrent", "0"})
+	if err == nil {
+		t.Fatal("Load accepted invalid settings")
+	}
+	for _, want := range []string{"transport", "log.level", "nws.max_concurrent"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error lacks %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestPrintRoundTrip(t *testing.T) {
+	cfg, err := Load([]string{
+		"--transport", "serve", "--stdio", "--addr", "127.0.0.1:9000", "--shutdown-grace", "3s",
+		"--nws-user-agent", "(test, ops@example.com)", "--nws-requests-per-second", "2.5",
+		"--fallback", "openmeteo", "--watch-webhook-allow", "10.0.0.0/8,::1", "--log-format", "json",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Load([]string{"--config", writeFile(t, "printed.yaml", buf.String())})
+	if err != nil {
+		t.Fatalf("loading the printed config: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(again, cfg) {
+		t.Errorf("printed config loads as\n%+v\nwant\n%+v\nprinted:\n%s", again, cfg, buf.String())
+	}
+}
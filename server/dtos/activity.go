@@ -0,0 +1,13 @@
+package dtos
+
+type (
+	ActivityParams struct {
+		Activity  string  `json:"activity" jsonschema:"activity profile, e.g. 'running', 'cycling', 'picnic', 'drone' or 'crane'"`
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+		Start     string  `json:"start,omitempty" jsonschema:"start of the window as RFC 3339, or YYYY-MM-DDTHH:MM in the location's local time (default now)"`
+		Hours     int     `json:"hours,omitempty" jsonschema:"length of the window in hours (default 24, max 156)"`
+	}
+)
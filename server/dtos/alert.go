@@ -1,8 +1,20 @@
 package dtos
 
+import (
+	"encoding/json"
+	"weather/server/ugc"
+	"weather/server/vtec"
+)
+
 type (
 	AlertsParams struct {
-		State string `json:"state" jsonschema:"two-letter US state code"`
+		State     string  `json:"state,omitempty" jsonschema:"two-letter US state code or marine area code (e.g. PZ, GM, LM)"`
+		Region    string  `json:"region,omitempty" jsonschema:"marine region code: AL, AT, GL, GM, PA or PI"`
+		Zone      string  `json:"zone,omitempty" jsonschema:"NWS zone or county code, e.g. PZZ530 or TXZ211"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; its state is used when state is omitted and alerts are checked against it"`
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of a point to check alerts against"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of a point to check alerts against"`
+		Format    string  `json:"format,omitempty" jsonschema:"output format: 'text' (default) or 'cap' for one CAP 1.2 XML document per alert"`
 	}
 
 	FeatureCollection struct {
@@ -10,14 +22,73 @@ type (
 	}
 
 	Feature struct {
+		ID              string    `json:"id"`
+		Geometry        *Geometry `json:"geometry"`
 		AlertProperties `json:"properties"`
 	}
 
 	AlertProperties struct {
-		Event       string `json:"event"`
-		AreaDesc    string `json:"areaDesc"`
-		Severity    string `json:"severity"`
-		Description string `json:"description"`
-		Instruction string `json:"instruction"`
+		Event         string              `json:"event"`
+		AreaDesc      string              `json:"areaDesc"`
+		Severity      string              `json:"severity"`
+		Description   string              `json:"description"`
+		Instruction   string              `json:"instruction"`
+		Urgency       string              `json:"urgency"`
+		Certainty     string              `json:"certainty"`
+		Status        string              `json:"status"`
+		Sender        string              `json:"sender"`
+		SenderName    string              `json:"senderName"`
+		Headline      string              `json:"headline"`
+		MessageType   string              `json:"messageType"`
+		Sent          string              `json:"sent"`
+		Effective     string              `json:"effective"`
+		Onset         string              `json:"onset"`
+		Expires       string              `json:"expires"`
+		Ends          string              `json:"ends"`
+		AffectedZones []string            `json:"affectedZones"`
+		Geocode       AlertGeocode        `json:"geocode"`
+		Parameters    map[string][]string `json:"parameters"`
+	}
+
+	AlertGeocode struct {
+		UGC  []string `json:"UGC"`
+		SAME []string `json:"SAME"`
+	}
+
+	// Geometry is a GeoJSON geometry object.
+	Geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates,omitempty"`
+		Geometries  []Geometry      `json:"geometries,omitempty"`
+	}
+
+	// AlertResult is the structured summary of an alert returned by tools.
+	AlertResult struct {
+		ID       string      `json:"id"`
+		Event    string      `json:"event"`
+		AreaDesc string      `json:"areaDesc"`
+		Severity string      `json:"severity"`
+		Coverage string      `json:"coverage,omitempty"`
+		Places   []ugc.Area  `json:"places,omitempty"`
+		VTEC     []vtec.VTEC `json:"vtec,omitempty"`
+	}
+
+	ZoneData struct {
+		ID         string         `json:"id"`
+		Geometry   *Geometry      `json:"geometry"`
+		Properties ZoneProperties `json:"properties"`
+	}
+
+	ZoneProperties struct {
+		ID              string   `json:"id"`
+		Name            string   `json:"name"`
+		Type            string   `json:"type"`
+		State           string   `json:"state"`
+		CWA             []string `json:"cwa"`
+		ForecastOffices []string `json:"forecastOffices"`
+	}
+
+	ZonesData struct {
+		Features []ZoneData `json:"features"`
 	}
 )
@@ -0,0 +1,37 @@
+package dtos
+
+type (
+	AstronomyParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Date      string  `json:"date,omitempty" jsonschema:"local date as YYYY-MM-DD (default today)"`
+	}
+
+	// Astronomy holds the sun and moon events for a location on one local
+	// date. Times are RFC 3339 in the location's timezone; events that do not
+	// happen that day are omitted.
+	Astronomy struct {
+		Latitude         float64 `json:"latitude"`
+		Longitude        float64 `json:"longitude"`
+		Date             string  `json:"date"`
+		TimeZone         string  `json:"timeZone"`
+		AstronomicalDawn string  `json:"astronomicalDawn,omitempty"`
+		NauticalDawn     string  `json:"nauticalDawn,omitempty"`
+		CivilDawn        string  `json:"civilDawn,omitempty"`
+		Sunrise          string  `json:"sunrise,omitempty"`
+		SolarNoon        string  `json:"solarNoon"`
+		Sunset           string  `json:"sunset,omitempty"`
+		CivilDusk        string  `json:"civilDusk,omitempty"`
+		NauticalDusk     string  `json:"nauticalDusk,omitempty"`
+		AstronomicalDusk string  `json:"astronomicalDusk,omitempty"`
+		DayLengthMinutes int     `json:"dayLengthMinutes"`
+		PolarDay         bool    `json:"polarDay,omitempty"`
+		PolarNight       bool    `json:"polarNight,omitempty"`
+		Moonrise         string  `json:"moonrise,omitempty"`
+		Moonset          string  `json:"moonset,omitempty"`
+		MoonPhase        string  `json:"moonPhase"`
+		MoonIllumination float64 `json:"moonIllumination"`
+		MoonAgeDays      float64 `json:"moonAgeDays"`
+	}
+)
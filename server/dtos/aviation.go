@@ -0,0 +1,10 @@
+package dtos
+
+type (
+	AviationParams struct {
+		Station   string  `json:"station,omitempty" jsonschema:"ICAO station identifier, e.g. KAUS; the nearest station to the location is used when omitted"`
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+	}
+)
@@ -0,0 +1,31 @@
+package dtos
+
+type (
+	WeatherIndexParams struct {
+		Temperature      float64  `json:"temperature" jsonschema:"air temperature"`
+		Unit             string   `json:"unit,omitempty" jsonschema:"unit of temperature and dewPoint: 'F' (default) or 'C'"`
+		RelativeHumidity *float64 `json:"relativeHumidity,omitempty" jsonschema:"relative humidity in percent"`
+		DewPoint         *float64 `json:"dewPoint,omitempty" jsonschema:"dew point, used when relativeHumidity is omitted"`
+		WindSpeed        *float64 `json:"windSpeed,omitempty" jsonschema:"sustained wind speed"`
+		WindUnit         string   `json:"windUnit,omitempty" jsonschema:"unit of windSpeed: 'mph' (default), 'kt', 'km/h' or 'm/s'"`
+	}
+
+	// Temperature is a temperature in both scales.
+	Temperature struct {
+		F float64 `json:"f"`
+		C float64 `json:"c"`
+	}
+
+	// WeatherIndexResult holds the values derived by compute_weather_index.
+	// Indices that do not apply to the inputs are omitted.
+	WeatherIndexResult struct {
+		Temperature         Temperature  `json:"temperature"`
+		RelativeHumidity    *float64     `json:"relativeHumidity,omitempty"`
+		DewPoint            *Temperature `json:"dewPoint,omitempty"`
+		WindSpeedMph        *float64     `json:"windSpeedMph,omitempty"`
+		HeatIndex           *Temperature `json:"heatIndex,omitempty"`
+		WindChill           *Temperature `json:"windChill,omitempty"`
+		ApparentTemperature Temperature  `json:"apparentTemperature"`
+		Notes               []string     `json:"notes,omitempty"`
+	}
+)
@@ -0,0 +1,11 @@
+package dtos
+
+type (
+	CalendarParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+		Include   string  `json:"include,omitempty" jsonschema:"what to export: 'forecast', 'alerts' or 'all' (default)"`
+	}
+)
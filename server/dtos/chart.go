@@ -0,0 +1,13 @@
+package dtos
+
+type (
+	ChartParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+		Hours     int     `json:"hours,omitempty" jsonschema:"number of hours to chart (default 48, max 156)"`
+		Format    string  `json:"format,omitempty" jsonschema:"image format: 'png' (default), 'svg' or 'both'"`
+		Sparkline bool    `json:"sparkline,omitempty" jsonschema:"also return text sparklines of each element for terminal clients"`
+	}
+)
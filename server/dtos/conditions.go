@@ -0,0 +1,76 @@
+package dtos
+
+type (
+	ConditionsParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+	}
+
+	// HourlyPeriod is one hour of a provider-independent hourly forecast.
+	// Temperatures are in °F and speeds in mph.
+	HourlyPeriod struct {
+		StartTime                  string   `json:"startTime"`
+		Temperature                float64  `json:"temperature"`
+		ProbabilityOfPrecipitation *float64 `json:"probabilityOfPrecipitation,omitempty"`
+		RelativeHumidity           *float64 `json:"relativeHumidity,omitempty"`
+		DewPoint                   *float64 `json:"dewPoint,omitempty"`
+		WindSpeed                  float64  `json:"windSpeed"`
+		WindGust                   *float64 `json:"windGust,omitempty"`
+		WindDirection              string   `json:"windDirection"`
+		Visibility                 *float64 `json:"visibility,omitempty"`
+		ShortForecast              string   `json:"shortForecast"`
+		HeatIndex                  *float64 `json:"heatIndex,omitempty"`
+		WindChill                  *float64 `json:"windChill,omitempty"`
+		ApparentTemperature        *float64 `json:"apparentTemperature,omitempty"`
+	}
+
+	// CurrentConditions is a provider-independent observation.
+	// Temperatures are in °F, speeds in mph, visibility in miles and pressure in hPa.
+	CurrentConditions struct {
+		Station             string   `json:"station,omitempty"`
+		Timestamp           string   `json:"timestamp"`
+		Description         string   `json:"description"`
+		Temperature         *float64 `json:"temperature,omitempty"`
+		DewPoint            *float64 `json:"dewPoint,omitempty"`
+		RelativeHumidity    *float64 `json:"relativeHumidity,omitempty"`
+		WindSpeed           *float64 `json:"windSpeed,omitempty"`
+		WindGust            *float64 `json:"windGust,omitempty"`
+		WindDirection       *float64 `json:"windDirection,omitempty"`
+		Visibility          *float64 `json:"visibility,omitempty"`
+		Pressure            *float64 `json:"pressure,omitempty"`
+		RawMessage          string   `json:"rawMessage,omitempty"`
+		HeatIndex           *float64 `json:"heatIndex,omitempty"`
+		WindChill           *float64 `json:"windChill,omitempty"`
+		ApparentTemperature *float64 `json:"apparentTemperature,omitempty"`
+	}
+
+	StationsData struct {
+		Features []struct {
+			Properties struct {
+				StationIdentifier string `json:"stationIdentifier"`
+				Name              string `json:"name"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+
+	ObservationData struct {
+		Properties ObservationProperties `json:"properties"`
+	}
+
+	ObservationProperties struct {
+		Station            string            `json:"station"`
+		Timestamp          string            `json:"timestamp"`
+		RawMessage         string            `json:"rawMessage"`
+		TextDescription    string            `json:"textDescription"`
+		Temperature        QuantitativeValue `json:"temperature"`
+		Dewpoint           QuantitativeValue `json:"dewpoint"`
+		WindDirection      QuantitativeValue `json:"windDirection"`
+		WindSpeed          QuantitativeValue `json:"windSpeed"`
+		WindGust           QuantitativeValue `json:"windGust"`
+		BarometricPressure QuantitativeValue `json:"barometricPressure"`
+		Visibility         QuantitativeValue `json:"visibility"`
+		RelativeHumidity   QuantitativeValue `json:"relativeHumidity"`
+	}
+)
@@ -0,0 +1,62 @@
+package dtos
+
+type (
+	FireWeatherParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Hours     int     `json:"hours,omitempty" jsonschema:"hours ahead to summarise gridpoint data for (default 24, max 72)"`
+	}
+
+	GridpointData struct {
+		Properties GridpointProperties `json:"properties"`
+	}
+
+	// GridpointProperties holds the raw forecast grid layers used by tools.
+	GridpointProperties struct {
+		Temperature        GridLayer `json:"temperature"`
+		RelativeHumidity   GridLayer `json:"relativeHumidity"`
+		WindSpeed          GridLayer `json:"windSpeed"`
+		WindGust           GridLayer `json:"windGust"`
+		HainesIndex        GridLayer `json:"hainesIndex"`
+		MixingHeight       GridLayer `json:"mixingHeight"`
+		TransportWindSpeed GridLayer `json:"transportWindSpeed"`
+	}
+
+	// GridLayer is a time series of one forecast element. Each value's
+	// validTime is an ISO 8601 interval such as "2025-08-05T18:00:00+00:00/PT3H".
+	GridLayer struct {
+		UnitCode string      `json:"uom"`
+		Values   []GridValue `json:"values"`
+	}
+
+	GridValue struct {
+		ValidTime string   `json:"validTime"`
+		Value     *float64 `json:"value"`
+	}
+
+	// FireWeatherExtreme is the most critical value of an element over the
+	// briefing window and when it occurs.
+	FireWeatherExtreme struct {
+		Value float64 `json:"value"`
+		Unit  string  `json:"unit"`
+		Time  string  `json:"time"`
+	}
+
+	// FireWeatherBriefing combines the fire weather zone forecast, fire
+	// alerts and gridpoint fire weather elements for a point.
+	FireWeatherBriefing struct {
+		Latitude            float64             `json:"latitude"`
+		Longitude           float64             `json:"longitude"`
+		FireWeatherZone     string              `json:"fireWeatherZone"`
+		Office              string              `json:"office"`
+		TimeZone            string              `json:"timeZone"`
+		Hours               int                 `json:"hours"`
+		Alerts              []AlertResult       `json:"alerts"`
+		MinRelativeHumidity *FireWeatherExtreme `json:"minRelativeHumidity,omitempty"`
+		MaxWindGust         *FireWeatherExtreme `json:"maxWindGust,omitempty"`
+		MaxHainesIndex      *FireWeatherExtreme `json:"maxHainesIndex,omitempty"`
+		MaxMixingHeight     *FireWeatherExtreme `json:"maxMixingHeight,omitempty"`
+		Forecast            *ZoneText           `json:"forecast,omitempty"`
+	}
+)
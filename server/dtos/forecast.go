@@ -2,8 +2,50 @@ package dtos
 
 type (
 	ForecastParams struct {
-		Latitude  float64 `json:"latitude" jsonschema:"latitude of the location"`
-		Longitude float64 `json:"longitude" jsonschema:"longitude of the location"`
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+	}
+
+	HourlyParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Provider  string  `json:"provider,omitempty" jsonschema:"weather provider to use: 'nws' or 'openmeteo'; chosen by location coverage when omitted"`
+		Hours     int     `json:"hours,omitempty" jsonschema:"number of hours to return (default 12, max 156)"`
+		Ensemble  bool    `json:"ensemble,omitempty" jsonschema:"merge the hourly forecasts of every provider covering the location and report their spread"`
+	}
+
+	BatchForecastParams struct {
+		Locations []Location `json:"locations" jsonschema:"locations to forecast (at most 10)"`
+		Provider  string     `json:"provider,omitempty" jsonschema:"weather provider to use for every location: 'nws' or 'openmeteo'; chosen per location when omitted"`
+		Periods   int        `json:"periods,omitempty" jsonschema:"forecast periods to return per location (default 3, max 14)"`
+	}
+
+	// Location is a point given either as coordinates or as a place name.
+	Location struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+	}
+
+	// LocationForecast is one location's result in a batch. Error is set
+	// instead of the forecast when that location failed.
+	LocationForecast struct {
+		Place        string           `json:"place,omitempty"`
+		Latitude     float64          `json:"latitude"`
+		Longitude    float64          `json:"longitude"`
+		Source       string           `json:"source,omitempty"`
+		FallbackFrom []string         `json:"fallbackFrom,omitempty"`
+		Periods      []ForecastPeriod `json:"periods,omitempty"`
+		Error        string           `json:"error,omitempty"`
+	}
+
+	// BatchForecast holds the results of a batch in request order.
+	BatchForecast struct {
+		Locations []LocationForecast `json:"locations"`
+		Failed    int                `json:"failed"`
 	}
 
 	ForecastData struct {
@@ -11,16 +53,56 @@ type (
 	}
 
 	ForecastPeriod struct {
-		Name             string `json:"name"`
-		Temperature      int    `json:"temperature"`
-		TemperatureUnit  string `json:"temperatureUnit"`
-		WindSpeed        string `json:"windSpeed"`
-		WindDirection    string `json:"windDirection"`
-		DetailedForecast string `json:"detailedForecast"`
+		Number                     int               `json:"number"`
+		Name                       string            `json:"name"`
+		StartTime                  string            `json:"startTime"`
+		EndTime                    string            `json:"endTime"`
+		IsDaytime                  bool              `json:"isDaytime"`
+		Temperature                int               `json:"temperature"`
+		TemperatureUnit            string            `json:"temperatureUnit"`
+		ProbabilityOfPrecipitation QuantitativeValue `json:"probabilityOfPrecipitation"`
+		Dewpoint                   QuantitativeValue `json:"dewpoint"`
+		RelativeHumidity           QuantitativeValue `json:"relativeHumidity"`
+		WindSpeed                  string            `json:"windSpeed"`
+		WindDirection              string            `json:"windDirection"`
+		ShortForecast              string            `json:"shortForecast"`
+		DetailedForecast           string            `json:"detailedForecast"`
 	}
 
 	PointsData struct {
-		Properties ForecastProperties `json:"properties"`
+		Properties PointProperties `json:"properties"`
+	}
+
+	PointProperties struct {
+		ForecastURL         string           `json:"forecast"`
+		ForecastHourlyURL   string           `json:"forecastHourly"`
+		ForecastGridDataURL string           `json:"forecastGridData"`
+		ForecastZone        string           `json:"forecastZone"`
+		County              string           `json:"county"`
+		FireWeatherZone     string           `json:"fireWeatherZone"`
+		CWA                 string           `json:"cwa"`
+		GridID              string           `json:"gridId"`
+		GridX               int              `json:"gridX"`
+		GridY               int              `json:"gridY"`
+		ObservationStations string           `json:"observationStations"`
+		TimeZone            string           `json:"timeZone"`
+		RadarStation        string           `json:"radarStation"`
+		RelativeLocation    RelativeLocation `json:"relativeLocation"`
+	}
+
+	RelativeLocation struct {
+		Properties struct {
+			City     string            `json:"city"`
+			State    string            `json:"state"`
+			Distance QuantitativeValue `json:"distance"`
+			Bearing  QuantitativeValue `json:"bearing"`
+		} `json:"properties"`
+	}
+
+	// QuantitativeValue is an NWS quantitative value such as {"unitCode": "wmoUnit:m", "value": 1234.5}.
+	QuantitativeValue struct {
+		UnitCode string   `json:"unitCode"`
+		Value    *float64 `json:"value"`
 	}
 
 	ForecastProperties struct {
@@ -0,0 +1,8 @@
+package dtos
+
+type (
+	GeocodeParams struct {
+		Query string `json:"query" jsonschema:"place name, county or ZIP code, e.g. 'Denver', 'Portland, ME', 'Travis County, TX' or '80202'"`
+		Limit int    `json:"limit,omitempty" jsonschema:"maximum number of candidates to return (default 5)"`
+	}
+)
@@ -0,0 +1,56 @@
+package dtos
+
+type (
+	MarineParams struct {
+		Zone      string  `json:"zone,omitempty" jsonschema:"marine zone code, e.g. PZZ530 or ANZ335"`
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of a point on the water; its marine zone is used when zone is omitted"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of a point on the water"`
+	}
+
+	ZoneForecastData struct {
+		Properties struct {
+			Updated string               `json:"updated"`
+			Periods []ZoneForecastPeriod `json:"periods"`
+		} `json:"properties"`
+	}
+
+	ZoneForecastPeriod struct {
+		Number           int    `json:"number"`
+		Name             string `json:"name"`
+		DetailedForecast string `json:"detailedForecast"`
+	}
+
+	ProductList struct {
+		Graph []struct {
+			ID           string `json:"id"`
+			IssuanceTime string `json:"issuanceTime"`
+		} `json:"@graph"`
+	}
+
+	Product struct {
+		ID            string `json:"id"`
+		ProductCode   string `json:"productCode"`
+		ProductName   string `json:"productName"`
+		IssuingOffice string `json:"issuingOffice"`
+		IssuanceTime  string `json:"issuanceTime"`
+		ProductText   string `json:"productText"`
+	}
+
+	// ZoneText is a zone's text forecast, taken from the zone forecast API or
+	// from the zone's segment of a text product.
+	ZoneText struct {
+		Zone      string               `json:"zone"`
+		Name      string               `json:"name"`
+		Office    string               `json:"office,omitempty"`
+		Source    string               `json:"source"`
+		Issued    string               `json:"issued,omitempty"`
+		Headlines []string             `json:"headlines,omitempty"`
+		Periods   []ZoneForecastPeriod `json:"periods"`
+	}
+
+	// MarineForecast is a coastal or offshore waters forecast for one zone.
+	MarineForecast struct {
+		ZoneText
+		Alerts []AlertResult `json:"alerts,omitempty"`
+	}
+)
@@ -0,0 +1,24 @@
+package dtos
+
+type (
+	LocateParams struct {
+		Latitude  float64 `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude float64 `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place     string  `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+	}
+
+	// PointLocation describes the NWS areas a coordinate falls in.
+	PointLocation struct {
+		Latitude        float64  `json:"latitude"`
+		Longitude       float64  `json:"longitude"`
+		ForecastZone    string   `json:"forecastZone"`
+		County          string   `json:"county"`
+		FireWeatherZone string   `json:"fireWeatherZone"`
+		Office          string   `json:"office"`
+		TimeZone        string   `json:"timeZone"`
+		NearestCity     string   `json:"nearestCity"`
+		NearestState    string   `json:"nearestState"`
+		DistanceKm      *float64 `json:"distanceKm,omitempty"`
+		BearingDeg      *float64 `json:"bearingDeg,omitempty"`
+	}
+)
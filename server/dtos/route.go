@@ -0,0 +1,53 @@
+package dtos
+
+type (
+	RouteWeatherParams struct {
+		Points        []Location  `json:"points,omitempty" jsonschema:"route waypoints in order (at least two), as coordinates or place names"`
+		LineString    *LineString `json:"lineString,omitempty" jsonschema:"route as a GeoJSON LineString; used instead of points"`
+		Departure     string      `json:"departure,omitempty" jsonschema:"departure time as RFC 3339, or YYYY-MM-DDTHH:MM in the start's local time (default now)"`
+		SpeedMph      float64     `json:"speedMph,omitempty" jsonschema:"average speed including stops, in mph (default 55)"`
+		IntervalMiles float64     `json:"intervalMiles,omitempty" jsonschema:"miles between forecast points (default 50; widened for long routes)"`
+	}
+
+	// LineString is a GeoJSON LineString geometry.
+	LineString struct {
+		Type        string      `json:"type" jsonschema:"must be 'LineString'"`
+		Coordinates [][]float64 `json:"coordinates" jsonschema:"positions as [longitude, latitude]"`
+	}
+
+	// RouteStop is the forecast at one point along a route at the time the
+	// traveller is expected to reach it.
+	RouteStop struct {
+		Miles     float64       `json:"miles"`
+		Latitude  float64       `json:"latitude"`
+		Longitude float64       `json:"longitude"`
+		Near      string        `json:"near,omitempty"`
+		TimeZone  string        `json:"timeZone,omitempty"`
+		Arrival   string        `json:"arrival"`
+		Source    string        `json:"source,omitempty"`
+		Forecast  *HourlyPeriod `json:"forecast,omitempty"`
+		Alerts    []string      `json:"alerts,omitempty"`
+		Error     string        `json:"error,omitempty"`
+	}
+
+	// RouteAlert is an active alert covering at least one point of a route.
+	RouteAlert struct {
+		ID       string    `json:"id"`
+		Event    string    `json:"event"`
+		Severity string    `json:"severity"`
+		Headline string    `json:"headline,omitempty"`
+		Ends     string    `json:"ends,omitempty"`
+		TimeZone string    `json:"timeZone,omitempty"`
+		Miles    []float64 `json:"miles"`
+	}
+
+	// RouteWeather is a weather timeline along a route.
+	RouteWeather struct {
+		DistanceMiles float64      `json:"distanceMiles"`
+		SpeedMph      float64      `json:"speedMph"`
+		Departure     string       `json:"departure"`
+		Arrival       string       `json:"arrival"`
+		Stops         []RouteStop  `json:"stops"`
+		Alerts        []RouteAlert `json:"alerts"`
+	}
+)
@@ -0,0 +1,26 @@
+package dtos
+
+type (
+	CreateWatchParams struct {
+		Name        string   `json:"name,omitempty" jsonschema:"short label for the watch, e.g. 'site gusts'"`
+		Latitude    float64  `json:"latitude,omitempty" jsonschema:"latitude of the location"`
+		Longitude   float64  `json:"longitude,omitempty" jsonschema:"longitude of the location"`
+		Place       string   `json:"place,omitempty" jsonschema:"US place name, county or ZIP code; used instead of latitude/longitude"`
+		Element     string   `json:"element,omitempty" jsonschema:"forecast element for a threshold watch: 'temperature', 'dew_point', 'humidity', 'wind', 'gust' or 'precipitation_chance' (°F, mph, %)"`
+		Operator    string   `json:"operator,omitempty" jsonschema:"comparison for a threshold watch: '>', '>=', '<' or '<='"`
+		Value       *float64 `json:"value,omitempty" jsonschema:"threshold value for a threshold watch"`
+		WithinHours int      `json:"withinHours,omitempty" jsonschema:"how far ahead the forecast is checked, in hours (default 24, max 156)"`
+		AlertEvents []string `json:"alertEvents,omitempty" jsonschema:"NWS alert events for an alert watch, e.g. ['Red Flag Warning', 'Tornado Warning']; used instead of element/operator/value"`
+		WebhookURL  string   `json:"webhookUrl,omitempty" jsonschema:"public http or https URL that receives a JSON POST with an Idempotency-Key header when the watch fires"`
+		OwnerToken  string   `json:"ownerToken,omitempty" jsonschema:"secret of at least 16 characters; a watch created with one outlives this session and server restarts, and any session giving the same token can list and delete it"`
+	}
+
+	WatchIDParams struct {
+		ID         string `json:"id" jsonschema:"watch ID as returned by create_watch or list_watches"`
+		OwnerToken string `json:"ownerToken,omitempty" jsonschema:"owner token the watch was created with, if any"`
+	}
+
+	ListWatchesParams struct {
+		OwnerToken string `json:"ownerToken,omitempty" jsonschema:"owner token to also list the watches created with it"`
+	}
+)
@@ -0,0 +1,173 @@
+package gazetteer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Census gazetteer files, as published by the Census Bureau at
+// https://www.census.gov/geographies/reference-files/time-series/geo/gazetteer-files.html
+// under names such as 2023_Gaz_place_national.txt.
+var censusFiles = []struct {
+	pattern string
+	kind    Kind
+	parse   func(row map[string]string) (Entry, error)
+}{
+	{"*_Gaz_place_national.txt", KindPlace, parseCensusPlace},
+	{"*_Gaz_zcta_national.txt", KindZIP, parseCensusZCTA},
+	{"*_Gaz_counties_national.txt", KindCounty, parseCensusCounty},
+}
+
+// LoadCensus replaces the embedded sample with the Census Bureau national
+// gazetteer files (places, ZCTAs and counties) found in dir. Each kind is
+// replaced only when its file is present. Populations and ZIP place names,
+// which the Census files lack, are kept from the sample where it has them.
+// Lookups already running finish with the entries they started with.
+func LoadCensus(dir string) error {
+	current := all()
+	var loaded bool
+	for _, f := range censusFiles {
+		matches, err := filepath.Glob(filepath.Join(dir, f.pattern))
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		// Names start with the vintage year, so the last match is the newest.
+		es, err := readCensus(matches[len(matches)-1], f.parse)
+		if err != nil {
+			return err
+		}
+		current = replaceKind(current, f.kind, es)
+		loaded = true
+	}
+	if !loaded {
+		return fmt.Errorf("gazetteer: no Census gazetteer files in %s", dir)
+	}
+	setEntries(current)
+	return nil
+}
+
+// replaceKind swaps the entries of one kind for es, carrying over what only
+// the old entries know.
+func replaceKind(old []Entry, kind Kind, es []Entry) []Entry {
+	known := make(map[string]Entry)
+	var out []Entry
+	for _, e := range old {
+		if e.Kind == kind {
+			known[entryKey(e)] = e
+			continue
+		}
+		out = append(out, e)
+	}
+	for _, e := range es {
+		if k, ok := known[entryKey(e)]; ok {
+			if e.Population == 0 {
+				e.Population = k.Population
+			}
+			if e.Name == "" {
+				e.Name, e.State = k.Name, k.State
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func entryKey(e Entry) string {
+	if e.Kind == KindPlace {
+		return e.State + "|" + strings.ToLower(e.Name)
+	}
+	return e.Code
+}
+
+// readCensus parses a tab-separated Census gazetteer file by its header.
+func readCensus(path string, parse func(map[string]string) (Entry, error)) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = '\t'
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading header: %w", path, err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var out []Entry
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		row := make(map[string]string, len(header))
+		for i, v := range rec {
+			if i < len(header) {
+				row[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		e, err := parse(row)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func parseCensusPlace(row map[string]string) (Entry, error) {
+	lat, lon, err := parseCoords(row["INTPTLAT"], row["INTPTLONG"])
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Kind: KindPlace, Name: placeName(row["NAME"]), State: row["USPS"], Latitude: lat, Longitude: lon}, nil
+}
+
+func parseCensusZCTA(row map[string]string) (Entry, error) {
+	lat, lon, err := parseCoords(row["INTPTLAT"], row["INTPTLONG"])
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Kind: KindZIP, Code: row["GEOID"], Latitude: lat, Longitude: lon}, nil
+}
+
+func parseCensusCounty(row map[string]string) (Entry, error) {
+	lat, lon, err := parseCoords(row["INTPTLAT"], row["INTPTLONG"])
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Kind: KindCounty, Name: row["NAME"], State: row["USPS"], Code: row["GEOID"], Latitude: lat, Longitude: lon}, nil
+}
+
+// placeName strips the legal description the Census appends to place names:
+// "Austin city" becomes "Austin", "Nashville-Davidson metropolitan
+// government (balance)" becomes "Nashville-Davidson" and "Paradise CDP"
+// becomes "Paradise".
+func placeName(name string) string {
+	words := strings.Fields(name)
+	for len(words) > 1 {
+		w := words[len(words)-1]
+		if w != "CDP" && !strings.HasPrefix(w, "(") && !unicode.IsLower([]rune(w)[0]) {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,171 @@
+// Package gazetteer resolves US place names, ZIP codes and counties to
+// coordinates offline. The embedded dataset is only a sample of major places,
+// ZIP codes and counties; LoadCensus swaps in the full Census Bureau
+// gazetteer for complete coverage.
+package gazetteer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+)
+
+//go:embed data/*.csv
+var dataFS embed.FS
+
+// Kind identifies the type of a gazetteer entry.
+type Kind string
+
+const (
+	KindPlace  Kind = "place"
+	KindZIP    Kind = "zip"
+	KindCounty Kind = "county"
+)
+
+// Entry is a single location known to the gazetteer.
+type Entry struct {
+	Kind       Kind    `json:"kind"`
+	Name       string  `json:"name"`
+	State      string  `json:"state"`
+	Code       string  `json:"code,omitempty"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Population int     `json:"population,omitempty"`
+}
+
+// Label returns a human readable description of the entry.
+func (e Entry) Label() string {
+	switch e.Kind {
+	case KindZIP:
+		if e.Name == "" {
+			return "ZIP " + e.Code
+		}
+		return fmt.Sprintf("ZIP %s (%s, %s)", e.Code, e.Name, e.State)
+	default:
+		return e.Name + ", " + e.State
+	}
+}
+
+var (
+	loadOnce  sync.Once
+	entriesMu sync.RWMutex
+	entries   []Entry
+)
+
+// all returns every entry of the dataset, loading the embedded sample on
+// first use. The slice is never modified in place, so callers may range over
+// it while LoadCensus swaps in another.
+func all() []Entry {
+	loadOnce.Do(func() {
+		var es []Entry
+		for _, load := range []func() ([]Entry, error){loadPlaces, loadZIPs, loadCounties} {
+			loaded, err := load()
+			if err != nil {
+				// The dataset is embedded at build time, so this only happens
+				// if a malformed file was committed.
+				slog.Error("failed to load gazetteer data", "error", err)
+				continue
+			}
+			es = append(es, loaded...)
+		}
+		setEntries(es)
+	})
+	entriesMu.RLock()
+	defer entriesMu.RUnlock()
+	return entries
+}
+
+func setEntries(es []Entry) {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	entries = es
+}
+
+func loadPlaces() ([]Entry, error) {
+	return readCSV("data/places.csv", func(r []string) (Entry, error) {
+		lat, lon, err := parseCoords(r[2], r[3])
+		if err != nil {
+			return Entry{}, err
+		}
+		pop, _ := strconv.Atoi(r[4])
+		return Entry{Kind: KindPlace, Name: r[0], State: r[1], Latitude: lat, Longitude: lon, Population: pop}, nil
+	})
+}
+
+func loadZIPs() ([]Entry, error) {
+	return readCSV("data/zips.csv", func(r []string) (Entry, error) {
+		lat, lon, err := parseCoords(r[3], r[4])
+		if err != nil {
+			return Entry{}, err
+		}
+		return Entry{Kind: KindZIP, Code: r[0], Name: r[1], State: r[2], Latitude: lat, Longitude: lon}, nil
+	})
+}
+
+func loadCounties() ([]Entry, error) {
+	return readCSV("data/counties.csv", func(r []string) (Entry, error) {
+		lat, lon, err := parseCoords(r[3], r[4])
+		if err != nil {
+			return Entry{}, err
+		}
+		return Entry{Kind: KindCounty, Name: r[0], State: r[1], Code: r[2], Latitude: lat, Longitude: lon}, nil
+	})
+}
+
+// readCSV parses an embedded CSV file, skipping its header row.
+func readCSV(name string, parse func([]string) (Entry, error)) ([]Entry, error) {
+	raw, err := dataFS.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(raw))
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("%s: reading header: %w", name, err)
+	}
+
+	var out []Entry
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		e, err := parse(rec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func parseCoords(lat, lon string) (float64, float64, error) {
+	la, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lo, err := strconv.ParseFloat(lon, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return la, lo, nil
+}
+
+// CountyByFIPS returns the county with the given five-digit FIPS code
+// (two-digit state code followed by three-digit county code).
+func CountyByFIPS(fips string) (Entry, bool) {
+	for _, e := range all() {
+		if e.Kind == KindCounty && e.Code == fips {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
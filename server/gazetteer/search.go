@@ -0,0 +1,222 @@
+package gazetteer
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minScore is the lowest similarity a candidate needs to be returned.
+	minScore = 0.6
+	// ambiguityMargin is how far ahead the best candidate must be of the
+	// runner-up for a lookup to be considered unambiguous.
+	ambiguityMargin = 0.05
+	// countyPenalty is applied to county matches when the query does not
+	// mention a county, so "Denver" prefers the city over Denver County.
+	countyPenalty = 0.9
+)
+
+// Candidate is a gazetteer entry matched by a query, with a similarity score
+// between 0 and 1.
+type Candidate struct {
+	Entry
+	Score float64 `json:"score"`
+}
+
+var (
+	zipPattern    = regexp.MustCompile(`^(\d{5})(-\d{4})?$`)
+	zipPrefix     = regexp.MustCompile(`^(zip|zipcode|zip code|postal code)\s*:?\s*`)
+	countyPattern = regexp.MustCompile(`\b(county|parish|borough|municipality)\b`)
+)
+
+// abbreviations are expanded in both queries and entry names before comparison.
+var abbreviations = map[string]string{
+	"st":  "saint",
+	"ste": "sainte",
+	"ft":  "fort",
+	"mt":  "mount",
+	"pt":  "point",
+	"n":   "north",
+	"s":   "south",
+	"e":   "east",
+	"w":   "west",
+}
+
+// Lookup returns up to limit candidates matching query, best first.
+// The query may be a place name ("Denver", "Portland, OR"), a county
+// ("Travis County, Texas") or a ZIP code ("80202", "ZIP 80202").
+func Lookup(query string, limit int) []Candidate {
+	q := strings.ToLower(strings.TrimSpace(query))
+	q = zipPrefix.ReplaceAllString(q, "")
+
+	var cands []Candidate
+	if m := zipPattern.FindStringSubmatch(q); m != nil {
+		cands = lookupZIP(m[1])
+	} else {
+		cands = lookupName(q)
+	}
+
+	slices.SortStableFunc(cands, func(a, b Candidate) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return b.Population - a.Population
+	})
+	if limit > 0 && len(cands) > limit {
+		cands = cands[:limit]
+	}
+	return cands
+}
+
+// Resolve looks up query and returns the best candidate when it is a clear
+// winner. Otherwise ok is false and the returned candidates should be offered
+// to the caller for disambiguation.
+func Resolve(query string) (best Candidate, cands []Candidate, ok bool) {
+	cands = Lookup(query, 5)
+	if len(cands) == 0 {
+		return Candidate{}, nil, false
+	}
+	if len(cands) > 1 && cands[0].Score-cands[1].Score < ambiguityMargin {
+		return Candidate{}, cands, false
+	}
+	return cands[0], cands, true
+}
+
+func lookupZIP(zip string) []Candidate {
+	var out []Candidate
+	for _, e := range all() {
+		if e.Kind != KindZIP {
+			continue
+		}
+		switch {
+		case e.Code == zip:
+			out = append(out, Candidate{Entry: e, Score: 1})
+		case e.Code[:3] == zip[:3]:
+			// Same sectional center: close by, but not an exact match.
+			out = append(out, Candidate{Entry: e, Score: 0.7})
+		}
+	}
+	return out
+}
+
+func lookupName(q string) []Candidate {
+	name, state := splitState(q)
+	wantCounty := countyPattern.MatchString(name)
+	name = normalize(countyPattern.ReplaceAllString(name, ""))
+	if name == "" {
+		return nil
+	}
+
+	var out []Candidate
+	for _, e := range all() {
+		if e.Kind == KindZIP {
+			continue
+		}
+		if state != "" && e.State != state {
+			continue
+		}
+
+		en := strings.ToLower(e.Name)
+		if e.Kind == KindCounty {
+			en = countyPattern.ReplaceAllString(en, "")
+		}
+
+		score := similarity(name, normalize(en))
+		if e.Kind == KindCounty && !wantCounty {
+			score *= countyPenalty
+		}
+		if e.Kind == KindPlace && wantCounty {
+			score *= countyPenalty
+		}
+		if score >= minScore {
+			out = append(out, Candidate{Entry: e, Score: score})
+		}
+	}
+	return out
+}
+
+// splitState separates a trailing state ("Austin, TX", "Austin Texas") from
+// the rest of the query. The returned state is a two-letter code or empty.
+func splitState(q string) (name, state string) {
+	if i := strings.LastIndex(q, ","); i >= 0 {
+		if code, ok := StateCode(strings.TrimSpace(q[i+1:])); ok {
+			return strings.TrimSpace(q[:i]), code
+		}
+	}
+
+	words := strings.Fields(q)
+	// Try the longest trailing state name first, e.g. "new mexico" before "mexico".
+	for n := min(3, len(words)-1); n >= 1; n-- {
+		if code, ok := StateCode(strings.Join(words[len(words)-n:], " ")); ok {
+			return strings.Join(words[:len(words)-n], " "), code
+		}
+	}
+	return q, ""
+}
+
+// normalize lowercases s, strips punctuation and expands common abbreviations.
+func normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case r == '\'':
+			return -1
+		default:
+			return ' '
+		}
+	}, s)
+
+	words := strings.Fields(s)
+	for i, w := range words {
+		if full, ok := abbreviations[w]; ok {
+			words[i] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// similarity scores how closely a matches b, from 0 to 1. Exact matches
+// score 1, prefixes of b score highly, and everything else is scored by
+// edit distance.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if strings.HasPrefix(b, a+" ") {
+		return 0.9
+	}
+
+	d := levenshtein(a, b)
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 {
+		return 0
+	}
+	return 1 - float64(d)/float64(n)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,40 @@
+package gazetteer
+
+import "strings"
+
+// states maps lowercase US state and territory names to their USPS codes.
+var states = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR",
+	"california": "CA", "colorado": "CO", "connecticut": "CT", "delaware": "DE",
+	"district of columbia": "DC", "florida": "FL", "georgia": "GA", "hawaii": "HI",
+	"idaho": "ID", "illinois": "IL", "indiana": "IN", "iowa": "IA",
+	"kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME",
+	"maryland": "MD", "massachusetts": "MA", "michigan": "MI", "minnesota": "MN",
+	"mississippi": "MS", "missouri": "MO", "montana": "MT", "nebraska": "NE",
+	"nevada": "NV", "new hampshire": "NH", "new jersey": "NJ", "new mexico": "NM",
+	"new york": "NY", "north carolina": "NC", "north dakota": "ND", "ohio": "OH",
+	"oklahoma": "OK", "oregon": "OR", "pennsylvania": "PA", "rhode island": "RI",
+	"south carolina": "SC", "south dakota": "SD", "tennessee": "TN", "texas": "TX",
+	"utah": "UT", "vermont": "VT", "virginia": "VA", "washington": "WA",
+	"west virginia": "WV", "wisconsin": "WI", "wyoming": "WY",
+	"puerto rico": "PR", "guam": "GU", "virgin islands": "VI",
+	"american samoa": "AS", "northern mariana islands": "MP",
+}
+
+// StateCode returns the two-letter USPS code for a US state or territory,
+// given either its name ("Texas") or its code ("tx").
+func StateCode(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(s, ".")))
+	if code, ok := states[s]; ok {
+		return code, true
+	}
+	if len(s) == 2 {
+		up := strings.ToUpper(s)
+		for _, code := range states {
+			if code == up {
+				return code, true
+			}
+		}
+	}
+	return "", false
+}
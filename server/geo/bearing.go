@@ -0,0 +1,11 @@
+package geo
+
+// CompassPoint converts a bearing in degrees to a 16-point compass direction.
+func CompassPoint(deg float64) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	for deg < 0 {
+		deg += 360
+	}
+	i := int((deg+11.25)/22.5) % 16
+	return points[i]
+}
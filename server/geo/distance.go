@@ -0,0 +1,65 @@
+package geo
+
+import "math"
+
+const earthRadiusMiles = 3958.8
+
+// DistanceMiles returns the great-circle distance between two points.
+func DistanceMiles(lat1, lon1, lat2, lon2 float64) float64 {
+	p1, p2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dp := p2 - p1
+	dl := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dp/2)*math.Sin(dp/2) + math.Cos(p1)*math.Cos(p2)*math.Sin(dl/2)*math.Sin(dl/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(a))
+}
+
+// Sample is a point along a path and its distance from the start.
+type Sample struct {
+	Lat, Lon float64
+	Miles    float64
+}
+
+// SamplePath returns points every interval miles along a path of positions,
+// always including the first and last. Points between vertices are
+// interpolated linearly, which is close enough over the short segments of a
+// route.
+func SamplePath(path []Position, interval float64) []Sample {
+	if len(path) == 0 {
+		return nil
+	}
+	samples := []Sample{{Lat: path[0][1], Lon: path[0][0]}}
+	travelled, next := 0.0, interval
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		seg := DistanceMiles(a[1], a[0], b[1], b[0])
+		for interval > 0 && next < travelled+seg {
+			f := (next - travelled) / seg
+			samples = append(samples, Sample{
+				Lat:   a[1] + (b[1]-a[1])*f,
+				Lon:   a[0] + (b[0]-a[0])*f,
+				Miles: next,
+			})
+			next += interval
+		}
+		travelled += seg
+	}
+
+	last := path[len(path)-1]
+	end := Sample{Lat: last[1], Lon: last[0], Miles: travelled}
+	// Avoid a sample a few miles before the end duplicating the end itself.
+	if n := len(samples); n > 1 && travelled-samples[n-1].Miles < interval/4 {
+		samples[n-1] = end
+	} else if travelled > 0 {
+		samples = append(samples, end)
+	}
+	return samples
+}
+
+// PathMiles returns the length of a path.
+func PathMiles(path []Position) float64 {
+	var total float64
+	for i := 1; i < len(path); i++ {
+		total += DistanceMiles(path[i-1][1], path[i-1][0], path[i][1], path[i][0])
+	}
+	return total
+}
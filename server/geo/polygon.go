@@ -0,0 +1,148 @@
+// Package geo implements the small amount of geometry needed to match points
+// against GeoJSON alert and zone shapes and to measure routes.
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Position is a GeoJSON position: longitude first, then latitude.
+type Position [2]float64
+
+// Ring is a closed linear ring of positions.
+type Ring []Position
+
+// Polygon is an outer ring followed by zero or more holes.
+type Polygon []Ring
+
+// Shape is a set of polygons parsed from a GeoJSON geometry.
+type Shape []Polygon
+
+// ParseShape decodes the coordinates of a GeoJSON Polygon or MultiPolygon.
+// Other geometry types carry no area and yield an empty shape.
+func ParseShape(typ string, coordinates json.RawMessage) (Shape, error) {
+	switch typ {
+	case "Polygon":
+		var p Polygon
+		if err := json.Unmarshal(coordinates, &p); err != nil {
+			return nil, fmt.Errorf("geo: decoding polygon: %w", err)
+		}
+		return Shape{p}, nil
+	case "MultiPolygon":
+		var s Shape
+		if err := json.Unmarshal(coordinates, &s); err != nil {
+			return nil, fmt.Errorf("geo: decoding multipolygon: %w", err)
+		}
+		return s, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Contains reports whether the point lies inside any polygon of the shape.
+func (s Shape) Contains(lat, lon float64) bool {
+	for _, p := range s {
+		if p.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether the point lies inside the outer ring of the
+// polygon and outside all of its holes.
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p) == 0 || !p[0].contains(lat, lon) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains implements the even-odd ray casting test.
+func (r Ring) contains(lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Span is a stretch of a path, in miles from the path's start.
+type Span struct {
+	From, To float64
+}
+
+// PathSpans returns the stretches of a path that lie inside the shape, in
+// order. Each segment is split where it crosses a ring edge and the pieces
+// whose midpoints are inside make up the spans; like SamplePath, it treats
+// segments as straight lines in longitude and latitude.
+func (s Shape) PathSpans(path []Position) []Span {
+	var (
+		spans     []Span
+		travelled float64
+	)
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		seg := DistanceMiles(a[1], a[0], b[1], b[0])
+
+		cuts := []float64{0, 1}
+		for _, p := range s {
+			for _, r := range p {
+				for j := 1; j < len(r); j++ {
+					if t, ok := intersect(a, b, r[j-1], r[j]); ok {
+						cuts = append(cuts, t)
+					}
+				}
+			}
+		}
+		slices.Sort(cuts)
+
+		for j := 1; j < len(cuts); j++ {
+			t0, t1 := cuts[j-1], cuts[j]
+			if t1-t0 < 1e-9 {
+				continue
+			}
+			mid := (t0 + t1) / 2
+			if !s.Contains(a[1]+(b[1]-a[1])*mid, a[0]+(b[0]-a[0])*mid) {
+				continue
+			}
+			from, to := travelled+t0*seg, travelled+t1*seg
+			if n := len(spans); n > 0 && from-spans[n-1].To < 1e-6 {
+				spans[n-1].To = to
+			} else {
+				spans = append(spans, Span{From: from, To: to})
+			}
+		}
+		travelled += seg
+	}
+	return spans
+}
+
+// intersect returns where along the segment a-b it crosses the segment c-d,
+// as a fraction of a-b's length. Parallel segments do not cross.
+func intersect(a, b, c, d Position) (float64, bool) {
+	rx, ry := b[0]-a[0], b[1]-a[1]
+	qx, qy := d[0]-c[0], d[1]-c[1]
+	denom := rx*qy - ry*qx
+	if denom == 0 {
+		return 0, false
+	}
+	ex, ey := c[0]-a[0], c[1]-a[1]
+	t := (ex*qy - ey*qx) / denom
+	u := (ex*ry - ey*rx) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
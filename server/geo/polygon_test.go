@@ -0,0 +1,45 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// box returns a square polygon between the given longitudes and latitudes.
+func box(west, south, east, north float64) Polygon {
+	return Polygon{Ring{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}}
+}
+
+func TestPathSpans(t *testing.T) {
+	// A path due east along latitude 30, 1° of longitude at a time.
+	path := []Position{{-100, 30}, {-99, 30}, {-98, 30}, {-97, 30}}
+	degree := DistanceMiles(30, -100, 30, -99)
+
+	tests := []struct {
+		name  string
+		shape Shape
+		want  []Span
+	}{
+		{"between vertices", Shape{box(-99.75, 29.9, -99.25, 30.1)}, []Span{{0.25 * degree, 0.75 * degree}}},
+		{"across a vertex", Shape{box(-98.5, 29.9, -97.5, 30.1)}, []Span{{1.5 * degree, 2.5 * degree}}},
+		{"covers the start", Shape{box(-101, 29, -99.5, 31)}, []Span{{0, 0.5 * degree}}},
+		{"two crossings", Shape{box(-99.9, 29.9, -99.8, 30.1), box(-97.2, 29.9, -96, 30.1)}, []Span{{0.1 * degree, 0.2 * degree}, {2.8 * degree, 3 * degree}}},
+		{"hole", Shape{append(box(-100.5, 29, -96.5, 31), box(-99, 29.5, -98, 30.5)[0])}, []Span{{0, degree}, {2 * degree, 3 * degree}}},
+		{"misses", Shape{box(-99, 31, -98, 32)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.shape.PathSpans(path)
+			if len(got) != len(tt.want) {
+				t.Fatalf("PathSpans = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				// Longitude is not quite linear in distance along a
+				// parallel, but within a mile over these lengths.
+				if math.Abs(got[i].From-tt.want[i].From) > 1 || math.Abs(got[i].To-tt.want[i].To) > 1 {
+					t.Errorf("span %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
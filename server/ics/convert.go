@@ -0,0 +1,163 @@
+package ics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/vtec"
+)
+
+// ForecastEvents converts forecast periods for a location into events. A
+// period's UID is keyed on the location and its end time: NWS moves the start
+// of the current period forward as the forecast is reissued, but "Tonight"
+// always ends at the same time. Daily periods given as a bare date, as from
+// Open-Meteo, become all-day events.
+func ForecastEvents(lat, lon float64, location string, periods []dtos.ForecastPeriod) []Event {
+	events := make([]Event, 0, len(periods))
+	for _, p := range periods {
+		var start, end time.Time
+		allDay := false
+		if day, err := time.Parse(time.DateOnly, p.StartTime); err == nil {
+			start, end, allDay = day, day.AddDate(0, 0, 1), true
+		} else {
+			start, err = time.Parse(time.RFC3339, p.StartTime)
+			if err != nil {
+				continue
+			}
+			end, err = time.Parse(time.RFC3339, p.EndTime)
+			if err != nil || !end.After(start) {
+				continue
+			}
+		}
+
+		temp := "low"
+		if p.IsDaytime {
+			temp = "high"
+		}
+		summary := fmt.Sprintf("%s %d°%s", temp, p.Temperature, p.TemperatureUnit)
+		if p.ShortForecast != "" {
+			summary = p.ShortForecast + ", " + summary
+		}
+		if p.Name != "" {
+			summary = p.Name + ": " + summary
+		}
+		desc := p.DetailedForecast
+		if p.WindSpeed != "" {
+			desc = strings.TrimSpace(desc + "\nWind: " + p.WindSpeed + " " + p.WindDirection)
+		}
+
+		events = append(events, Event{
+			UID:         uid("forecast", fmt.Sprintf("%.4f,%.4f", lat, lon), utc(end)),
+			Start:       start,
+			End:         end,
+			AllDay:      allDay,
+			Summary:     summary,
+			Description: desc,
+			Location:    location,
+			Categories:  []string{"Weather", "Forecast"},
+		})
+	}
+	return events
+}
+
+// AlertEvents converts alerts into events running from onset (or effective)
+// to end (or expiry). Alerts tracked by VTEC use the event key as their UID,
+// so extensions and cancellations update the original event; a cancelled or
+// expired hazard is marked cancelled. When several messages describe the
+// same hazard the most recently sent wins, and each event's sequence is its
+// message's sent time in minutes since the Unix epoch, so a reissue always
+// outranks what a calendar already holds.
+func AlertEvents(features []dtos.Feature) []Event {
+	var events []Event
+	sent := map[string]time.Time{}
+	keys := eventKeys(features)
+	for i, f := range features {
+		start := firstTime(f.Onset, f.Effective, f.Sent)
+		end := firstTime(f.Ends, f.Expires)
+		if start.IsZero() {
+			continue
+		}
+		if !end.After(start) {
+			end = start.Add(time.Hour)
+		}
+
+		id, cancelled := alertUID(f, keys[i])
+		sentAt := firstTime(f.Sent)
+		desc := strings.TrimSpace(strings.Join([]string{f.Headline, f.Description, f.Instruction}, "\n\n"))
+		e := Event{
+			UID:         id,
+			Start:       start,
+			End:         end,
+			Summary:     f.Event,
+			Description: desc,
+			Location:    f.AreaDesc,
+			Categories:  []string{"Weather", "Alert", f.Severity},
+			Cancelled:   cancelled,
+		}
+		if !sentAt.IsZero() {
+			e.Sequence = int(sentAt.Unix() / 60)
+		}
+		if e.Categories[2] == "" {
+			e.Categories = e.Categories[:2]
+		}
+
+		j := slices.IndexFunc(events, func(have Event) bool { return have.UID == id })
+		switch {
+		case j < 0:
+			events = append(events, e)
+			sent[id] = sentAt
+		case sentAt.After(sent[id]):
+			events[j] = e
+			sent[id] = sentAt
+		}
+	}
+	return events
+}
+
+// eventKeys returns the VTEC event key of each alert's first VTEC string, or
+// "" for alerts without one. The keys are computed together, with each
+// alert's onset, so that an update sent after New Year keeps the year of the
+// event it continues.
+func eventKeys(features []dtos.Feature) []string {
+	keys := make([]string, len(features))
+	var (
+		msgs  []vtec.Message
+		owner []int
+	)
+	for i, f := range features {
+		if vs := vtec.ParseAll(f.Parameters["VTEC"]); len(vs) > 0 {
+			msgs = append(msgs, vtec.Message{VTEC: vs[0], Sent: firstTime(f.Sent), Onset: firstTime(f.Onset, f.Effective)})
+			owner = append(owner, i)
+		}
+	}
+	for j, key := range vtec.EventKeys(msgs) {
+		keys[owner[j]] = key
+	}
+	return keys
+}
+
+// alertUID returns the UID for an alert with the given event key and whether
+// its VTEC action ends the hazard. Alerts without VTEC are keyed on a hash of
+// their ID.
+func alertUID(f dtos.Feature, key string) (string, bool) {
+	if key != "" {
+		v := vtec.ParseAll(f.Parameters["VTEC"])[0]
+		return uid("alert", key), v.IsTerminal()
+	}
+	sum := sha256.Sum256([]byte(f.ID))
+	return uid("alert", hex.EncodeToString(sum[:12])), false
+}
+
+// firstTime returns the first of the RFC 3339 timestamps that parses.
+func firstTime(values ...string) time.Time {
+	for _, v := range values {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,83 @@
+package ics
+
+import (
+	"testing"
+	"weather/server/dtos"
+)
+
+func alert(id, sent, vtecString string) dtos.Feature {
+	f := dtos.Feature{ID: id}
+	f.Event = "Winter Storm Warning"
+	f.Sent = sent
+	f.Onset = "2025-12-31T22:00:00Z"
+	f.Ends = "2026-01-01T18:00:00Z"
+	if vtecString != "" {
+		f.Parameters = map[string][]string{"VTEC": {vtecString}}
+	}
+	return f
+}
+
+func TestAlertEventsReissue(t *testing.T) {
+	issued := alert("urn:1", "2025-12-31T20:00:00Z", "/O.NEW.KBOU.WS.W.0090.251231T2200Z-260101T1800Z/")
+	continued := alert("urn:2", "2026-01-01T02:00:00Z", "/O.CON.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/")
+	cancelled := alert("urn:3", "2026-01-01T09:00:00Z", "/O.CAN.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/")
+
+	// Each fetch of the feed sees the messages current at the time.
+	fetches := [][]dtos.Feature{
+		{issued},
+		{continued, issued},
+		{cancelled, continued},
+		// The NEW message has dropped out of the feed after New Year.
+		{continued},
+	}
+	const want = "alert-2025.KBOU.WS.W.0090@weather-mcp"
+	var sequences []int
+	for i, features := range fetches {
+		events := AlertEvents(features)
+		if len(events) != 1 {
+			t.Fatalf("fetch %d: %d events, want 1", i+1, len(events))
+		}
+		if events[0].UID != want {
+			t.Errorf("fetch %d: UID %q, want %q", i+1, events[0].UID, want)
+		}
+		sequences = append(sequences, events[0].Sequence)
+	}
+	if !(sequences[0] < sequences[1] && sequences[1] < sequences[2]) {
+		t.Errorf("sequences %v do not increase with each reissue", sequences)
+	}
+	if sequences[3] != sequences[1] {
+		t.Errorf("the same message has sequences %d and %d", sequences[1], sequences[3])
+	}
+	if events := AlertEvents(fetches[2]); !events[0].Cancelled {
+		t.Error("the cancelled warning is not cancelled")
+	}
+}
+
+func TestAlertEventsWithoutVTEC(t *testing.T) {
+	a := alert("urn:oid:2.49.0.1.840.0.abc", "2025-12-31T20:00:00Z", "")
+	b := alert("urn:oid:2.49.0.1.840.0.def", "2025-12-31T20:00:00Z", "")
+	first, again := AlertEvents([]dtos.Feature{a, b}), AlertEvents([]dtos.Feature{b, a})
+	if len(first) != 2 || first[0].UID == first[1].UID {
+		t.Fatalf("events %+v, want two with distinct UIDs", first)
+	}
+	if first[0].UID != again[1].UID || first[1].UID != again[0].UID {
+		t.Error("UIDs depend on the order of the alerts")
+	}
+	// An unparseable sent time gives no sequence.
+	a.Sent = ""
+	if e := AlertEvents([]dtos.Feature{a}); e[0].Sequence != 0 {
+		t.Errorf("sequence %d without a sent time", e[0].Sequence)
+	}
+}
+
+func TestForecastEventsReissue(t *testing.T) {
+	period := func(start string) dtos.ForecastPeriod {
+		return dtos.ForecastPeriod{Name: "Tonight", StartTime: start, EndTime: "2025-08-06T06:00:00-05:00", Temperature: 78, TemperatureUnit: "F"}
+	}
+	// The reissued forecast moves the start of "Tonight" forward.
+	first := ForecastEvents(30.2672, -97.7431, "Austin, TX", []dtos.ForecastPeriod{period("2025-08-05T18:00:00-05:00")})
+	again := ForecastEvents(30.2672, -97.7431, "Austin, TX", []dtos.ForecastPeriod{period("2025-08-05T21:00:00-05:00")})
+	if len(first) != 1 || len(again) != 1 || first[0].UID != again[0].UID {
+		t.Errorf("UIDs %+v and %+v, want the same", first, again)
+	}
+}
@@ -0,0 +1,140 @@
+// Package ics writes iCalendar (RFC 5545) documents from forecast periods and
+// alerts. Event UIDs are derived from what an event describes rather than
+// from when it was fetched, so calendars that re-import or re-subscribe to a
+// document update their events instead of duplicating them.
+package ics
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MediaType is the media type of iCalendar documents.
+const MediaType = "text/calendar"
+
+// uidDomain qualifies UIDs so they do not collide with other producers'.
+const uidDomain = "weather-mcp"
+
+// Calendar is an iCalendar document. Name is shown by calendar apps that
+// subscribe to it.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT. All-day events use only the dates of Start and End, End
+// being exclusive. Cancelled events keep their UID so that a calendar holding
+// the original removes it. Sequence is the revision of the event; calendars
+// replace an event only with a higher one, and zero is not written.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+	Cancelled   bool
+}
+
+// Marshal renders the calendar, stamping every event with stamp.
+func (c *Calendar) Marshal(stamp time.Time) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) { writeLine(&b, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//weather-mcp//Weather Calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	// Ask subscribing clients to refresh hourly; forecasts change often.
+	line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	line("X-PUBLISHED-TTL", "PT1H")
+
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", utc(stamp))
+		if e.Sequence > 0 {
+			line("SEQUENCE", strconv.Itoa(e.Sequence))
+		}
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", e.End.Format("20060102"))
+		} else {
+			line("DTSTART", utc(e.Start))
+			line("DTEND", utc(e.End))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escape(c)
+			}
+			line("CATEGORIES", strings.Join(cats, ","))
+		}
+		if e.Cancelled {
+			line("STATUS", "CANCELLED")
+		}
+		// Weather should not mark anyone busy.
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a TEXT value (RFC 5545 section 3.3.11).
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeLine writes a content line folded at 75 octets, without splitting
+// UTF-8 sequences, and terminated by CRLF (RFC 5545 section 3.1).
+func writeLine(b *bytes.Buffer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+// uid builds a UID from its parts, e.g. "forecast-30.2672--97.7431-...".
+func uid(parts ...string) string {
+	return fmt.Sprintf("%s@%s", strings.Join(parts, "-"), uidDomain)
+}
@@ -0,0 +1,92 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteLineFolds(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"short", "SUMMARY:Sunny"},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("a", 63)},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("Partly sunny, with a high near 95. ", 8)},
+		// Two- and three-byte characters straddle the fold points.
+		{"multibyte", "SUMMARY:" + strings.Repeat("Mayagüez 95°F ", 12)},
+		{"cjk", "LOCATION:" + strings.Repeat("東京都", 20)},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		writeLine(&b, tt.in)
+		out := b.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%s: %q does not end in CRLF", tt.name, out)
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		for i, l := range lines {
+			if len(l) > 75 {
+				t.Errorf("%s: line %d is %d octets", tt.name, i+1, len(l))
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("%s: line %d splits a character: %q", tt.name, i+1, l)
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space", tt.name, i+1)
+			}
+		}
+		if len(tt.in) <= 75 && len(lines) != 1 {
+			t.Errorf("%s: %d octets folded into %d lines", tt.name, len(tt.in), len(lines))
+		}
+		// Unfolding removes each CRLF and the space after it.
+		if got := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); got != tt.in {
+			t.Errorf("%s: unfolds to %q", tt.name, got)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Sunny", "Sunny"},
+		{"Hot; humid, breezy", `Hot\; humid\, breezy`},
+		{`C:\temp`, `C:\\temp`},
+		{"Line one\nLine two\r\nLine three\r", `Line one\nLine two\nLine three`},
+		// Colons need no escaping in a value.
+		{"Wind: 10 mph", "Wind: 10 mph"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	start := time.Date(2025, 8, 5, 18, 0, 0, 0, time.UTC)
+	c := &Calendar{Name: "Weather: Austin, TX", Events: []Event{
+		{UID: "a@weather-mcp", Sequence: 29238840, Start: start, End: start.Add(time.Hour), Summary: "Heat Advisory", Categories: []string{"Weather", "Alert, Heat"}, Cancelled: true},
+		{UID: "b@weather-mcp", Start: start, End: start.AddDate(0, 0, 1), AllDay: true, Summary: "Sunny"},
+	}}
+	out := string(c.Marshal(start))
+	for _, want := range []string{
+		"X-WR-CALNAME:Weather: Austin\\, TX\r\n",
+		"UID:a@weather-mcp\r\nDTSTAMP:20250805T180000Z\r\nSEQUENCE:29238840\r\n",
+		"DTSTART:20250805T180000Z\r\nDTEND:20250805T190000Z\r\n",
+		"CATEGORIES:Weather,Alert\\, Heat\r\n",
+		"STATUS:CANCELLED\r\n",
+		"DTSTART;VALUE=DATE:20250805\r\nDTEND;VALUE=DATE:20250806\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar lacks %q:\n%s", want, out)
+		}
+	}
+	// A zero sequence is the default and is left out.
+	if n := strings.Count(out, "SEQUENCE:"); n != 1 {
+		t.Errorf("%d SEQUENCE lines, want 1", n)
+	}
+}
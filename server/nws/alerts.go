@@ -0,0 +1,43 @@
+package nws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"weather/server/cap"
+	"weather/server/dtos"
+	"weather/server/logger"
+)
+
+// FetchAlerts fetches alerts from url and decodes them into features. Both
+// GeoJSON feature collections and CAP 1.2 XML alerts and feeds are accepted,
+// so the same pipeline serves the NWS API, CAP feeds and local fixtures.
+func FetchAlerts(ctx context.Context, url string) ([]dtos.Feature, error) {
+	body, err := MakeNWSRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAlerts(ctx, body)
+}
+
+// DecodeAlerts decodes a GeoJSON feature collection, CAP 1.2 alerts or a CAP
+// Atom feed.
+func DecodeAlerts(ctx context.Context, body []byte) ([]dtos.Feature, error) {
+	trimmed := bytes.TrimSpace(body)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		features, err := cap.Parse(bytes.NewReader(trimmed))
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to parse CAP alert", "error", err)
+			return nil, err
+		}
+		return features, nil
+	}
+
+	data := dtos.FeatureCollection{}
+	if err := json.Unmarshal(trimmed, &data); err != nil {
+		logger.FromContext(ctx).Error("failed to parse NWS alerts response", "error", err)
+		return nil, fmt.Errorf("nws: parsing alerts response: %w", err)
+	}
+	return data.Features, nil
+}
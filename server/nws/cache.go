@@ -0,0 +1,97 @@
+package nws
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache is a small in-memory TTL cache for NWS metadata that rarely changes,
+// such as point and zone lookups. Keys come from arbitrary coordinates, so it
+// holds at most size entries and evicts the least recently used beyond that.
+type cache[V any] struct {
+	ttl     time.Duration
+	size    int
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type cacheEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+func newCache[V any](ttl time.Duration, size int) *cache[V] {
+	return &cache[V]{ttl: ttl, size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// SetTTL changes the TTL of entries stored from now on.
+func (c *cache[V]) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// Get returns the cached value for key if it has not expired.
+func (c *cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*cacheEntry[V])
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value under key for the cache's TTL, evicting the least
+// recently used entry when the cache is full.
+func (c *cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &cacheEntry[V]{key: key, value: value, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of entries held, including expired ones not yet
+// evicted.
+func (c *cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *cache[V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry[V]).key)
+}
+
+// SetCacheTTLs changes how long point and zone lookups are cached. Values of
+// zero or less keep the current TTL.
+func SetCacheTTLs(points, zones time.Duration) {
+	if points > 0 {
+		pointsCache.SetTTL(points)
+	}
+	if zones > 0 {
+		zonesCache.SetTTL(zones)
+	}
+}
@@ -0,0 +1,55 @@
+package nws
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newCache[int](time.Hour, 3)
+	for i := range 3 {
+		c.Set(fmt.Sprint(i), i)
+	}
+	// Touch "0" so "1" becomes the least recently used.
+	if _, ok := c.Get("0"); !ok {
+		t.Fatal("Get(0) missed")
+	}
+	c.Set("3", 3)
+
+	if n := c.Len(); n != 3 {
+		t.Errorf("Len() = %d, want 3", n)
+	}
+	if _, ok := c.Get("1"); ok {
+		t.Error("Get(1) hit, want it evicted")
+	}
+	for _, k := range []string{"0", "2", "3"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("Get(%s) missed", k)
+		}
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	c := newCache[string](time.Millisecond, 10)
+	c.Set("k", "v")
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Error("Get hit an expired entry")
+	}
+	if n := c.Len(); n != 0 {
+		t.Errorf("Len() = %d after expiry, want 0", n)
+	}
+}
+
+func TestCacheSetReplaces(t *testing.T) {
+	c := newCache[int](time.Hour, 2)
+	c.Set("a", 1)
+	c.Set("a", 2)
+	if v, _ := c.Get("a"); v != 2 {
+		t.Errorf("Get(a) = %d, want 2", v)
+	}
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+}
@@ -0,0 +1,17 @@
+package nws
+
+import (
+	"context"
+	"weather/server/dtos"
+)
+
+// fireWeatherProducts carry fire weather zone forecasts.
+var fireWeatherProducts = []productType{
+	{"FWF", "Fire Weather Planning Forecast"},
+}
+
+// FireWeatherForecast fetches the forecast for a fire weather zone such as
+// "CAZ211".
+func FireWeatherForecast(ctx context.Context, zoneID string) (*dtos.ZoneText, error) {
+	return zoneTextForecast(ctx, "fire", zoneID, fireWeatherProducts)
+}
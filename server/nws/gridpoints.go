@@ -0,0 +1,89 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"weather/server/dtos"
+)
+
+// Gridpoint fetches the raw forecast grid data at gridURL, the
+// forecastGridData URL from a /points lookup.
+func Gridpoint(ctx context.Context, gridURL string) (*dtos.GridpointData, error) {
+	body, err := MakeNWSRequest(ctx, gridURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dtos.GridpointData{}
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, fmt.Errorf("nws: parsing gridpoint response: %w", err)
+	}
+	return data, nil
+}
+
+// GridInterval is one value of a grid layer and the time it is valid for.
+type GridInterval struct {
+	Start time.Time
+	End   time.Time
+	Value float64
+}
+
+// Intervals expands a grid layer into its valid intervals, skipping missing
+// values and malformed times.
+func Intervals(layer dtos.GridLayer) []GridInterval {
+	out := make([]GridInterval, 0, len(layer.Values))
+	for _, v := range layer.Values {
+		if v.Value == nil {
+			continue
+		}
+		start, end, err := ParseValidTime(v.ValidTime)
+		if err != nil {
+			continue
+		}
+		out = append(out, GridInterval{Start: start, End: end, Value: *v.Value})
+	}
+	return out
+}
+
+// ParseValidTime parses an ISO 8601 interval of a start time and duration,
+// e.g. "2025-08-05T18:00:00+00:00/PT3H".
+func ParseValidTime(s string) (time.Time, time.Time, error) {
+	startStr, durStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("nws: invalid validTime %q", s)
+	}
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("nws: invalid validTime %q: %w", s, err)
+	}
+	d, err := parseDuration(durStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("nws: invalid validTime %q: %w", s, err)
+	}
+	return start, start.Add(d), nil
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?)?$`)
+
+// parseDuration parses the day/hour/minute subset of ISO 8601 durations used
+// by grid data, such as "PT3H" or "P1DT6H".
+func parseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
@@ -0,0 +1,85 @@
+package nws
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default request limits.
+const (
+	DefaultMaxConcurrent = 4
+	DefaultPerSecond     = 10
+)
+
+// requests limits the calls made to the NWS API by every tool, so concurrent
+// work such as batch forecasts stays within polite use of the shared API.
+var requests = newLimiter(DefaultMaxConcurrent, DefaultPerSecond)
+
+// SetLimits changes how many NWS requests may be in flight at once and how
+// many may start per second. Values of zero or less keep the current limit.
+func SetLimits(maxConcurrent int, perSecond float64) {
+	requests.set(maxConcurrent, perSecond)
+}
+
+// limiter bounds both the number of requests in flight and the rate at which
+// they start.
+type limiter struct {
+	mu       sync.Mutex
+	slots    chan struct{}
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(maxConcurrent int, perSecond float64) *limiter {
+	l := &limiter{}
+	l.set(maxConcurrent, perSecond)
+	return l
+}
+
+func (l *limiter) set(maxConcurrent int, perSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+}
+
+// acquire waits for a free slot and the next start time, and returns the
+// function that releases the slot. It fails only if ctx is done first.
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	l.mu.Lock()
+	slots := l.slots
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release = func() { <-slots }
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,24 @@
+package nws
+
+import (
+	"context"
+	"weather/server/dtos"
+)
+
+// marineProducts are the text products that carry marine zone forecasts:
+// coastal waters, offshore waters, Great Lakes nearshore and open lakes.
+var marineProducts = []productType{
+	{"CWF", "Coastal Waters Forecast"},
+	{"OFF", "Offshore Waters Forecast"},
+	{"NSH", "Nearshore Marine Forecast"},
+	{"GLF", "Open Lakes Forecast"},
+}
+
+// MarineForecast fetches the forecast for a marine zone such as "PZZ530".
+func MarineForecast(ctx context.Context, zoneID string) (*dtos.MarineForecast, error) {
+	text, err := zoneTextForecast(ctx, "marine", zoneID, marineProducts)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.MarineForecast{ZoneText: *text}, nil
+}
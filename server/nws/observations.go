@@ -0,0 +1,50 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"weather/server/dtos"
+)
+
+// LatestObservation fetches the most recent observation from a station,
+// e.g. LatestObservation(ctx, "KDEN").
+func LatestObservation(ctx context.Context, stationID string) (*dtos.ObservationProperties, error) {
+	body, err := MakeNWSRequest(ctx, GetLatestObservationURL(stationID))
+	if err != nil {
+		return nil, err
+	}
+
+	data := dtos.ObservationData{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("nws: parsing observation response: %w", err)
+	}
+	return &data.Properties, nil
+}
+
+// NearestStations lists the identifiers of the observation stations for a
+// coordinate, nearest first.
+func NearestStations(ctx context.Context, latitude, longitude float64) ([]string, error) {
+	point, err := ResolvePoint(ctx, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := MakeNWSRequest(ctx, point.ObservationStations)
+	if err != nil {
+		return nil, err
+	}
+	stations := dtos.StationsData{}
+	if err := json.Unmarshal(body, &stations); err != nil {
+		return nil, fmt.Errorf("nws: parsing stations response: %w", err)
+	}
+	if len(stations.Features) == 0 {
+		return nil, fmt.Errorf("nws: no observation stations near %.4f, %.4f", latitude, longitude)
+	}
+
+	ids := make([]string, 0, len(stations.Features))
+	for _, f := range stations.Features {
+		ids = append(ids, f.Properties.StationIdentifier)
+	}
+	return ids, nil
+}
@@ -0,0 +1,68 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+	"weather/server/dtos"
+	"weather/server/logger"
+)
+
+// DefaultPointsTTL is how long a resolved point is reused. Gridpoint
+// assignments only change when NWS re-grids an office, so a generous TTL is
+// safe.
+const DefaultPointsTTL = 6 * time.Hour
+
+// pointsCacheSize bounds the points cache, whose keys are arbitrary
+// coordinates from route, batch and watch lookups.
+const pointsCacheSize = 4096
+
+var pointsCache = newCache[*dtos.PointProperties](DefaultPointsTTL, pointsCacheSize)
+
+// ErrOutsideCoverage is returned by ResolvePoint for coordinates NWS does not
+// forecast for, such as locations outside the US.
+var ErrOutsideCoverage = errors.New("nws: point outside coverage")
+
+// ResolvePoint returns the NWS metadata (forecast URLs, zones, office and
+// timezone) for a coordinate. Results are cached, so tools can call it freely.
+func ResolvePoint(ctx context.Context, latitude, longitude float64) (*dtos.PointProperties, error) {
+	url := GetForecastURL(latitude, longitude)
+	log := logger.FromContext(ctx)
+
+	if p, ok := pointsCache.Get(url); ok {
+		log.Debug("points cache hit", "url", url)
+		return p, nil
+	}
+	log.Debug("points cache miss", "url", url)
+
+	body, err := MakeNWSRequest(ctx, url)
+	if err != nil {
+		var se *StatusError
+		if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %.4f, %.4f", ErrOutsideCoverage, latitude, longitude)
+		}
+		return nil, err
+	}
+
+	data := dtos.PointsData{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		log.Error("failed to parse NWS points response", "url", url, "error", err)
+		return nil, fmt.Errorf("nws: parsing points response: %w", err)
+	}
+
+	pointsCache.Set(url, &data.Properties)
+	return &data.Properties, nil
+}
+
+// ZoneID extracts the zone or county identifier (e.g. "COZ040") from an NWS
+// zone URL such as "https://api.weather.gov/zones/forecast/COZ040".
+func ZoneID(zoneURL string) string {
+	if zoneURL == "" {
+		return ""
+	}
+	return path.Base(zoneURL)
+}
@@ -0,0 +1,248 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/ugc"
+)
+
+var (
+	// ErrNoProduct is returned when an office has not issued a product type.
+	ErrNoProduct = errors.New("nws: no product issued")
+	// ErrNoZoneForecast is returned when no forecast covering a zone could be
+	// found.
+	ErrNoZoneForecast = errors.New("nws: no zone forecast found")
+)
+
+// productType is a text product code and its name.
+type productType struct{ code, name string }
+
+func GetProductsURL(productType, office string) string {
+	return fmt.Sprintf("%s/products/types/%s/locations/%s", nwsAPIBase, productType, office)
+}
+
+func GetProductURL(id string) string {
+	return fmt.Sprintf("%s/products/%s", nwsAPIBase, id)
+}
+
+// LatestProduct fetches the most recent text product of a type (e.g. "CWF"
+// for a coastal waters forecast) issued by an office.
+func LatestProduct(ctx context.Context, productType, office string) (*dtos.Product, error) {
+	body, err := MakeNWSRequest(ctx, GetProductsURL(productType, office))
+	if err != nil {
+		return nil, err
+	}
+
+	list := dtos.ProductList{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("nws: parsing product list: %w", err)
+	}
+	if len(list.Graph) == 0 {
+		return nil, fmt.Errorf("%w: %s from %s", ErrNoProduct, productType, office)
+	}
+
+	body, err = MakeNWSRequest(ctx, GetProductURL(list.Graph[0].ID))
+	if err != nil {
+		return nil, err
+	}
+	product := &dtos.Product{}
+	if err := json.Unmarshal(body, product); err != nil {
+		return nil, fmt.Errorf("nws: parsing product: %w", err)
+	}
+	return product, nil
+}
+
+// zoneTextForecast fetches the text forecast for a zone. The zone forecast
+// API is used when it has periods for the zone; otherwise the zone's segment
+// is extracted from the latest of products issued by its forecast office.
+func zoneTextForecast(ctx context.Context, zoneType, zoneID string, products []productType) (*dtos.ZoneText, error) {
+	zoneID = strings.ToUpper(zoneID)
+	log := logger.FromContext(ctx)
+
+	zone, err := Zone(ctx, GetZoneURL(zoneType, zoneID))
+	if err != nil {
+		return nil, err
+	}
+	forecast := &dtos.ZoneText{Zone: zoneID, Name: zone.Properties.Name}
+	if len(zone.Properties.CWA) > 0 {
+		forecast.Office = zone.Properties.CWA[0]
+	}
+
+	data, err := ZoneForecast(ctx, zoneType, zoneID)
+	if err == nil && len(data.Properties.Periods) > 0 {
+		forecast.Source = "NWS zone forecast"
+		forecast.Issued = data.Properties.Updated
+		forecast.Periods = data.Properties.Periods
+		return forecast, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	log.Debug("zone forecast unavailable, trying text products", "zone", zoneID, "error", err)
+
+	for _, office := range zone.Properties.CWA {
+		for _, p := range products {
+			product, err := LatestProduct(ctx, p.code, office)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				log.Debug("text product unavailable", "product", p.code, "office", office, "error", err)
+				continue
+			}
+			seg, ok := FindSegment(product.ProductText, zoneID)
+			if !ok {
+				continue
+			}
+			forecast.Office = office
+			forecast.Source = fmt.Sprintf("%s (%s%s)", p.name, p.code, office)
+			forecast.Issued = seg.Issued
+			if forecast.Issued == "" {
+				forecast.Issued = product.IssuanceTime
+			}
+			forecast.Headlines = seg.Headlines
+			forecast.Periods = seg.Periods
+			if forecast.Name == "" {
+				forecast.Name = seg.Name
+			}
+			return forecast, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoZoneForecast, zoneID)
+}
+
+// Segment is the part of a zone-segmented text product (such as a coastal
+// waters or fire weather forecast) that covers a group of zones.
+type Segment struct {
+	Zones     []string
+	Name      string
+	Issued    string
+	Headlines []string
+	Periods   []dtos.ZoneForecastPeriod
+}
+
+var (
+	// ugcLinePattern matches a UGC line, including a wrapped line holding
+	// only the DDHHMM expiry, e.g. "101100-".
+	ugcLinePattern  = regexp.MustCompile(`^(?:[A-Z]{2}[CZ])?\d{3}(?:\d{3})?[->]`)
+	issuedPattern   = regexp.MustCompile(`^\d{3,4} [AP]M [A-Z]{3,4} `)
+	periodPattern   = regexp.MustCompile(`^\.([A-Z][A-Z0-9 /]*?)\.\.\.(.*)$`)
+	headlinePattern = regexp.MustCompile(`^\.\.\.(.+?)\.\.\.$`)
+	// dotLeader separates the label and value of a row in a fire weather
+	// forecast table, e.g. "Max temperature.....95-100.".
+	dotLeader        = regexp.MustCompile(`\.{4,}`)
+	segmentSeparator = "$$"
+)
+
+// FindSegment returns the segment of a product's text that covers zoneID.
+func FindSegment(text, zoneID string) (*Segment, bool) {
+	zoneID = strings.ToUpper(zoneID)
+	for _, raw := range strings.Split(text, segmentSeparator) {
+		seg := parseSegment(raw)
+		if seg != nil && slices.Contains(seg.Zones, zoneID) {
+			return seg, true
+		}
+	}
+	return nil, false
+}
+
+// parseSegment parses one segment: its UGC lines, the zone names (ending in
+// "-"), the issuance time, then headlines ("...ADVISORY IN EFFECT...") and
+// forecast periods (".TONIGHT...").
+func parseSegment(raw string) *Segment {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r", ""), "\n")
+
+	// Skip the product header before the first UGC line.
+	i := 0
+	for i < len(lines) && !ugcLinePattern.MatchString(strings.TrimSpace(lines[i])) {
+		i++
+	}
+	var ugcLine string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !ugcLinePattern.MatchString(line) {
+			break
+		}
+		ugcLine += line
+	}
+	if ugcLine == "" {
+		return nil
+	}
+	seg := &Segment{Zones: ugc.Expand(ugcLine)}
+
+	// Zone names end in "-"; a name too long for one line wraps onto the
+	// next.
+	var names string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if issuedPattern.MatchString(line) {
+			seg.Issued = line
+			i++
+			break
+		}
+		if line == "" {
+			continue
+		}
+		if names != "" && !strings.HasSuffix(names, "-") {
+			names += " "
+		}
+		names += line
+	}
+	seg.Name = strings.TrimSuffix(names, "-")
+
+	var (
+		current  *dtos.ZoneForecastPeriod
+		headline string
+	)
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		// Headlines may wrap over several lines.
+		if headline != "" || (strings.HasPrefix(line, "...") && !periodPattern.MatchString(line)) {
+			headline = strings.TrimSpace(headline + " " + line)
+			if m := headlinePattern.FindStringSubmatch(headline); m != nil {
+				seg.Headlines = append(seg.Headlines, m[1])
+				headline = ""
+			} else if line == "" {
+				headline = ""
+			}
+			current = nil
+			continue
+		}
+		switch {
+		case line == "":
+			// A fire weather period's table starts after a blank line.
+			if current != nil && current.DetailedForecast != "" {
+				current = nil
+			}
+		case periodPattern.MatchString(line):
+			m := periodPattern.FindStringSubmatch(line)
+			seg.Periods = append(seg.Periods, dtos.ZoneForecastPeriod{
+				Number:           len(seg.Periods) + 1,
+				Name:             titleCase(m[1]),
+				DetailedForecast: strings.TrimSpace(m[2]),
+			})
+			current = &seg.Periods[len(seg.Periods)-1]
+		case current != nil:
+			line = dotLeader.ReplaceAllString(line, ": ")
+			current.DetailedForecast = strings.TrimSpace(current.DetailedForecast + " " + line)
+		}
+	}
+	return seg
+}
+
+// titleCase turns a period name such as "TONIGHT" or "WED NIGHT" into
+// "Tonight" or "Wed Night".
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,159 @@
+package nws
+
+import (
+	"slices"
+	"testing"
+)
+
+// cwfHGX is a coastal waters forecast with a synopsis segment, a UGC header
+// whose expiry wraps onto its own line, zone names over several lines, a
+// wrapped headline and wrapped periods.
+const cwfHGX = `000
+FZUS54 KHGX 100845
+CWFHGX
+
+Coastal Waters Forecast for Texas
+National Weather Service Houston/Galveston TX
+345 AM CDT Sun Aug 10 2025
+
+Upper Texas coastal waters from High Island to the Matagorda Ship
+Channel out 60 nm.
+
+Seas are stated as significant wave height, which is the average
+height of the highest 1/3 of the waves.
+
+GMZ300-101700-
+Synopsis for High Island to Matagorda Ship Channel out 60 NM-
+345 AM CDT Sun Aug 10 2025
+
+.SYNOPSIS...Moderate onshore winds persist through Monday.
+
+$$
+
+GMZ330-335-350-355-370-375-
+101700-
+Matagorda Bay-Galveston Bay-
+Coastal waters from Freeport to the Matagorda Ship Channel out
+20 NM-
+345 AM CDT Sun Aug 10 2025
+
+...SMALL CRAFT SHOULD EXERCISE CAUTION THROUGH THIS
+AFTERNOON...
+
+.TODAY...South winds 15 to 20 knots. Seas 3 to 5 feet. Slight
+chance of showers and thunderstorms.
+.TONIGHT...South winds 10 to 15 knots. Seas 2 to 4 feet.
+.MON NIGHT...
+Southwest winds around 10 knots.
+
+$$
+`
+
+// fwfEWX is a fire weather planning forecast whose periods are tables, with
+// a zone range in the UGC header.
+const fwfEWX = `FNUS54 KEWX 101930
+FWFEWX
+
+Fire Weather Planning Forecast for South Central Texas
+National Weather Service Austin/San Antonio TX
+230 PM CDT Sun Aug 10 2025
+
+.DISCUSSION...Hot and dry conditions continue through Monday.
+
+TXZ171-172-183>185-
+110930-
+Llano-Burnet-Gillespie-Kendall-Blanco-
+230 PM CDT Sun Aug 10 2025
+
+...RED FLAG WARNING IN EFFECT FROM NOON TO 8 PM CDT MONDAY FOR
+LOW RELATIVE HUMIDITY AND STRONG WINDS...
+
+.TONIGHT...
+
+Sky/weather...........Clear.
+Min temperature.......70-75.
+Max humidity..........65-75 percent.
+
+.MONDAY...
+
+Sky/weather...........Sunny.
+Max temperature.......100-105.
+
+$$
+`
+
+func TestFindSegment(t *testing.T) {
+	tests := []struct {
+		name, text, zone string
+		wantName         string
+		wantHeadlines    []string
+		wantPeriods      [][2]string // name, detail
+	}{
+		{
+			name: "CWF synopsis", text: cwfHGX, zone: "GMZ300",
+			wantName:    "Synopsis for High Island to Matagorda Ship Channel out 60 NM",
+			wantPeriods: [][2]string{{"Synopsis", "Moderate onshore winds persist through Monday."}},
+		},
+		{
+			name: "CWF wrapped header", text: cwfHGX, zone: "gmz375",
+			wantName:      "Matagorda Bay-Galveston Bay-Coastal waters from Freeport to the Matagorda Ship Channel out 20 NM",
+			wantHeadlines: []string{"SMALL CRAFT SHOULD EXERCISE CAUTION THROUGH THIS AFTERNOON"},
+			wantPeriods: [][2]string{
+				{"Today", "South winds 15 to 20 knots. Seas 3 to 5 feet. Slight chance of showers and thunderstorms."},
+				{"Tonight", "South winds 10 to 15 knots. Seas 2 to 4 feet."},
+				{"Mon Night", "Southwest winds around 10 knots."},
+			},
+		},
+		{
+			name: "FWF tables", text: fwfEWX, zone: "TXZ184",
+			wantName:      "Llano-Burnet-Gillespie-Kendall-Blanco",
+			wantHeadlines: []string{"RED FLAG WARNING IN EFFECT FROM NOON TO 8 PM CDT MONDAY FOR LOW RELATIVE HUMIDITY AND STRONG WINDS"},
+			wantPeriods: [][2]string{
+				{"Tonight", "Sky/weather: Clear. Min temperature: 70-75. Max humidity: 65-75 percent."},
+				{"Monday", "Sky/weather: Sunny. Max temperature: 100-105."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		seg, ok := FindSegment(tt.text, tt.zone)
+		if !ok {
+			t.Errorf("%s: no segment for %s", tt.name, tt.zone)
+			continue
+		}
+		if seg.Name != tt.wantName {
+			t.Errorf("%s: name = %q, want %q", tt.name, seg.Name, tt.wantName)
+		}
+		if seg.Issued == "" {
+			t.Errorf("%s: no issuance time", tt.name)
+		}
+		if !slices.Equal(seg.Headlines, tt.wantHeadlines) {
+			t.Errorf("%s: headlines = %q, want %q", tt.name, seg.Headlines, tt.wantHeadlines)
+		}
+		if len(seg.Periods) != len(tt.wantPeriods) {
+			t.Errorf("%s: %d periods, want %d: %+v", tt.name, len(seg.Periods), len(tt.wantPeriods), seg.Periods)
+			continue
+		}
+		for i, p := range seg.Periods {
+			if p.Name != tt.wantPeriods[i][0] || p.DetailedForecast != tt.wantPeriods[i][1] {
+				t.Errorf("%s: period %d = %q %q, want %q %q", tt.name, i+1, p.Name, p.DetailedForecast, tt.wantPeriods[i][0], tt.wantPeriods[i][1])
+			}
+		}
+	}
+
+	if _, ok := FindSegment(cwfHGX, "GMZ355"); !ok {
+		t.Error("GMZ355, listed before the wrapped expiry, was not found")
+	}
+	if _, ok := FindSegment(fwfEWX, "TXZ186"); ok {
+		t.Error("found TXZ186, outside the range 183>185")
+	}
+}
+
+func TestParseSegmentExpiryLine(t *testing.T) {
+	seg := parseSegment("GMZ330-335-\n101700-\nMatagorda Bay-Galveston Bay-\n345 AM CDT Sun Aug 10 2025\n")
+	if seg == nil || !slices.Equal(seg.Zones, []string{"GMZ330", "GMZ335"}) || seg.Name != "Matagorda Bay-Galveston Bay" {
+		t.Errorf("parseSegment = %+v", seg)
+	}
+	if parseSegment("Coastal Waters Forecast for Texas\n") != nil {
+		t.Error("parseSegment found UGC lines in a product header")
+	}
+}
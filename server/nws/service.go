@@ -1,40 +1,136 @@
 package nws
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+	"weather/server/logger"
 )
 
 const (
-	nwsAPIBase = "https://api.weather.gov"
-	userAgent  = "weather-app/1.0"
+	DefaultBaseURL   = "https://api.weather.gov"
+	DefaultUserAgent = "weather-app/1.0"
+)
+
+var (
+	nwsAPIBase = DefaultBaseURL
+	userAgent  = DefaultUserAgent
+)
+
+// SetBaseURL points requests at another NWS-compatible API, such as a
+// caching proxy. URLs already taken from NWS responses are unaffected.
+func SetBaseURL(baseURL string) {
+	nwsAPIBase = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetUserAgent sets the User-Agent sent with every request. NWS asks for one
+// that identifies the application and a contact.
+func SetUserAgent(ua string) {
+	userAgent = ua
+}
+
+// StatusError is returned when the NWS API answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("nws: unexpected status %d %s for %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
+
+// Retries of failed NWS requests.
+const (
+	maxAttempts  = 3
+	retryBackoff = 500 * time.Millisecond
 )
 
 // MakeNWSRequest sends a GET request to the specified NWS API URL.
-func MakeNWSRequest(url string) ([]byte, error) {
+// The request is aborted as soon as ctx is cancelled, so a cancelled tool call
+// does not keep waiting on the upstream API. Requests wait their turn under
+// the shared limiter (see SetLimits). Network errors, 429 and 5xx answers are
+// retried with backoff, and each retry is logged.
+func MakeNWSRequest(ctx context.Context, url string) ([]byte, error) {
+	log := logger.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		body, retry, err := doNWSRequest(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if !retry || attempt == maxAttempts || ctx.Err() != nil {
+			log.Error("NWS request failed", "url", url, "attempts", attempt, "error", err)
+			return nil, err
+		}
+
+		wait := retryBackoff << (attempt - 1)
+		log.Warn("retrying NWS request", "url", url, "attempt", attempt+1, "wait", wait, "error", err)
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// doNWSRequest makes one attempt at a request and reports whether a failure
+// is worth retrying.
+func doNWSRequest(ctx context.Context, url string) (body []byte, retry bool, err error) {
+	release, err := requests.acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
 	client := http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "application/geo+json")
+	req.Header.Set("Accept", "application/geo+json, application/cap+xml;q=0.9")
+
+	logger.FromContext(ctx).Debug("NWS request", "url", url)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retry, &StatusError{StatusCode: resp.StatusCode, URL: url}
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	return body, err != nil && ctx.Err() == nil, err
 }
 
 func GetAlertsURL(state string) string {
 	return fmt.Sprintf("%s/alerts/active/area/%s", nwsAPIBase, state)
 }
 
+func GetRegionAlertsURL(region string) string {
+	return fmt.Sprintf("%s/alerts/active/region/%s", nwsAPIBase, region)
+}
+
+func GetZoneAlertsURL(zoneID string) string {
+	return fmt.Sprintf("%s/alerts/active/zone/%s", nwsAPIBase, zoneID)
+}
+
 func GetForecastURL(latitude, longitude float64) string {
 	return fmt.Sprintf("%s/points/%.4f,%.4f", nwsAPIBase, latitude, longitude)
 }
+
+func GetPointAlertsURL(latitude, longitude float64) string {
+	return fmt.Sprintf("%s/alerts/active?point=%.4f,%.4f", nwsAPIBase, latitude, longitude)
+}
+
+func GetLatestObservationURL(stationID string) string {
+	return fmt.Sprintf("%s/stations/%s/observations/latest", nwsAPIBase, stationID)
+}
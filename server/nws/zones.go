@@ -0,0 +1,104 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"weather/server/dtos"
+	"weather/server/logger"
+)
+
+// DefaultZonesTTL is how long zone geometry is reused. Zone shapes are only
+// revised a few times a year.
+const DefaultZonesTTL = 24 * time.Hour
+
+// zonesCacheSize bounds the zones cache. Zone geometries can be large, and
+// there are a few thousand forecast, county and marine zones in all.
+const zonesCacheSize = 1024
+
+var zonesCache = newCache[*dtos.ZoneData](DefaultZonesTTL, zonesCacheSize)
+
+// GetZoneURL returns the URL of a zone, e.g. GetZoneURL("county", "TXC453").
+func GetZoneURL(zoneType, id string) string {
+	return fmt.Sprintf("%s/zones/%s/%s", nwsAPIBase, zoneType, id)
+}
+
+// GetZoneForecastURL returns the URL of a zone's text forecast.
+func GetZoneForecastURL(zoneType, id string) string {
+	return fmt.Sprintf("%s/zones/%s/%s/forecast", nwsAPIBase, zoneType, id)
+}
+
+// GetZonesAtPointURL returns the URL listing the zones of a type that
+// contain a point.
+func GetZonesAtPointURL(zoneType string, latitude, longitude float64) string {
+	return fmt.Sprintf("%s/zones?type=%s&point=%.4f,%.4f", nwsAPIBase, zoneType, latitude, longitude)
+}
+
+// ZoneGeometry returns the geometry of the zone at zoneURL, as listed in an
+// alert's affectedZones (".../zones/{type}/{id}"). Results are cached.
+func ZoneGeometry(ctx context.Context, zoneURL string) (*dtos.Geometry, error) {
+	zone, err := Zone(ctx, zoneURL)
+	if err != nil {
+		return nil, err
+	}
+	return zone.Geometry, nil
+}
+
+// Zone returns the zone at zoneURL, including its name and forecast
+// offices. Results are cached.
+func Zone(ctx context.Context, zoneURL string) (*dtos.ZoneData, error) {
+	log := logger.FromContext(ctx)
+
+	if z, ok := zonesCache.Get(zoneURL); ok {
+		log.Debug("zone cache hit", "url", zoneURL)
+		return z, nil
+	}
+	log.Debug("zone cache miss", "url", zoneURL)
+
+	body, err := MakeNWSRequest(ctx, zoneURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dtos.ZoneData{}
+	if err := json.Unmarshal(body, data); err != nil {
+		log.Error("failed to parse NWS zone response", "url", zoneURL, "error", err)
+		return nil, fmt.Errorf("nws: parsing zone response: %w", err)
+	}
+
+	zonesCache.Set(zoneURL, data)
+	return data, nil
+}
+
+// ZoneAtPoint returns the ID of the zone of the given type containing a
+// point, e.g. the marine zone for a spot offshore.
+func ZoneAtPoint(ctx context.Context, zoneType string, latitude, longitude float64) (string, error) {
+	body, err := MakeNWSRequest(ctx, GetZonesAtPointURL(zoneType, latitude, longitude))
+	if err != nil {
+		return "", err
+	}
+
+	data := dtos.ZonesData{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("nws: parsing zones response: %w", err)
+	}
+	if len(data.Features) == 0 {
+		return "", fmt.Errorf("nws: no %s zone at %.4f, %.4f", zoneType, latitude, longitude)
+	}
+	return data.Features[0].Properties.ID, nil
+}
+
+// ZoneForecast fetches a zone's text forecast periods.
+func ZoneForecast(ctx context.Context, zoneType, id string) (*dtos.ZoneForecastData, error) {
+	body, err := MakeNWSRequest(ctx, GetZoneForecastURL(zoneType, id))
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dtos.ZoneForecastData{}
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, fmt.Errorf("nws: parsing zone forecast response: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,43 @@
+// Package pool runs indexed work on a bounded number of goroutines.
+package pool
+
+import "context"
+
+// Run calls work for each index in [0, n) on at most workers goroutines,
+// and calls done from the calling goroutine as each index finishes, so done
+// may report progress without locking. It returns ctx.Err() if ctx is
+// cancelled before all work has finished; work still running is left to
+// notice the cancellation itself.
+func Run(ctx context.Context, n, workers int, work func(i int), done func(i int)) error {
+	jobs := make(chan int)
+	// finished is buffered so workers never block once the caller has gone.
+	finished := make(chan int, n)
+	for range min(workers, n) {
+		go func() {
+			for i := range jobs {
+				work(i)
+				finished <- i
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range n {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range n {
+		select {
+		case i := <-finished:
+			done(i)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/nws"
+)
+
+// Chain tries its providers in order, moving on to the next one when a
+// provider cannot serve the request: the point is outside its coverage,
+// it does not offer the product, or its upstream API is failing.
+type Chain struct {
+	providers []Provider
+}
+
+// NewChain creates a fallback chain over providers, in order of preference.
+func NewChain(providers ...Provider) *Chain {
+	return &Chain{providers: providers}
+}
+
+func (c *Chain) Name() string {
+	names := make([]string, 0, len(c.providers))
+	for _, p := range c.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ">")
+}
+
+func (c *Chain) Covers(lat, lon float64) bool {
+	for _, p := range c.providers {
+		if p.Covers(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Chain) Forecast(ctx context.Context, lat, lon float64) (*Forecast, error) {
+	f, skipped, err := try(ctx, c, func(p Provider) (*Forecast, error) { return p.Forecast(ctx, lat, lon) })
+	if err != nil {
+		return nil, err
+	}
+	f.FallbackFrom = skipped
+	return f, nil
+}
+
+func (c *Chain) Hourly(ctx context.Context, lat, lon float64) (*Hourly, error) {
+	h, skipped, err := try(ctx, c, func(p Provider) (*Hourly, error) { return p.Hourly(ctx, lat, lon) })
+	if err != nil {
+		return nil, err
+	}
+	h.FallbackFrom = skipped
+	return h, nil
+}
+
+func (c *Chain) Current(ctx context.Context, lat, lon float64) (*Conditions, error) {
+	cond, skipped, err := try(ctx, c, func(p Provider) (*Conditions, error) { return p.Current(ctx, lat, lon) })
+	if err != nil {
+		return nil, err
+	}
+	cond.FallbackFrom = skipped
+	return cond, nil
+}
+
+func (c *Chain) Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error) {
+	alerts, _, err := try(ctx, c, func(p Provider) ([]dtos.Feature, error) { return p.Alerts(ctx, lat, lon) })
+	return alerts, err
+}
+
+// try calls fetch on each provider until one succeeds or fails with an error
+// that should not be retried elsewhere. It returns the names of the
+// providers that were skipped.
+func try[T any](ctx context.Context, c *Chain, fetch func(Provider) (T, error)) (T, []string, error) {
+	var (
+		zero    T
+		skipped []string
+		errs    []error
+	)
+	for _, p := range c.providers {
+		v, err := fetch(p)
+		if err == nil {
+			return v, skipped, nil
+		}
+		if !shouldFallback(ctx, err) {
+			return zero, skipped, err
+		}
+		logger.FromContext(ctx).Warn("provider failed, falling back", "provider", p.Name(), "error", err)
+		skipped = append(skipped, p.Name())
+		errs = append(errs, err)
+	}
+	return zero, skipped, errors.Join(errs...)
+}
+
+// shouldFallback reports whether err means another provider may succeed.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ErrNoCoverage) || errors.Is(err, ErrNotSupported) || errors.Is(err, nws.ErrOutsideCoverage) {
+		return true
+	}
+
+	var nse *nws.StatusError
+	if errors.As(err, &nse) {
+		return nse.StatusCode >= 500
+	}
+	var pse *StatusError
+	if errors.As(err, &pse) {
+		return pse.StatusCode >= 500
+	}
+
+	// Transport failures (DNS, connection refused, timeouts) mean the
+	// upstream is unreachable.
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Spread summarises one value across ensemble members.
+type Spread struct {
+	Mean    float64            `json:"mean"`
+	Min     float64            `json:"min"`
+	Max     float64            `json:"max"`
+	Spread  float64            `json:"spread"`
+	Members map[string]float64 `json:"members"`
+}
+
+// EnsembleHour is one hour of a merged forecast.
+type EnsembleHour struct {
+	StartTime                  string  `json:"startTime"`
+	Temperature                Spread  `json:"temperature"`
+	ProbabilityOfPrecipitation *Spread `json:"probabilityOfPrecipitation,omitempty"`
+
+	start time.Time
+}
+
+// Start returns the hour's start time.
+func (h EnsembleHour) Start() time.Time { return h.start }
+
+// EnsembleHourly merges the hourly forecasts of several providers.
+type EnsembleHourly struct {
+	Sources []string          `json:"sources"`
+	Failed  map[string]string `json:"failed,omitempty"`
+	Hours   []EnsembleHour    `json:"hours"`
+}
+
+// Ensemble fetches the hourly forecast from every provider concurrently and
+// merges temperature and precipitation chance hour by hour, reporting the
+// spread between members. Providers that fail are listed in Failed; an error
+// is returned only when all of them fail.
+func Ensemble(ctx context.Context, providers []Provider, lat, lon float64) (*EnsembleHourly, error) {
+	results := make([]*Hourly, len(providers))
+	errs := make([]error, len(providers))
+
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.Hourly(ctx, lat, lon)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	type members struct {
+		start time.Time
+		temp  map[string]float64
+		pop   map[string]float64
+	}
+	byHour := map[int64]*members{}
+	out := &EnsembleHourly{}
+	for i, h := range results {
+		name := providers[i].Name()
+		if errs[i] != nil {
+			if out.Failed == nil {
+				out.Failed = map[string]string{}
+			}
+			out.Failed[name] = errs[i].Error()
+			continue
+		}
+		out.Sources = append(out.Sources, h.Source)
+		for _, period := range h.Periods {
+			start, err := time.Parse(time.RFC3339, period.StartTime)
+			if err != nil {
+				continue
+			}
+			key := start.Truncate(time.Hour).Unix()
+			m := byHour[key]
+			if m == nil {
+				m = &members{start: start, temp: map[string]float64{}, pop: map[string]float64{}}
+				byHour[key] = m
+			}
+			m.temp[h.Source] = period.Temperature
+			if period.ProbabilityOfPrecipitation != nil {
+				m.pop[h.Source] = *period.ProbabilityOfPrecipitation
+			}
+		}
+	}
+	if len(out.Sources) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	keys := make([]int64, 0, len(byHour))
+	for k := range byHour {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		m := byHour[k]
+		hour := EnsembleHour{
+			StartTime:   m.start.Format(time.RFC3339),
+			Temperature: spread(m.temp),
+			start:       m.start,
+		}
+		if len(m.pop) > 0 {
+			pop := spread(m.pop)
+			hour.ProbabilityOfPrecipitation = &pop
+		}
+		out.Hours = append(out.Hours, hour)
+	}
+	return out, nil
+}
+
+func spread(values map[string]float64) Spread {
+	s := Spread{Min: math.Inf(1), Max: math.Inf(-1), Members: values}
+	for _, v := range values {
+		s.Mean += v
+		s.Min = min(s.Min, v)
+		s.Max = max(s.Max, v)
+	}
+	s.Mean /= float64(len(values))
+	s.Spread = s.Max - s.Min
+	return s
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"weather/server/dtos"
+	"weather/server/nws"
+	"weather/server/weathercalc"
+)
+
+// NWS serves data from the US National Weather Service API.
+type NWS struct{}
+
+// NewNWS creates the NWS provider.
+func NewNWS() *NWS {
+	return &NWS{}
+}
+
+func (p *NWS) Name() string { return "nws" }
+
+// coverage approximates the areas NWS forecasts for: the contiguous US,
+// Alaska, Hawaii, Puerto Rico and the Virgin Islands, Guam and the Northern
+// Marianas, and American Samoa. Boxes overlap Canada and Mexico slightly;
+// the /points lookup is the authoritative check.
+var coverage = []struct{ minLat, maxLat, minLon, maxLon float64 }{
+	{24.0, 50.0, -125.0, -66.5},
+	{51.0, 72.0, -180.0, -129.0},
+	{51.0, 55.0, 172.0, 180.0},
+	{18.5, 22.5, -161.0, -154.5},
+	{17.5, 18.6, -67.5, -64.5},
+	{13.0, 21.0, 144.0, 146.5},
+	{-15.0, -11.0, -171.5, -168.0},
+}
+
+func (p *NWS) Covers(lat, lon float64) bool {
+	for _, b := range coverage {
+		if lat >= b.minLat && lat <= b.maxLat && lon >= b.minLon && lon <= b.maxLon {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *NWS) Forecast(ctx context.Context, lat, lon float64) (*Forecast, error) {
+	data, err := p.fetchForecast(ctx, lat, lon, func(pt *dtos.PointProperties) string { return pt.ForecastURL })
+	if err != nil {
+		return nil, err
+	}
+	return &Forecast{Source: p.Name(), Periods: data.Properties.Periods}, nil
+}
+
+func (p *NWS) Hourly(ctx context.Context, lat, lon float64) (*Hourly, error) {
+	data, err := p.fetchForecast(ctx, lat, lon, func(pt *dtos.PointProperties) string { return pt.ForecastHourlyURL })
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hourly{Source: p.Name(), Periods: make([]dtos.HourlyPeriod, 0, len(data.Properties.Periods))}
+	for _, period := range data.Properties.Periods {
+		temp := float64(period.Temperature)
+		if period.TemperatureUnit == "C" {
+			temp = weathercalc.CelsiusToFahrenheit(temp)
+		}
+		h.Periods = append(h.Periods, dtos.HourlyPeriod{
+			StartTime:                  period.StartTime,
+			Temperature:                temp,
+			ProbabilityOfPrecipitation: convert(period.ProbabilityOfPrecipitation),
+			RelativeHumidity:           convert(period.RelativeHumidity),
+			DewPoint:                   convert(period.Dewpoint),
+			WindSpeed:                  parseWindSpeed(period.WindSpeed),
+			WindDirection:              period.WindDirection,
+			ShortForecast:              period.ShortForecast,
+		})
+	}
+	return h, nil
+}
+
+func (p *NWS) Current(ctx context.Context, lat, lon float64) (*Conditions, error) {
+	stations, err := nws.NearestStations(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	stationID := stations[0]
+	reportStep(ctx, "found station "+stationID)
+
+	obs, err := nws.LatestObservation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	reportStep(ctx, "fetched observation")
+
+	return &Conditions{
+		Source: p.Name(),
+		CurrentConditions: dtos.CurrentConditions{
+			Station:          stationID,
+			Timestamp:        obs.Timestamp,
+			Description:      obs.TextDescription,
+			Temperature:      convert(obs.Temperature),
+			DewPoint:         convert(obs.Dewpoint),
+			RelativeHumidity: convert(obs.RelativeHumidity),
+			WindSpeed:        convert(obs.WindSpeed),
+			WindGust:         convert(obs.WindGust),
+			WindDirection:    convert(obs.WindDirection),
+			Visibility:       convert(obs.Visibility),
+			Pressure:         convert(obs.BarometricPressure),
+			RawMessage:       obs.RawMessage,
+		},
+	}, nil
+}
+
+func (p *NWS) Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error) {
+	return nws.FetchAlerts(ctx, nws.GetPointAlertsURL(lat, lon))
+}
+
+// fetchForecast resolves the point and fetches the forecast product chosen by
+// urlOf (daily or hourly).
+func (p *NWS) fetchForecast(ctx context.Context, lat, lon float64, urlOf func(*dtos.PointProperties) string) (*dtos.ForecastData, error) {
+	point, err := nws.ResolvePoint(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	reportStep(ctx, "resolved gridpoint")
+
+	body, err := nws.MakeNWSRequest(ctx, urlOf(point))
+	if err != nil {
+		return nil, err
+	}
+
+	data := dtos.ForecastData{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("nws: parsing forecast response: %w", err)
+	}
+	reportStep(ctx, "fetched forecast")
+	return &data, nil
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/geo"
+	"weather/server/logger"
+	"weather/server/weathercalc"
+)
+
+// DefaultOpenMeteoURL is the public Open-Meteo API.
+const DefaultOpenMeteoURL = "https://api.open-meteo.com"
+
+const (
+	openMeteoHourly  = "temperature_2m,relative_humidity_2m,dew_point_2m,precipitation_probability,weather_code,wind_speed_10m,wind_direction_10m,wind_gusts_10m,visibility"
+	openMeteoDaily   = "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max,wind_speed_10m_max,wind_direction_10m_dominant"
+	openMeteoCurrent = "temperature_2m,relative_humidity_2m,dew_point_2m,weather_code,wind_speed_10m,wind_direction_10m,wind_gusts_10m,pressure_msl,visibility"
+)
+
+// OpenMeteo serves data from an Open-Meteo compatible JSON API. It covers the
+// whole globe but offers no alerts.
+type OpenMeteo struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenMeteo creates an Open-Meteo provider for the API at baseURL, or the
+// public API when baseURL is empty.
+func NewOpenMeteo(baseURL string) *OpenMeteo {
+	if baseURL == "" {
+		baseURL = DefaultOpenMeteoURL
+	}
+	return &OpenMeteo{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *OpenMeteo) Name() string { return "openmeteo" }
+
+func (p *OpenMeteo) Covers(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+type openMeteoResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time                     []string   `json:"time"`
+		Temperature2m            []float64  `json:"temperature_2m"`
+		RelativeHumidity2m       []*float64 `json:"relative_humidity_2m"`
+		DewPoint2m               []*float64 `json:"dew_point_2m"`
+		PrecipitationProbability []*float64 `json:"precipitation_probability"`
+		WeatherCode              []int      `json:"weather_code"`
+		WindSpeed10m             []float64  `json:"wind_speed_10m"`
+		WindDirection10m         []float64  `json:"wind_direction_10m"`
+		WindGusts10m             []*float64 `json:"wind_gusts_10m"`
+		Visibility               []*float64 `json:"visibility"`
+	} `json:"hourly"`
+	Daily struct {
+		Time                        []string   `json:"time"`
+		WeatherCode                 []int      `json:"weather_code"`
+		Temperature2mMax            []float64  `json:"temperature_2m_max"`
+		Temperature2mMin            []float64  `json:"temperature_2m_min"`
+		PrecipitationProbabilityMax []*float64 `json:"precipitation_probability_max"`
+		WindSpeed10mMax             []float64  `json:"wind_speed_10m_max"`
+		WindDirection10mDominant    []float64  `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+	Current struct {
+		Time               string   `json:"time"`
+		Temperature2m      *float64 `json:"temperature_2m"`
+		RelativeHumidity2m *float64 `json:"relative_humidity_2m"`
+		DewPoint2m         *float64 `json:"dew_point_2m"`
+		WeatherCode        int      `json:"weather_code"`
+		WindSpeed10m       *float64 `json:"wind_speed_10m"`
+		WindDirection10m   *float64 `json:"wind_direction_10m"`
+		WindGusts10m       *float64 `json:"wind_gusts_10m"`
+		PressureMSL        *float64 `json:"pressure_msl"`
+		Visibility         *float64 `json:"visibility"`
+	} `json:"current"`
+}
+
+func (p *OpenMeteo) Forecast(ctx context.Context, lat, lon float64) (*Forecast, error) {
+	data, err := p.fetch(ctx, lat, lon, url.Values{"daily": {openMeteoDaily}})
+	if err != nil {
+		return nil, err
+	}
+
+	d := data.Daily
+	f := &Forecast{Source: p.Name()}
+	for i, day := range d.Time {
+		if i >= len(d.Temperature2mMax) || i >= len(d.Temperature2mMin) || i >= len(d.WeatherCode) {
+			break
+		}
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		name := t.Weekday().String()
+		if i == 0 {
+			name = "Today"
+		}
+
+		summary := weatherCodeText(d.WeatherCode[i])
+		detail := fmt.Sprintf("%s. High %.0f°F, low %.0f°F.", summary, d.Temperature2mMax[i], d.Temperature2mMin[i])
+		period := dtos.ForecastPeriod{
+			Number:          i + 1,
+			Name:            name,
+			StartTime:       day,
+			IsDaytime:       true,
+			Temperature:     int(math.Round(d.Temperature2mMax[i])),
+			TemperatureUnit: "F",
+			ShortForecast:   summary,
+		}
+		if i < len(d.PrecipitationProbabilityMax) && d.PrecipitationProbabilityMax[i] != nil {
+			pop := *d.PrecipitationProbabilityMax[i]
+			period.ProbabilityOfPrecipitation = dtos.QuantitativeValue{UnitCode: "wmoUnit:percent", Value: &pop}
+			detail += fmt.Sprintf(" Chance of precipitation %.0f%%.", pop)
+		}
+		if i < len(d.WindSpeed10mMax) && i < len(d.WindDirection10mDominant) {
+			period.WindSpeed = fmt.Sprintf("%.0f mph", d.WindSpeed10mMax[i])
+			period.WindDirection = geo.CompassPoint(d.WindDirection10mDominant[i])
+			detail += fmt.Sprintf(" Wind %s up to %s.", period.WindDirection, period.WindSpeed)
+		}
+		period.DetailedForecast = detail
+		f.Periods = append(f.Periods, period)
+	}
+	return f, nil
+}
+
+func (p *OpenMeteo) Hourly(ctx context.Context, lat, lon float64) (*Hourly, error) {
+	data, err := p.fetch(ctx, lat, lon, url.Values{"hourly": {openMeteoHourly}})
+	if err != nil {
+		return nil, err
+	}
+
+	hr := data.Hourly
+	h := &Hourly{Source: p.Name()}
+	for i, ts := range hr.Time {
+		if i >= len(hr.Temperature2m) {
+			break
+		}
+		period := dtos.HourlyPeriod{
+			StartTime:   data.localTime(ts),
+			Temperature: hr.Temperature2m[i],
+		}
+		period.RelativeHumidity = at(hr.RelativeHumidity2m, i)
+		period.DewPoint = at(hr.DewPoint2m, i)
+		period.ProbabilityOfPrecipitation = at(hr.PrecipitationProbability, i)
+		period.WindGust = at(hr.WindGusts10m, i)
+		if v := at(hr.Visibility, i); v != nil {
+			miles := weathercalc.MetersToMiles(*v)
+			period.Visibility = &miles
+		}
+		if i < len(hr.WindSpeed10m) {
+			period.WindSpeed = hr.WindSpeed10m[i]
+		}
+		if i < len(hr.WindDirection10m) {
+			period.WindDirection = geo.CompassPoint(hr.WindDirection10m[i])
+		}
+		if i < len(hr.WeatherCode) {
+			period.ShortForecast = weatherCodeText(hr.WeatherCode[i])
+		}
+		h.Periods = append(h.Periods, period)
+	}
+	return h, nil
+}
+
+func (p *OpenMeteo) Current(ctx context.Context, lat, lon float64) (*Conditions, error) {
+	data, err := p.fetch(ctx, lat, lon, url.Values{"current": {openMeteoCurrent}})
+	if err != nil {
+		return nil, err
+	}
+
+	c := data.Current
+	var visibility *float64
+	if c.Visibility != nil {
+		// Open-Meteo reports visibility in meters.
+		miles := weathercalc.MetersToMiles(*c.Visibility)
+		visibility = &miles
+	}
+	return &Conditions{
+		Source: p.Name(),
+		CurrentConditions: dtos.CurrentConditions{
+			Timestamp:        data.localTime(c.Time),
+			Description:      weatherCodeText(c.WeatherCode),
+			Temperature:      c.Temperature2m,
+			DewPoint:         c.DewPoint2m,
+			RelativeHumidity: c.RelativeHumidity2m,
+			WindSpeed:        c.WindSpeed10m,
+			WindGust:         c.WindGusts10m,
+			WindDirection:    c.WindDirection10m,
+			Visibility:       visibility,
+			Pressure:         c.PressureMSL,
+		},
+	}, nil
+}
+
+func (p *OpenMeteo) Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error) {
+	return nil, ErrNotSupported
+}
+
+// fetch calls the /v1/forecast endpoint with imperial units and the
+// location's own timezone.
+func (p *OpenMeteo) fetch(ctx context.Context, lat, lon float64, q url.Values) (*openMeteoResponse, error) {
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("temperature_unit", "fahrenheit")
+	q.Set("wind_speed_unit", "mph")
+	q.Set("timezone", "auto")
+	u := p.baseURL + "/v1/forecast?" + q.Encode()
+
+	log := logger.FromContext(ctx)
+	log.Debug("Open-Meteo request", "url", u)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Error("Open-Meteo request failed", "url", u, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Error("Open-Meteo returned an error status", "url", u, "status", resp.StatusCode)
+		return nil, &StatusError{Provider: p.Name(), StatusCode: resp.StatusCode}
+	}
+
+	data := &openMeteoResponse{}
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, fmt.Errorf("openmeteo: parsing response: %w", err)
+	}
+	reportStep(ctx, "fetched forecast")
+	return data, nil
+}
+
+// localTime converts one of the response's local timestamps, which carry no
+// offset, to RFC 3339 so they line up with other providers' times.
+func (r *openMeteoResponse) localTime(ts string) string {
+	t, err := time.ParseInLocation("2006-01-02T15:04", ts, time.FixedZone("", r.UTCOffsetSeconds))
+	if err != nil {
+		return ts
+	}
+	return t.Format(time.RFC3339)
+}
+
+func at(values []*float64, i int) *float64 {
+	if i < len(values) {
+		return values[i]
+	}
+	return nil
+}
+
+// weatherCodeText describes a WMO weather interpretation code.
+func weatherCodeText(code int) string {
+	switch code {
+	case 0:
+		return "Clear sky"
+	case 1:
+		return "Mainly clear"
+	case 2:
+		return "Partly cloudy"
+	case 3:
+		return "Overcast"
+	case 45, 48:
+		return "Fog"
+	case 51, 53, 55:
+		return "Drizzle"
+	case 56, 57:
+		return "Freezing drizzle"
+	case 61:
+		return "Light rain"
+	case 63:
+		return "Rain"
+	case 65:
+		return "Heavy rain"
+	case 66, 67:
+		return "Freezing rain"
+	case 71:
+		return "Light snow"
+	case 73:
+		return "Snow"
+	case 75:
+		return "Heavy snow"
+	case 77:
+		return "Snow grains"
+	case 80, 81, 82:
+		return "Rain showers"
+	case 85, 86:
+		return "Snow showers"
+	case 95:
+		return "Thunderstorm"
+	case 96, 99:
+		return "Thunderstorm with hail"
+	default:
+		return "Unknown"
+	}
+}
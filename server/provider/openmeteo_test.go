@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const openMeteoHourlyBody = `{
+  "utc_offset_seconds": -18000,
+  "hourly": {
+    "time": ["2025-08-05T13:00", "2025-08-05T14:00"],
+    "temperature_2m": [97.2, 98.6],
+    "relative_humidity_2m": [40, null],
+    "dew_point_2m": [70.1, 69.8],
+    "precipitation_probability": [10, 20],
+    "weather_code": [1, 95],
+    "wind_speed_10m": [8.5, 12],
+    "wind_direction_10m": [180, 225],
+    "wind_gusts_10m": [null, 25],
+    "visibility": [16093.44, 8046.72]
+  }
+}`
+
+const openMeteoDailyBody = `{
+  "utc_offset_seconds": -18000,
+  "daily": {
+    "time": ["2025-08-05", "2025-08-06", "2025-08-07"],
+    "weather_code": [2, 63, 73],
+    "temperature_2m_max": [99.4, 91, -3.6],
+    "temperature_2m_min": [76, 72.6, -12],
+    "precipitation_probability_max": [null, 60, 80],
+    "wind_speed_10m_max": [14, 18, 20],
+    "wind_direction_10m_dominant": [200, 90, 0]
+  }
+}`
+
+// openMeteoServer answers /v1/forecast with the hourly or daily body,
+// checking the query asks for imperial units at the right point.
+func openMeteoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/forecast" || q.Get("latitude") != "30.2672" || q.Get("longitude") != "-97.7431" ||
+			q.Get("temperature_unit") != "fahrenheit" || q.Get("wind_speed_unit") != "mph" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case q.Has("hourly"):
+			w.Write([]byte(openMeteoHourlyBody))
+		case q.Has("daily"):
+			w.Write([]byte(openMeteoDailyBody))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenMeteoHourly(t *testing.T) {
+	p := NewOpenMeteo(openMeteoServer(t).URL + "/")
+	h, err := p.Hourly(context.Background(), 30.2672, -97.7431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Source != "openmeteo" || len(h.Periods) != 2 {
+		t.Fatalf("got %s with %d periods", h.Source, len(h.Periods))
+	}
+
+	first, second := h.Periods[0], h.Periods[1]
+	if first.StartTime != "2025-08-05T13:00:00-05:00" {
+		t.Errorf("StartTime = %s", first.StartTime)
+	}
+	if first.Temperature != 97.2 || first.WindSpeed != 8.5 || first.WindDirection != "S" || first.ShortForecast != "Mainly clear" {
+		t.Errorf("first period = %+v", first)
+	}
+	if first.WindGust != nil || second.RelativeHumidity != nil {
+		t.Error("null values were not left unset")
+	}
+	if first.Visibility == nil || *first.Visibility < 9.99 || *first.Visibility > 10.01 {
+		t.Errorf("Visibility = %v, want 10 miles", first.Visibility)
+	}
+	if second.WindGust == nil || *second.WindGust != 25 || second.WindDirection != "SW" || second.ShortForecast != "Thunderstorm" {
+		t.Errorf("second period = %+v", second)
+	}
+}
+
+func TestOpenMeteoForecast(t *testing.T) {
+	p := NewOpenMeteo(openMeteoServer(t).URL)
+	f, err := p.Forecast(context.Background(), 30.2672, -97.7431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Periods) != 3 {
+		t.Fatalf("got %d periods, want 3", len(f.Periods))
+	}
+
+	today, tomorrow := f.Periods[0], f.Periods[1]
+	if today.Name != "Today" || tomorrow.Name != "Wednesday" {
+		t.Errorf("names = %s, %s", today.Name, tomorrow.Name)
+	}
+	if today.Temperature != 99 || today.ShortForecast != "Partly cloudy" || today.ProbabilityOfPrecipitation.Value != nil {
+		t.Errorf("today = %+v", today)
+	}
+	if want := "Partly cloudy. High 99°F, low 76°F. Wind SSW up to 14 mph."; today.DetailedForecast != want {
+		t.Errorf("today detail = %q, want %q", today.DetailedForecast, want)
+	}
+	if v := tomorrow.ProbabilityOfPrecipitation.Value; v == nil || *v != 60 {
+		t.Errorf("tomorrow precipitation = %v, want 60", v)
+	}
+	if tomorrow.WindSpeed != "18 mph" || tomorrow.WindDirection != "E" {
+		t.Errorf("tomorrow wind = %s %s", tomorrow.WindDirection, tomorrow.WindSpeed)
+	}
+	// Below zero the high rounds away from zero, not towards it.
+	if cold := f.Periods[2]; cold.Temperature != -4 {
+		t.Errorf("high of -3.6°F rounded to %d, want -4", cold.Temperature)
+	}
+}
+
+func TestOpenMeteoStatusError(t *testing.T) {
+	p := NewOpenMeteo(openMeteoServer(t).URL)
+	_, err := p.Current(context.Background(), 30.2672, -97.7431)
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Current error = %v, want a 503 StatusError", err)
+	}
+}
+
+func TestRouterSelect(t *testing.T) {
+	r := NewRouter(NewNWS(), NewOpenMeteo(""))
+	tests := []struct {
+		name     string
+		provider string
+		lat, lon float64
+		want     string
+		fallback []string
+	}{
+		{"us point", "", 30.2672, -97.7431, "nws", nil},
+		{"hawaii", "", 21.3069, -157.8583, "nws", nil},
+		{"outside nws", "", 51.5074, -0.1278, "openmeteo", nil},
+		{"explicit", "openmeteo", 30.2672, -97.7431, "openmeteo", nil},
+		{"us with fallback", "", 30.2672, -97.7431, "nws>openmeteo", []string{"nws", "openmeteo"}},
+		{"outside with fallback", "", 51.5074, -0.1278, "openmeteo", []string{"nws", "openmeteo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.SetFallback(tt.fallback...); err != nil {
+				t.Fatal(err)
+			}
+			p, err := r.Select(tt.provider, tt.lat, tt.lon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Name() != tt.want {
+				t.Errorf("Select = %s, want %s", p.Name(), tt.want)
+			}
+		})
+	}
+
+	if _, err := r.Select("darksky", 0, 0); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Select(darksky) error = %v, want ErrUnknownProvider", err)
+	}
+	if _, err := NewRouter(NewNWS()).Select("", 51.5074, -0.1278); !errors.Is(err, ErrNoCoverage) {
+		t.Errorf("Select outside NWS-only router error = %v, want ErrNoCoverage", err)
+	}
+	if err := r.SetFallback("nws", "darksky"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("SetFallback(darksky) error = %v, want ErrUnknownProvider", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package provider abstracts weather data sources behind a common interface,
+// so tools can serve locations outside NWS coverage from other backends.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"weather/server/dtos"
+)
+
+var (
+	// ErrNotSupported is returned when a provider does not offer a product,
+	// e.g. alerts from a forecast-only backend.
+	ErrNotSupported = errors.New("provider: not supported")
+	// ErrUnknownProvider is returned when a provider is requested by a name
+	// that is not registered.
+	ErrUnknownProvider = errors.New("provider: unknown provider")
+	// ErrNoCoverage is returned when no registered provider covers a location.
+	ErrNoCoverage = errors.New("provider: location not covered")
+)
+
+// StatusError is returned when a provider's upstream API answers with a
+// non-2xx status.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d %s", e.Provider, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Provider is a source of weather data for a coordinate.
+type Provider interface {
+	// Name is the identifier used to select the provider explicitly.
+	Name() string
+	// Covers reports whether the provider serves the coordinate.
+	Covers(lat, lon float64) bool
+
+	Forecast(ctx context.Context, lat, lon float64) (*Forecast, error)
+	Hourly(ctx context.Context, lat, lon float64) (*Hourly, error)
+	Current(ctx context.Context, lat, lon float64) (*Conditions, error)
+	Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error)
+}
+
+// Forecast is a period forecast (e.g. "Tonight", "Friday").
+type Forecast struct {
+	Source       string                `json:"source"`
+	FallbackFrom []string              `json:"fallbackFrom,omitempty"`
+	Periods      []dtos.ForecastPeriod `json:"periods"`
+}
+
+// Hourly is an hour-by-hour forecast.
+type Hourly struct {
+	Source       string              `json:"source"`
+	FallbackFrom []string            `json:"fallbackFrom,omitempty"`
+	Periods      []dtos.HourlyPeriod `json:"periods"`
+}
+
+// Conditions are the latest observed conditions.
+type Conditions struct {
+	Source       string   `json:"source"`
+	FallbackFrom []string `json:"fallbackFrom,omitempty"`
+	dtos.CurrentConditions
+}
+
+// Router picks the provider for a request, either by explicit name or by
+// the first registered provider covering the location. With a fallback
+// order configured, requests without an explicit provider are served by a
+// Chain that falls through to the other covering providers.
+type Router struct {
+	providers []Provider
+	fallback  []string
+}
+
+// NewRouter creates a router over providers, in order of preference.
+func NewRouter(providers ...Provider) *Router {
+	return &Router{providers: providers}
+}
+
+// Select returns the provider named name, or when name is empty, the first
+// provider that covers the coordinate.
+func (r *Router) Select(name string, lat, lon float64) (Provider, error) {
+	if name != "" {
+		if p := r.byName(name); p != nil {
+			return p, nil
+		}
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+
+	var primary Provider
+	for _, p := range r.providers {
+		if p.Covers(lat, lon) {
+			primary = p
+			break
+		}
+	}
+	if primary == nil {
+		return nil, ErrNoCoverage
+	}
+	if len(r.fallback) == 0 {
+		return primary, nil
+	}
+
+	chain := []Provider{primary}
+	for _, name := range r.fallback {
+		p := r.byName(name)
+		if p != primary && p.Covers(lat, lon) {
+			chain = append(chain, p)
+		}
+	}
+	if len(chain) == 1 {
+		return primary, nil
+	}
+	return NewChain(chain...), nil
+}
+
+// SetFallback configures the order in which providers are tried when the
+// selected one cannot serve a request. No names disables fallback.
+func (r *Router) SetFallback(names ...string) error {
+	for _, name := range names {
+		if r.byName(name) == nil {
+			return fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+		}
+	}
+	r.fallback = names
+	return nil
+}
+
+// Covering returns every provider that covers the coordinate, in order of
+// preference.
+func (r *Router) Covering(lat, lon float64) []Provider {
+	var out []Provider
+	for _, p := range r.providers {
+		if p.Covers(lat, lon) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (r *Router) byName(name string) Provider {
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Names lists the registered providers in order of preference.
+func (r *Router) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for _, p := range r.providers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context whose provider calls report each completed
+// upstream step to fn, e.g. "resolved gridpoint".
+func WithProgress(ctx context.Context, fn func(message string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportStep(ctx context.Context, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		fn(message)
+	}
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"regexp"
+	"strconv"
+	"weather/server/dtos"
+	"weather/server/weathercalc"
+)
+
+// convert returns an NWS quantitative value converted to the units used by
+// the provider-independent model (°F, mph, miles, hPa, %, degrees).
+func convert(q dtos.QuantitativeValue) *float64 {
+	if q.Value == nil {
+		return nil
+	}
+	v := *q.Value
+	switch q.UnitCode {
+	case "wmoUnit:degC":
+		v = weathercalc.CelsiusToFahrenheit(v)
+	case "wmoUnit:km_h-1":
+		v = weathercalc.KmhToMph(v)
+	case "wmoUnit:m_s-1":
+		v = weathercalc.MpsToMph(v)
+	case "wmoUnit:m":
+		v = weathercalc.MetersToMiles(v)
+	case "wmoUnit:Pa":
+		v = weathercalc.PascalsToHPa(v)
+	}
+	return &v
+}
+
+var numberPattern = regexp.MustCompile(`\d+`)
+
+// parseWindSpeed extracts the highest speed from NWS wind strings such as
+// "10 mph" or "5 to 15 mph".
+func parseWindSpeed(s string) float64 {
+	var max float64
+	for _, m := range numberPattern.FindAllString(s, -1) {
+		if v, err := strconv.ParseFloat(m, 64); err == nil && v > max {
+			max = v
+		}
+	}
+	return max
+}
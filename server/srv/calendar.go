@@ -0,0 +1,59 @@
+package srv
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"weather/server/dtos"
+	"weather/server/ics"
+	"weather/server/tools"
+)
+
+// calendarPath is where RunHTTP serves the iCalendar feed.
+const calendarPath = "/calendar.ics"
+
+// serveCalendar serves forecast periods and alerts as an iCalendar feed that
+// calendar apps can subscribe to, e.g. /calendar.ics?place=Austin,TX or
+// /calendar.ics?lat=30.27&lon=-97.74&include=alerts. It accepts the same
+// parameters as the export_calendar tool.
+func serveCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	args := dtos.CalendarParams{
+		Place:    q.Get("place"),
+		Provider: q.Get("provider"),
+		Include:  q.Get("include"),
+	}
+	if args.Place == "" {
+		lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+		lon, errLon := strconv.ParseFloat(q.Get("lon"), 64)
+		if errLat != nil || errLon != nil {
+			http.Error(w, "give a place or lat and lon", http.StatusBadRequest)
+			return
+		}
+		args.Latitude, args.Longitude = lat, lon
+	}
+
+	cal, err := tools.BuildCalendar(r.Context(), args)
+	if err != nil {
+		if errors.Is(err, tools.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Warn("calendar request failed", "query", r.URL.RawQuery, "error", err)
+		http.Error(w, "unable to fetch weather data", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", ics.MediaType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="weather.ics"`)
+	w.Header().Set("Cache-Control", "max-age=900")
+	w.Write(cal.Marshal(time.Now()))
+}
@@ -1,18 +1,45 @@
 package srv
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"weather/server/activity"
+	"weather/server/aviation"
+	"weather/server/config"
+	"weather/server/gazetteer"
+	"weather/server/logger"
+	"weather/server/nws"
+	"weather/server/provider"
 	"weather/server/tools"
+	"weather/server/ugc"
+	"weather/server/watch"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type Server struct {
-	mcpServer *mcp.Server
+	mcpServer   *mcp.Server
+	cfg         *config.Config
+	stopWatches context.CancelFunc
+	watchesDone chan struct{}
+
+	// calls is the parent of every request context; cancelling it aborts
+	// the calls still running when a shutdown's grace period ends.
+	calls       context.Context
+	cancelCalls context.CancelFunc
+
+	// stdio returns the transport RunStdio and Serve connect over standard
+	// input and output. Tests replace it with an in-memory transport.
+	stdio func() mcp.Transport
 }
 
 // NewServer creates and initializes a new Server instance.
-// It sets up the underlying MCP server with the necessary implementation details.
-func NewServer() *Server {
+// It sets up the underlying MCP server with the necessary implementation details
+// and applies cfg to the NWS client and the weather providers. It fails if a
+// file named in cfg cannot be loaded.
+func NewServer(cfg *config.Config) (*Server, error) {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "weather",
 		Version: "v1.0.0",
@@ -20,11 +47,103 @@ func NewServer() *Server {
 
 	s := &Server{
 		mcpServer: mcpServer,
+		cfg:       cfg,
+		stdio:     func() mcp.Transport { return mcp.NewStdioTransport() },
 	}
+	s.calls, s.cancelCalls = context.WithCancel(context.Background())
 
+	nws.SetBaseURL(cfg.NWS.BaseURL)
+	nws.SetUserAgent(cfg.NWS.UserAgent)
+	nws.SetLimits(cfg.NWS.MaxConcurrent, cfg.NWS.RequestsPerSecond)
+	nws.SetCacheTTLs(cfg.NWS.PointsTTL, cfg.NWS.ZonesTTL)
+
+	mcpServer.AddReceivingMiddleware(sessionLogging, trackSessions, s.cancelOnShutdown)
+	router := provider.NewRouter(
+		provider.NewNWS(),
+		provider.NewOpenMeteo(cfg.OpenMeteo.BaseURL),
+	)
+	if err := router.SetFallback(cfg.Fallback...); err != nil {
+		return nil, err
+	}
+	tools.SetProviders(router)
+	tools.SetTAFSource(aviation.NewTAFSource(cfg.TAF.BaseURL))
+	// Files named in the configuration must load: silently falling back to
+	// the embedded samples would hide a typo until a lookup came up empty.
+	if dir := cfg.GazetteerDir; dir != "" {
+		if err := gazetteer.LoadCensus(dir); err != nil {
+			return nil, fmt.Errorf("gazetteer_dir: %w", err)
+		}
+	}
+	if path := cfg.ZonesFile; path != "" {
+		if err := ugc.LoadZones(path); err != nil {
+			return nil, fmt.Errorf("zones_file: %w", err)
+		}
+	}
+	if path := cfg.ActivityProfiles; path != "" {
+		profiles, err := activity.LoadProfiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("activity_profiles: %w", err)
+		}
+		tools.SetActivityProfiles(profiles)
+	}
+	s.startWatches()
 	s.registerTools()
 
-	return s
+	return s, nil
+}
+
+// startWatches opens the configured watch store and starts the scheduler
+// that checks it against NWS every watch interval. If the store cannot be
+// opened the watch tools are disabled.
+func (s *Server) startWatches() {
+	store, err := watch.Open(s.cfg.Watch.Store)
+	if err != nil {
+		slog.Warn("watches disabled", "error", err)
+		return
+	}
+
+	allow, err := watch.ParseAllowlist(s.cfg.Watch.WebhookAllow)
+	if err != nil {
+		slog.Warn("ignoring the watch webhook allowlist", "error", err)
+	}
+	watch.SetWebhookAllowlist(allow)
+
+	// No session from before a restart can come back, so their watches
+	// would only be orphans.
+	if n, err := store.ExpireSession(""); err != nil {
+		slog.Warn("removing watches of earlier sessions", "error", err)
+	} else if n > 0 {
+		slog.Info("Removed watches of earlier sessions", "count", n)
+	}
+
+	scheduler := watch.NewScheduler(store, provider.NewNWS(), s.cfg.Watch.Interval, s.notifyWatch)
+	tools.SetWatches(store, scheduler)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopWatches = cancel
+	s.watchesDone = make(chan struct{})
+	go func() {
+		defer close(s.watchesDone)
+		scheduler.Run(ctx)
+	}()
+}
+
+// notifyWatch sends a watch event to the session that created the watch as
+// a log notification from the "watch" logger. Clients only receive it once
+// they have set a log level. A watch without an owning session, e.g. one
+// saved before sessions had IDs, is not notified.
+func (s *Server) notifyWatch(ctx context.Context, sessionID string, e watch.Event) error {
+	if sessionID == "" {
+		return errors.New("watch has no owning session")
+	}
+	ss := tools.SessionByID(sessionID)
+	if ss == nil {
+		return errors.New("session not connected")
+	}
+	return ss.Log(ctx, &mcp.LoggingMessageParams{
+		Level:  "notice",
+		Logger: "watch",
+		Data:   e,
+	})
 }
 
 func (s *Server) MCP() *mcp.Server {
@@ -36,7 +155,7 @@ func (s *Server) registerTools() {
 	// Tool: get_alerts
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_alerts",
-		Description: "Get active weather alerts for a given US state",
+		Description: "Get active weather alerts for a US state, marine area (e.g. PZ, GM), marine region or zone (e.g. PZZ530); with a place or coordinates, marks which alerts actually cover that point",
 	}, tools.GetAlerts)
 
 	// Tool: get_forecast
@@ -44,4 +163,148 @@ func (s *Server) registerTools() {
 		Name:        "get_forecast",
 		Description: "Get weather forecast for a given location",
 	}, tools.GetForecast)
+
+	// Tool: get_hourly_forecast
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_hourly_forecast",
+		Description: "Get the hour-by-hour forecast (temperature, precipitation chance, humidity, wind) for a location; ensemble mode merges all providers and reports their spread",
+	}, tools.GetHourlyForecast)
+
+	// Tool: get_current_conditions
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_current_conditions",
+		Description: "Get the latest observed weather conditions near a location",
+	}, tools.GetCurrentConditions)
+
+	// Tool: geocode
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "geocode",
+		Description: "Resolve a US place name, county or ZIP code to coordinates using an offline gazetteer; returns ranked candidates. Unless the server is configured with the full Census gazetteer, only a sample of major places, ZIP codes and counties is known",
+	}, tools.Geocode)
+
+	// Tool: locate_point
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "locate_point",
+		Description: "Find the NWS forecast zone, county, fire weather zone, forecast office (WFO) and timezone for a location",
+	}, tools.LocatePoint)
+
+	// Tool: get_metar
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_metar",
+		Description: "Get the latest METAR for an airport station (e.g. KAUS) or the station nearest a location, decoded into wind, visibility, weather, clouds, ceiling and flight category (VFR/MVFR/IFR/LIFR)",
+	}, tools.GetMETAR)
+
+	// Tool: get_taf
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_taf",
+		Description: "Get the current TAF (terminal aerodrome forecast) for an airport station or the nearest station issuing one, decoded into FM/TEMPO/BECMG/PROB change groups with flight categories",
+	}, tools.GetTAF)
+
+	// Tool: get_marine_forecast
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_marine_forecast",
+		Description: "Get the coastal, offshore or Great Lakes waters forecast (wind, waves, weather) and active marine alerts for a marine zone such as PZZ530, or for coordinates on the water",
+	}, tools.GetMarineForecast)
+
+	// Tool: get_fire_weather
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_fire_weather",
+		Description: "Get a fire weather briefing for a location: active Red Flag Warnings and Fire Weather Watches, minimum humidity, peak wind gusts, Haines index and mixing height from gridpoint data, and the fire weather zone forecast",
+	}, tools.GetFireWeather)
+
+	// Tool: compute_weather_index
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "compute_weather_index",
+		Description: "Compute heat index, wind chill, dew point or relative humidity, and apparent (feels-like) temperature from temperature, humidity and wind using NWS formulas",
+	}, tools.ComputeWeatherIndex)
+
+	// Tool: get_astronomy
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_astronomy",
+		Description: "Get sunrise, sunset, civil/nautical/astronomical twilight, day length, solar noon, moon phase and moonrise/moonset for a location and date, in the location's local time",
+	}, tools.GetAstronomy)
+
+	// Tool: get_forecasts_batch
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_forecasts_batch",
+		Description: "Get forecasts for up to 10 locations (coordinates or place names) in one call, fetched concurrently; each location reports its own result or error",
+	}, tools.GetForecastsBatch)
+
+	// Tool: get_route_weather
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_route_weather",
+		Description: "Get a weather timeline for a trip: samples points along a route (waypoints or a GeoJSON LineString), estimates arrival at each from the departure time and average speed, and reports the hourly forecast valid then and active alerts along the way",
+	}, tools.GetRouteWeather)
+
+	// Tool: score_activity
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "score_activity",
+		Description: "Score the hourly forecast for an outdoor activity (running, cycling, picnic, drone, crane or a configured profile) against its wind, precipitation, temperature, visibility and lightning limits, returning go/no-go windows with reasons",
+	}, tools.ScoreActivity)
+
+	// Tool: export_calendar
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "export_calendar",
+		Description: "Export a location's forecast periods and active alerts as an iCalendar (ICS) document with stable event UIDs, so re-importing updates events instead of duplicating them; over HTTP the same calendar is served at /calendar.ics for subscriptions",
+	}, tools.ExportCalendar)
+
+	// Tool: get_forecast_chart
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_forecast_chart",
+		Description: "Draw a chart of the hourly temperature, precipitation chance and wind forecast for a location, returned as a PNG and/or SVG image with optional text sparklines for terminal clients",
+	}, tools.GetForecastChart)
+
+	// Tool: create_watch
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "create_watch",
+		Description: "Create a weather watch for a location that is re-checked against NWS data every few minutes: a threshold on the hourly forecast (e.g. gust > 40 mph within 24h) or a set of alert events (e.g. Red Flag Warning). Firings are sent to this session as log notifications and, optionally, POSTed to a webhook with an Idempotency-Key header. The watch ends with the session unless it is created with an ownerToken",
+	}, tools.CreateWatch)
+
+	// Tool: list_watches
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_watches",
+		Description: "List the weather watches created by this session, or with the given owner token, with their condition, last check and whether they are currently triggered",
+	}, tools.ListWatches)
+
+	// Tool: delete_watch
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "delete_watch",
+		Description: "Delete a weather watch created by this session, or with the given owner token, by ID",
+	}, tools.DeleteWatch)
+}
+
+// sessionLogging attaches a logger to every incoming request that forwards
+// records to the calling session, honouring the level it set via logging/setLevel.
+func sessionLogging(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		ctx = logger.WithContext(ctx, logger.ForSession(ss))
+		return next(ctx, ss, method, params)
+	}
+}
+
+// trackSessions gives each session its ID as it initializes and drops the
+// ID once the session ends.
+func trackSessions(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		if method == "initialize" {
+			tools.SessionID(ss)
+			go func() {
+				ss.Wait()
+				tools.ForgetSession(ss)
+			}()
+		}
+		return next(ctx, ss, method, params)
+	}
+}
+
+// cancelOnShutdown cancels a request's context when the server gives up
+// waiting for in-flight calls during shutdown.
+func (s *Server) cancelOnShutdown(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(s.calls, cancel)
+		defer stop()
+		return next(ctx, ss, method, params)
+	}
 }
@@ -0,0 +1,164 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"weather/server/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const texasAlerts = `{
+  "type": "FeatureCollection",
+  "features": [{
+    "id": "https://api.weather.gov/alerts/urn:oid:2.49.0.1.840.0.1",
+    "properties": {
+      "event": "Heat Advisory",
+      "areaDesc": "Travis",
+      "severity": "Moderate",
+      "sent": "2025-08-05T12:00:00-05:00",
+      "description": "Heat index values up to 110 expected.",
+      "geocode": {"UGC": ["TXZ192"], "SAME": ["048453"]}
+    }
+  }]
+}`
+
+// newSSEServer serves RunSSE's handler on a test server, with NWS requests
+// going to a stub that knows the active alerts for Texas.
+func newSSEServer(t *testing.T) string {
+	t.Helper()
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts/active/area/TX" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Write([]byte(texasAlerts))
+	}))
+	t.Cleanup(stub.Close)
+
+	cfg, err := config.Load([]string{"--transport", "sse", "--nws-base-url", stub.URL, "--watch-store", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.stopWatches)
+
+	srv := httptest.NewServer(s.sseMux())
+	t.Cleanup(srv.Close)
+	return srv.URL + cfg.SSEPath
+}
+
+// getAlerts calls get_alerts for Texas on cs and checks the stub's alert
+// is reported.
+func getAlerts(ctx context.Context, cs *mcp.ClientSession) error {
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "get_alerts", Arguments: map[string]any{"state": "TX"}})
+	if err != nil {
+		return err
+	}
+	if len(res.Content) == 0 {
+		return errors.New("no content")
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Event: Heat Advisory", "Area: Travis", "Heat index values up to 110 expected."} {
+		if !strings.Contains(text, want) {
+			return fmt.Errorf("text lacks %q:\n%s", want, text)
+		}
+	}
+	return nil
+}
+
+func TestSSEGetAlerts(t *testing.T) {
+	url := newSSEServer(t)
+	ctx := context.Background()
+
+	// Both connections stay open as sessions on the same handler, and each
+	// call must be routed to the session it was made on.
+	var sessions [2]*mcp.ClientSession
+	for i := range sessions {
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, mcp.NewSSEClientTransport(url, nil))
+		if err != nil {
+			t.Fatalf("connection %d: %v", i+1, err)
+		}
+		defer cs.Close()
+		sessions[i] = cs
+	}
+	for round := range 2 {
+		for i, cs := range sessions {
+			if err := getAlerts(ctx, cs); err != nil {
+				t.Errorf("round %d, session %d: get_alerts: %v", round+1, i+1, err)
+			}
+		}
+	}
+
+	// And with the calls in flight together.
+	var wg sync.WaitGroup
+	errs := make([]error, len(sessions))
+	for i, cs := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = getAlerts(ctx, cs)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("concurrent call on session %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestSSEWatchesAreScopedToSession(t *testing.T) {
+	url := newSSEServer(t)
+	ctx := context.Background()
+
+	var sessions [2]*mcp.ClientSession
+	var ids [2]string
+	for i := range sessions {
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, mcp.NewSSEClientTransport(url, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cs.Close()
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "create_watch", Arguments: map[string]any{
+			"latitude": 30.2672, "longitude": -97.7431, "alertEvents": []string{"Heat Advisory"},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := res.Content[0].(*mcp.TextContent).Text
+		if _, err := fmt.Sscanf(text, "Created watch %s", &ids[i]); err != nil {
+			t.Fatalf("create_watch returned %q", text)
+		}
+		ids[i] = strings.TrimSuffix(ids[i], ":")
+		sessions[i] = cs
+	}
+
+	for i, cs := range sessions {
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "list_watches"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := res.Content[0].(*mcp.TextContent).Text
+		if !strings.Contains(text, ids[i]) || strings.Contains(text, ids[1-i]) {
+			t.Errorf("session %d lists %q, want only %s", i+1, text, ids[i])
+		}
+	}
+	res, err := sessions[0].CallTool(ctx, &mcp.CallToolParams{Name: "delete_watch", Arguments: map[string]any{"id": ids[1]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(text, "No watch") {
+		t.Errorf("deleting another session's watch: %q", text)
+	}
+}
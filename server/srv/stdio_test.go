@@ -0,0 +1,69 @@
+package srv
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather/server/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// runStdio runs s.RunStdio over in-memory transports in place of standard
+// input and output, returning the client's session and RunStdio's result.
+func runStdio(t *testing.T, ctx context.Context) (*mcp.ClientSession, <-chan error) {
+	t.Helper()
+	cfg, err := config.Load([]string{"--watch-store", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverT, clientT := mcp.NewInMemoryTransports()
+	s.stdio = func() mcp.Transport { return serverT }
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.RunStdio(ctx) }()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(context.Background(), clientT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs, errc
+}
+
+// waitRun fails the test unless RunStdio returns without error within a
+// second.
+func waitRun(t *testing.T, errc <-chan error) {
+	t.Helper()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Errorf("RunStdio = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RunStdio did not return")
+	}
+}
+
+func TestRunStdioEndsWithClient(t *testing.T) {
+	cs, errc := runStdio(t, context.Background())
+	res, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Tools) == 0 {
+		t.Error("no tools listed over stdio")
+	}
+	cs.Close()
+	waitRun(t, errc)
+}
+
+func TestRunStdioStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cs, errc := runStdio(t, ctx)
+	defer cs.Close()
+	cancel()
+	waitRun(t, errc)
+}
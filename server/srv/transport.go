@@ -2,129 +2,217 @@ package srv
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
+	"weather/server/config"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// Run serves the configured transport on the configured address and path.
+// It blocks until the server is stopped or ctx is cancelled, in which case
+// it shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	switch s.cfg.Transport {
+	case config.TransportStdio:
+		return s.RunStdio(ctx)
+	case config.TransportSSE:
+		return s.RunSSE(ctx, s.cfg.Addr)
+	case config.TransportServe:
+		return s.Serve(ctx, s.cfg.Addr, s.cfg.Stdio)
+	default:
+		return s.RunHTTP(ctx, s.cfg.Addr)
+	}
+}
+
 // RunStdio starts the server and communicates over standard input/output.
 // This is the typical mode for a command-line MCP plugin.
-// It blocks until the server is stopped.
-func (s *Server) RunStdio() error {
+// It blocks until the client closes the session or ctx is cancelled.
+func (s *Server) RunStdio(ctx context.Context) error {
 	slog.Info("Running server with stdio transport")
-	if err := s.mcpServer.Run(context.Background(), mcp.NewStdioTransport()); err != nil {
+	ss, err := s.mcpServer.Connect(ctx, s.stdio())
+	if err != nil {
 		return err
 	}
 
-	return nil
+	done := make(chan struct{})
+	go func() {
+		ss.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return s.shutdown(nil)
 }
 
 // RunHTTP starts the server and listens for connections on the given HTTP address.
-// It uses Server-Sent Events (SSE) for transport.
+// It uses the streamable HTTP transport on the configured path, where every
+// client that initializes gets a session of its own.
 // This is useful for web-based clients.
-func (s *Server) RunHTTP(addr string) error {
-	transport := mcp.NewStreamableServerTransport("")
-	http.Handle("/mcp/stream", transport)
-	// http.Handle("/images/", http.StripPrefix("/images/", http.FileServer(http.Dir("./static/images"))))
+func (s *Server) RunHTTP(ctx context.Context, addr string) error {
+	slog.Info("Starting HTTP server", "address", addr, "path", s.cfg.Path)
+	return s.listen(ctx, &http.Server{Addr: addr, Handler: s.httpMux()})
+}
 
-	// Run the MCP server in a separate goroutine to not block the HTTP server.
-	go func() {
-		if err := s.mcpServer.Run(context.Background(), transport); err != nil {
-			slog.Error("MCP server run failed", "error", err)
-			// os.Exit(1)
-			// return err
-		}
-	}()
+// httpMux returns the handler RunHTTP serves: streamable HTTP sessions on
+// the configured path and the alert calendar.
+func (s *Server) httpMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(s.cfg.Path, s.streamableHandler())
+	mux.HandleFunc(calendarPath, serveCalendar)
+	return mux
+}
 
-	slog.Info("Starting HTTP server", "address", addr)
-	if err := http.ListenAndServe(addr, nil); err != nil {
-		slog.Error("failed to start HTTP server", "error", err)
-		// os.Exit(1)
-		return err
-	}
-	return nil
+// streamableHandler returns the handler for streamable HTTP sessions. Like
+// sseHandler it keeps the session table, routing requests by their
+// Mcp-Session-Id header, so it must not be created per request.
+func (s *Server) streamableHandler() http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		slog.Info("New MCP connection", "transport", "http", "remote", r.RemoteAddr)
+		return s.mcpServer
+	}, nil)
 }
 
-// RunSSE starts the SSE server that can handle multiple MCP server instances
-func (s *Server) RunSSE() error {
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
+// RunSSE serves MCP over the 2024-11-05 SSE transport on addr. A client
+// opens an event stream with a GET on the SSE path and is sent a message
+// endpoint carrying its session ID; messages POSTed there are routed to that
+// session. It blocks until the server is stopped.
+func (s *Server) RunSSE(ctx context.Context, addr string) error {
+	slog.Info("Starting SSE server", "address", addr, "path", s.cfg.SSEPath)
+	return s.listen(ctx, &http.Server{Addr: addr, Handler: s.sseMux()})
+}
+
+// sseMux returns the handler RunSSE serves: SSE sessions on the configured
+// SSE path and the alert calendar.
+func (s *Server) sseMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(s.cfg.SSEPath, s.sseHandler())
+	mux.HandleFunc(calendarPath, serveCalendar)
+	return mux
+}
+
+// sseHandler returns the handler for SSE sessions. It lives as long as the
+// server and keeps the session table, so it must not be created per request.
+// Sessions share one MCP server, and with it the tools and caches.
+func (s *Server) sseHandler() http.Handler {
+	return mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
+		slog.Info("New MCP connection", "transport", "sse", "remote", r.RemoteAddr)
+		return s.mcpServer
+	})
+}
 
-		if path != "/mcp/stream" {
-			slog.Warn("No MCP server registered for path", "path", path)
-			http.Error(w, "invalid MCP path", http.StatusNotFound)
-			return
+// Serve listens on addr with streamable HTTP on the configured path and SSE
+// on the configured SSE path, and also serves stdio when stdio is set. Every
+// transport shares the same MCP server, so tools, caches and watches are
+// common to all sessions. It blocks until the HTTP server fails, ctx is
+// cancelled or, with stdio, the stdio session ends.
+func (s *Server) Serve(ctx context.Context, addr string, stdio bool) error {
+	mux := http.NewServeMux()
+	mux.Handle(s.cfg.Path, s.streamableHandler())
+	mux.Handle(s.cfg.SSEPath, s.sseHandler())
+	mux.HandleFunc(calendarPath, serveCalendar)
+
+	if stdio {
+		slog.Info("Running server with stdio transport")
+		ss, err := s.mcpServer.Connect(ctx, s.stdio())
+		if err != nil {
+			return err
 		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			ss.Wait()
+			cancel()
+		}()
+	}
 
-		sseHandler := mcp.NewSSEHandler(func(request *http.Request) *mcp.Server {
-			slog.Info("New MCP connection", "path", path)
-			return s.MCP()
-		})
+	slog.Info("Starting HTTP server", "address", addr, "streamable", s.cfg.Path, "sse", s.cfg.SSEPath)
+	return s.listen(ctx, &http.Server{Addr: addr, Handler: mux})
+}
 
-		sseT := mcp.NewSSEServerTransport("/mcp/stream", nil)
-		sseT.ServeHTTP(w, r)
+// listen serves hs until it fails or ctx is cancelled, then shuts down.
+func (s *Server) listen(ctx context.Context, hs *http.Server) error {
+	errc := make(chan error, 1)
+	go func() { errc <- hs.ListenAndServe() }()
 
-		sseHandler.ServeHTTP(w, r)
-	})
+	select {
+	case err := <-errc:
+		slog.Error("failed to start HTTP server", "error", err)
+		return err
+	case <-ctx.Done():
+	}
+	return s.shutdown(hs)
+}
 
-	if handler == nil {
-		return fmt.Errorf("failed to create SSE handler")
+// shutdown stops the watch scheduler and hs, if any, and closes every MCP
+// session once its in-flight calls have finished. Calls still running after
+// the configured grace period are cancelled, open connections are dropped
+// and an error is returned.
+func (s *Server) shutdown(hs *http.Server) error {
+	slog.Info("Shutting down", "grace", s.cfg.ShutdownGrace)
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownGrace)
+	defer cancel()
+
+	if s.stopWatches != nil {
+		s.stopWatches()
+		select {
+		case <-s.watchesDone:
+		case <-ctx.Done():
+		}
 	}
 
-	slog.Info("Starting SSE server", "address", ":8080")
-	if err := http.ListenAndServe(":8080", handler); err != nil {
-		slog.Error("Failed to start SSE server", "error", err)
-		return err
+	// Shutdown closes the listeners straight away but waits for open
+	// requests, including SSE streams, which end as their sessions close.
+	var wg sync.WaitGroup
+	httpErr := make(chan error, 1)
+	if hs != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			httpErr <- hs.Shutdown(ctx)
+		}()
+	} else {
+		httpErr <- nil
 	}
+	for ss := range s.mcpServer.Sessions() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ss.Close()
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-	return nil
+	select {
+	case <-done:
+		err := <-httpErr
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		return err
+	case <-ctx.Done():
+		slog.Warn("Shutdown grace period expired, cancelling in-flight calls")
+		s.cancelCalls()
+		if hs != nil {
+			hs.Close()
+		}
+		// Shutdown returns once the grace period is over, with its error if
+		// requests were still open; otherwise it was the sessions that did
+		// not close in time.
+		err := <-httpErr
+		if err == nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("shutdown grace period of %s expired: %w", s.cfg.ShutdownGrace, err)
+	}
 }
-
-// func (s *Server) RunHTTP(addr string) error {
-// 	transport := mcp.NewStreamableServerTransport("")
-// 	mux := http.NewServeMux()
-// 	mux.Handle("/mcp/stream", transport)
-
-// 	httpServer := &http.Server{
-// 		Addr:    addr,
-// 		Handler: mux,
-// 	}
-
-// 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-// 	defer stop()
-
-// 	errChan := make(chan error, 1)
-
-// 	// Run the MCP server in a separate goroutine to not block the HTTP server.
-// 	go func() {
-// 		if err := s.mcpServer.Run(ctx, transport); err != nil {
-// 			errChan <- err
-// 		}
-// 	}()
-
-// 	// Run the HTTP server in a separate goroutine.
-// 	go func() {
-// 		slog.Info("Starting HTTP server", "address", addr)
-// 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-// 			errChan <- err
-// 		}
-// 	}()
-
-// 	// Wait for an interrupt signal or an error from one of the goroutines.
-// 	select {
-// 	case err := <-errChan:
-// 		return err
-// 	case <-ctx.Done():
-// 		slog.Info("Shutting down server...")
-// 		// Create a new context for shutdown to allow for a timeout.
-// 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-// 		defer cancel()
-// 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
-// 			return err
-// 		}
-// 	}
-
-// 	return nil
-// }
@@ -0,0 +1,133 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather/server/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestShutdownReportsExpiredGrace(t *testing.T) {
+	s := &Server{
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: "test"}, nil),
+		cfg:       &config.Config{ShutdownGrace: 50 * time.Millisecond},
+	}
+	s.calls, s.cancelCalls = context.WithCancel(context.Background())
+
+	// A request that outlasts the grace period keeps Shutdown waiting.
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	hs := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go hs.Serve(ln)
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+
+	err = s.shutdown(hs)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("shutdown error = %v, want the expired grace period", err)
+	}
+	if s.calls.Err() == nil {
+		t.Error("in-flight calls were not cancelled")
+	}
+}
+
+func TestShutdownWithinGrace(t *testing.T) {
+	s := &Server{
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: "test"}, nil),
+		cfg:       &config.Config{ShutdownGrace: time.Second},
+	}
+	s.calls, s.cancelCalls = context.WithCancel(context.Background())
+	hs := &http.Server{Handler: http.NotFoundHandler()}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go hs.Serve(ln)
+
+	if err := s.shutdown(hs); err != nil {
+		t.Errorf("shutdown error = %v", err)
+	}
+}
+
+func TestHTTPSessionsAreIndependent(t *testing.T) {
+	cfg, err := config.Load([]string{"--watch-store", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.stopWatches)
+	srv := httptest.NewServer(s.httpMux())
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	var sessions [2]*mcp.ClientSession
+	for i := range sessions {
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, mcp.NewStreamableClientTransport(srv.URL+cfg.Path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cs.Close()
+		sessions[i] = cs
+	}
+	if a, b := sessions[0].ID(), sessions[1].ID(); a == "" || a == b {
+		t.Fatalf("session IDs %q and %q, want two distinct IDs", a, b)
+	}
+
+	// A watch made in one session is not visible in the other.
+	if _, err := sessions[0].CallTool(ctx, &mcp.CallToolParams{Name: "create_watch", Arguments: map[string]any{
+		"latitude": 30.2672, "longitude": -97.7431, "alertEvents": []string{"Heat Advisory"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := sessions[1].CallTool(ctx, &mcp.CallToolParams{Name: "list_watches"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "Heat Advisory") {
+		t.Errorf("second session lists the first one's watch: %q", text)
+	}
+
+	n := 0
+	for range s.mcpServer.Sessions() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("server has %d sessions, want 2", n)
+	}
+	if err := s.shutdown(nil); err != nil {
+		t.Fatal(err)
+	}
+	for range s.mcpServer.Sessions() {
+		t.Error("a session is still open after shutdown")
+	}
+}
+
+func TestNewServerRejectsMissingFiles(t *testing.T) {
+	missing := t.TempDir() + "/missing"
+	for _, flag := range []string{"--gazetteer-dir", "--zones-file", "--activity-profiles"} {
+		cfg, err := config.Load([]string{"--watch-store", "", flag, missing})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewServer(cfg); err == nil {
+			t.Errorf("NewServer with %s %s succeeded", flag, missing)
+		}
+	}
+}
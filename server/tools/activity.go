@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/activity"
+	"weather/server/dtos"
+	"weather/server/nws"
+	"weather/server/tz"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultActivityHours = 24
+	maxActivityHours     = 156
+)
+
+// activityProfiles are the profiles score_activity can use, by name.
+var activityProfiles = profileSet(nil)
+
+// SetActivityProfiles adds profiles, such as ones loaded from a config file,
+// to the built-in ones. A profile with the name of a built-in replaces it.
+func SetActivityProfiles(profiles []activity.Profile) {
+	activityProfiles = profileSet(profiles)
+}
+
+func profileSet(custom []activity.Profile) map[string]activity.Profile {
+	set := map[string]activity.Profile{}
+	for _, p := range append(activity.Defaults(), custom...) {
+		set[p.Name] = p
+	}
+	return set
+}
+
+// ScoreActivity evaluates the hourly forecast for a location against an
+// activity's weather limits and reports go and no-go windows with reasons.
+func ScoreActivity(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.ActivityParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	profile, ok := activityProfiles[strings.ToLower(strings.TrimSpace(args.Activity))]
+	if !ok {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown activity %q. Available activities: %s.", args.Activity, strings.Join(slices.Sorted(maps.Keys(activityProfiles)), ", "))}},
+		}, nil
+	}
+
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+	p, res := selectProvider(args.Provider, lat, lon)
+	if res != nil {
+		return res, nil
+	}
+
+	hours := args.Hours
+	if hours <= 0 {
+		hours = defaultActivityHours
+	}
+	hours = min(hours, maxActivityHours)
+
+	// The location's timezone is only needed for display; outside NWS
+	// coverage times keep the offset the provider reported.
+	var zone string
+	if point, err := nws.ResolvePoint(ctx, lat, lon); err == nil {
+		zone = point.TimeZone
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	loc := tz.Load(zone)
+
+	from := time.Now()
+	if args.Start != "" {
+		var err error
+		from, err = parseLocalTime(args.Start, loc)
+		if err != nil {
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid start %q. Use RFC 3339 (2025-06-20T06:00:00-05:00) or local time (2025-06-20T06:00).", args.Start)}},
+			}, nil
+		}
+	}
+	from = from.Truncate(time.Hour)
+	to := from.Add(time.Duration(hours) * time.Hour)
+
+	prog := newProgress(session, params, 0)
+	hourly, err := p.Hourly(prog.Context(ctx), lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch hourly forecast data for this location."}},
+		}, nil
+	}
+	deriveHourly(hourly.Periods)
+
+	report := profile.Evaluate(hourly.Source, hourly.Periods, from, to)
+	if len(report.Hours) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "The hourly forecast does not cover the requested window."}},
+		}, nil
+	}
+	if zone != "" {
+		for i := range report.Hours {
+			report.Hours[i].Start = report.Hours[i].Start.In(loc)
+		}
+		for i := range report.Windows {
+			report.Windows[i].Start = report.Windows[i].Start.In(loc)
+			report.Windows[i].End = report.Windows[i].End.In(loc)
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatActivity(report, hourly.FallbackFrom)}},
+		StructuredContent: report,
+	}, nil
+}
+
+func formatActivity(r *activity.Report, fallbackFrom []string) string {
+	goHours := 0
+	for _, h := range r.Hours {
+		if h.Go {
+			goHours++
+		}
+	}
+	name := r.Profile.Name
+	if r.Profile.Description != "" {
+		name += " (" + r.Profile.Description + ")"
+	}
+	lines := []string{
+		fmt.Sprintf("Activity: %s: %d of %d hours are a go", name, goHours, len(r.Hours)),
+		sourceLine(r.Source, fallbackFrom),
+	}
+
+	for _, w := range r.Windows {
+		span := w.Start.Format("Mon 3:04 PM") + " to " + w.End.Format("Mon 3:04 PM MST")
+		if !w.Go {
+			details := make([]string, len(w.Reasons))
+			for i, reason := range w.Reasons {
+				details[i] = reason.Detail
+			}
+			lines = append(lines, "- NO-GO "+span+": "+strings.Join(details, "; "))
+			continue
+		}
+		lines = append(lines, "- GO "+span)
+	}
+	if len(r.Unchecked) > 0 {
+		lines = append(lines, "Not checked (missing from this forecast): "+strings.Join(r.Unchecked, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,53 +2,226 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"strings"
+	"weather/server/alerts"
+	"weather/server/cap"
 	"weather/server/dtos"
+	"weather/server/logger"
 	"weather/server/nws"
+	"weather/server/ugc"
+	"weather/server/vtec"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// GetAlerts fetches active weather alerts for a given US state from the NWS API.
+// GetAlerts fetches active weather alerts for a US state, marine area, marine
+// region or zone from the NWS API. When a point is given (coordinates or a
+// place), each alert is marked with whether its polygon or affected zones
+// actually cover that point.
 func GetAlerts(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.AlertsParams]) (*mcp.CallToolResultFor[any], error) {
-	url := nws.GetAlertsURL(params.Arguments.State)
+	args := params.Arguments
+	format := strings.ToLower(strings.TrimSpace(args.Format))
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "cap" {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown format %q. Use 'text' or 'cap'.", args.Format)}},
+		}, nil
+	}
+	state := args.State
+	lat, lon := args.Latitude, args.Longitude
+	hasPoint := lat != 0 || lon != 0
+
+	if args.Place != "" {
+		place, res := resolvePlace(args.Place)
+		if res != nil {
+			return res, nil
+		}
+		if state == "" {
+			state = place.State
+		}
+		lat, lon, hasPoint = place.Latitude, place.Longitude, true
+	}
+	if state == "" && args.Zone == "" && args.Region == "" && hasPoint {
+		if point, err := nws.ResolvePoint(ctx, lat, lon); err == nil {
+			state = point.RelativeLocation.Properties.State
+		}
+	}
+
+	url, scope, res := alertsScope(args.Zone, args.Region, state)
+	if res != nil {
+		return res, nil
+	}
 
-	body, err := nws.MakeNWSRequest(url)
+	features, err := nws.FetchAlerts(ctx, url)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return &mcp.CallToolResultFor[any]{
 			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch alerts or no alerts found."}},
 		}, nil
 	}
 
-	data := dtos.FeatureCollection{}
-	if err := json.Unmarshal(body, &data); err != nil || len(data.Features) == 0 {
+	if len(features) == 0 {
 		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{&mcp.TextContent{Text: "No active alerts for this state."}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("No active alerts for %s.", scope)}},
 		}, nil
 	}
 
-	var alerts []string
-	for _, f := range data.Features {
-		alerts = append(alerts, formatAlert(f))
+	if format == "cap" {
+		return capResult(features)
+	}
+
+	var (
+		matcher  = alerts.NewMatcher()
+		prog     = newProgress(session, params, len(features))
+		results  = make([]dtos.AlertResult, 0, len(features))
+		texts    = make([]string, 0, len(features))
+		covering int
+	)
+	for i, f := range features {
+		result := dtos.AlertResult{
+			ID:       f.ID,
+			Event:    f.Event,
+			AreaDesc: f.AreaDesc,
+			Severity: f.Severity,
+			Places:   ugc.DecodeAll(f.Geocode.UGC, f.Geocode.SAME),
+			VTEC:     vtec.ParseAll(f.Parameters["VTEC"]),
+		}
+		text := formatAlert(f)
+
+		if hasPoint {
+			cov, err := matcher.Match(ctx, f, lat, lon)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				logger.FromContext(ctx).Warn("failed to match alert against point", "alert", f.ID, "error", err)
+			}
+			if cov == alerts.CoverageInside {
+				covering++
+			}
+			result.Coverage = string(cov)
+			text += "\nCovers location: " + coverageLabel(cov)
+			prog.Step(ctx, fmt.Sprintf("checked %d/%d alerts", i+1, len(features)))
+		}
+
+		results = append(results, result)
+		texts = append(texts, text)
+	}
+
+	events := alerts.GroupEvents(features)
+	if len(events) > 0 {
+		texts = append([]string{formatEvents(events)}, texts...)
+	}
+
+	if hasPoint {
+		summary := fmt.Sprintf("%d of %d active alerts for %s cover %.4f, %.4f.", covering, len(features), scope, lat, lon)
+		texts = append([]string{summary}, texts...)
 	}
 
 	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{&mcp.TextContent{Text: strings.Join(alerts, "\n")}},
+		Content:           []mcp.Content{&mcp.TextContent{Text: strings.Join(texts, "\n")}},
+		StructuredContent: map[string]any{"alerts": results, "events": events},
 	}, nil
 }
 
+// alertsScope picks the alerts URL for a zone, marine region, or state or
+// marine area code, in that order of precedence, along with a label for
+// messages. It returns a tool result when none is usable.
+func alertsScope(zone, region, area string) (string, string, *mcp.CallToolResultFor[any]) {
+	zone = strings.ToUpper(strings.TrimSpace(zone))
+	region = strings.ToUpper(strings.TrimSpace(region))
+	area = strings.ToUpper(strings.TrimSpace(area))
+
+	var text string
+	switch {
+	case zone != "":
+		if a, ok := ugc.Decode(zone); ok {
+			return nws.GetZoneAlertsURL(zone), a.Label() + " (" + zone + ")", nil
+		}
+		text = fmt.Sprintf("Unknown zone code %q. Use a UGC code such as PZZ530 or TXZ211.", zone)
+	case region != "":
+		if name, ok := ugc.MarineRegionName(region); ok {
+			return nws.GetRegionAlertsURL(region), name, nil
+		}
+		text = fmt.Sprintf("Unknown marine region %q. Use one of AL, AT, GL, GM, PA or PI.", region)
+	case area != "":
+		if _, ok := ugc.StateName(area); ok {
+			return nws.GetAlertsURL(area), area, nil
+		}
+		if name, ok := ugc.MarineAreaName(area); ok {
+			return nws.GetAlertsURL(area), name, nil
+		}
+		text = fmt.Sprintf("Unknown state or marine area code %q.", area)
+	default:
+		text = "Provide a state or marine area code, a marine region, a zone, a place or coordinates."
+	}
+	return "", "", &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}
+}
+
+// capResult renders each alert as a standalone CAP 1.2 XML document.
+func capResult(features []dtos.Feature) (*mcp.CallToolResultFor[any], error) {
+	content := make([]mcp.Content, 0, len(features))
+	for _, f := range features {
+		doc, err := cap.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, &mcp.TextContent{Text: string(doc)})
+	}
+	return &mcp.CallToolResultFor[any]{Content: content}, nil
+}
+
+// formatEvents lists each tracked hazard once, however many alert messages
+// (segments, updates) refer to it.
+func formatEvents(events []alerts.Event) string {
+	lines := []string{"Tracked events:"}
+	for _, e := range events {
+		lines = append(lines, "- "+e.Summary)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func coverageLabel(c alerts.Coverage) string {
+	switch c {
+	case alerts.CoverageInside:
+		return "YES - this location is inside the alert area"
+	case alerts.CoverageOutside:
+		return "no - this location is outside the alert area"
+	default:
+		return "unknown - the alert area could not be checked"
+	}
+}
+
 func formatAlert(f dtos.Feature) string {
 	lines := []string{
 		"Event: " + defaultString(f.AlertProperties.Event, "Unknown"),
 		"Area: " + defaultString(f.AlertProperties.AreaDesc, "Unknown"),
 		"Severity: " + defaultString(f.AlertProperties.Severity, "Unknown"),
+		"Affected places: " + defaultString(formatPlaces(ugc.DecodeAll(f.Geocode.UGC, f.Geocode.SAME)), "Unknown"),
 		"Description: " + defaultString(f.AlertProperties.Description, "No description available"),
 		"Instructions: " + defaultString(f.AlertProperties.Instruction, "No specific instructions provided"),
 	}
+	for _, v := range vtec.ParseAll(f.Parameters["VTEC"]) {
+		lines = append(lines, fmt.Sprintf("VTEC: %s #%d from %s %s (%s)", v.Name(), v.ETN, v.Office, v.ActionDescription(), v.Raw))
+	}
 	return strings.Join(lines, "\n")
 }
 
+func formatPlaces(areas []ugc.Area) string {
+	labels := make([]string, 0, len(areas))
+	for _, a := range areas {
+		labels = append(labels, a.Label())
+	}
+	return strings.Join(labels, "; ")
+}
+
 func defaultString(s, fallback string) string {
 	if strings.TrimSpace(s) == "" {
 		return fallback
@@ -0,0 +1,24 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"weather/server/dtos"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetAlertsRejectsUnknownFormat(t *testing.T) {
+	for _, format := range []string{"xml", "capp", "json"} {
+		res, err := GetAlerts(context.Background(), nil, &mcp.CallToolParamsFor[dtos.AlertsParams]{
+			Arguments: dtos.AlertsParams{State: "TX", Format: format},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(text, "Unknown format") {
+			t.Errorf("format %q: %q", format, text)
+		}
+	}
+}
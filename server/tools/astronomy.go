@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"weather/server/astro"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/nws"
+	"weather/server/tz"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetAstronomy computes sunrise, sunset, twilight, solar noon, moon phase and
+// moonrise/moonset for a location and local date. Only the timezone comes
+// from NWS; outside NWS coverage times are given in UTC.
+func GetAstronomy(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.AstronomyParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%.4f, %.4f is not a valid coordinate.", lat, lon)}},
+		}, nil
+	}
+
+	zone := "UTC"
+	point, err := nws.ResolvePoint(ctx, lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.FromContext(ctx).Debug("no NWS point for astronomy, using UTC", "error", err)
+	} else if point.TimeZone != "" {
+		zone = point.TimeZone
+	}
+	loc := tz.Load(zone)
+
+	date := time.Now().In(loc)
+	if args.Date != "" {
+		date, err = time.ParseInLocation("2006-01-02", args.Date, loc)
+		if err != nil {
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid date %q. Use YYYY-MM-DD.", args.Date)}},
+			}, nil
+		}
+	}
+
+	sun := astro.Sun(date, lat, lon)
+	moon := astro.Moon(date, lat, lon)
+	y, m, d := date.Date()
+	phase := astro.Phase(time.Date(y, m, d, 12, 0, 0, 0, loc))
+
+	a := &dtos.Astronomy{
+		Latitude:         lat,
+		Longitude:        lon,
+		Date:             date.Format("2006-01-02"),
+		TimeZone:         zone,
+		AstronomicalDawn: eventTime(sun.AstronomicalDawn),
+		NauticalDawn:     eventTime(sun.NauticalDawn),
+		CivilDawn:        eventTime(sun.CivilDawn),
+		Sunrise:          eventTime(sun.Sunrise),
+		SolarNoon:        eventTime(sun.SolarNoon),
+		Sunset:           eventTime(sun.Sunset),
+		CivilDusk:        eventTime(sun.CivilDusk),
+		NauticalDusk:     eventTime(sun.NauticalDusk),
+		AstronomicalDusk: eventTime(sun.AstronomicalDusk),
+		DayLengthMinutes: int(sun.DayLength.Round(time.Minute).Minutes()),
+		PolarDay:         sun.AlwaysUp,
+		PolarNight:       sun.AlwaysDown,
+		Moonrise:         eventTime(moon.Rise),
+		Moonset:          eventTime(moon.Set),
+		MoonPhase:        phase.Name,
+		MoonIllumination: math.Round(phase.Illumination*100) / 100,
+		MoonAgeDays:      math.Round(phase.Age*10) / 10,
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatAstronomy(a, sun, moon)}},
+		StructuredContent: a,
+	}, nil
+}
+
+// eventTime formats an event to the minute, or "" if it does not happen.
+func eventTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Round(time.Minute).Format(time.RFC3339)
+}
+
+func formatAstronomy(a *dtos.Astronomy, sun astro.SunTimes, moon astro.MoonTimes) string {
+	clock := func(t time.Time) string {
+		if t.IsZero() {
+			return "none"
+		}
+		return t.Round(time.Minute).Format("3:04 PM MST")
+	}
+	span := func(from, to time.Time) string {
+		if from.IsZero() && to.IsZero() {
+			return "none"
+		}
+		return clock(from) + " / " + clock(to)
+	}
+
+	date, _ := time.Parse("2006-01-02", a.Date)
+	lines := []string{
+		fmt.Sprintf("Sun and moon for %.4f, %.4f on %s (%s)", a.Latitude, a.Longitude, date.Format("Mon Jan 2, 2006"), a.TimeZone),
+	}
+	switch {
+	case a.PolarDay:
+		lines = append(lines, "Sunrise / sunset: the sun does not set (polar day)")
+	case a.PolarNight:
+		lines = append(lines, "Sunrise / sunset: the sun does not rise (polar night)")
+	default:
+		lines = append(lines, "Sunrise / sunset: "+span(sun.Sunrise, sun.Sunset))
+	}
+	lines = append(lines,
+		"Solar noon: "+clock(sun.SolarNoon),
+		fmt.Sprintf("Day length: %dh %02dm", a.DayLengthMinutes/60, a.DayLengthMinutes%60),
+		"Civil twilight (dawn / dusk): "+span(sun.CivilDawn, sun.CivilDusk),
+		"Nautical twilight (dawn / dusk): "+span(sun.NauticalDawn, sun.NauticalDusk),
+		"Astronomical twilight (dawn / dusk): "+span(sun.AstronomicalDawn, sun.AstronomicalDusk),
+		fmt.Sprintf("Moon: %s, %.0f%% illuminated, %.1f days old", a.MoonPhase, a.MoonIllumination*100, a.MoonAgeDays),
+	)
+	switch {
+	case moon.AlwaysUp:
+		lines = append(lines, "Moonrise / moonset: the moon is up all day")
+	case moon.AlwaysDown:
+		lines = append(lines, "Moonrise / moonset: the moon is down all day")
+	default:
+		lines = append(lines, "Moonrise: "+clock(moon.Rise), "Moonset: "+clock(moon.Set))
+	}
+	if a.TimeZone == "UTC" {
+		lines = append(lines, "Times are in UTC; the local timezone is only known for NWS-covered locations.")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"weather/server/aviation"
+	"weather/server/dtos"
+	"weather/server/nws"
+	"weather/server/weathercalc"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// tafStationTries is how many of the nearest stations are tried when looking
+// up a TAF by location; many small stations report METARs but have no TAF.
+const tafStationTries = 3
+
+// tafSource fetches raw TAFs.
+var tafSource = aviation.NewTAFSource("")
+
+// SetTAFSource replaces the source get_taf fetches from.
+func SetTAFSource(s *aviation.TAFSource) {
+	tafSource = s
+}
+
+// GetMETAR fetches and decodes the latest METAR for a station or the station
+// nearest to a location.
+func GetMETAR(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.AviationParams]) (*mcp.CallToolResultFor[any], error) {
+	stations, res, err := aviationStations(ctx, params.Arguments)
+	if res != nil || err != nil {
+		return res, err
+	}
+
+	prog := newProgress(session, params, 0)
+	prog.Step(ctx, "using station "+stations[0])
+
+	obs, err := nws.LatestObservation(ctx, stations[0])
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch the latest observation for " + stations[0] + "."}},
+		}, nil
+	}
+	if obs.RawMessage == "" {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Station " + stations[0] + " has no METAR in its latest observation."}},
+		}, nil
+	}
+
+	metar, err := aviation.DecodeMETAR(obs.RawMessage, time.Now())
+	if err != nil {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Raw METAR (could not be decoded): " + obs.RawMessage}},
+		}, nil
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatMETAR(metar)}},
+		StructuredContent: metar,
+	}, nil
+}
+
+// GetTAF fetches and decodes the current TAF for a station or the nearest
+// station to a location that issues one.
+func GetTAF(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.AviationParams]) (*mcp.CallToolResultFor[any], error) {
+	stations, res, err := aviationStations(ctx, params.Arguments)
+	if res != nil || err != nil {
+		return res, err
+	}
+	stations = stations[:min(tafStationTries, len(stations))]
+
+	prog := newProgress(session, params, len(stations))
+
+	var raw string
+	for _, station := range stations {
+		prog.Step(ctx, "checking station "+station)
+		raw, err = tafSource.Fetch(ctx, station)
+		if err == nil || !errors.Is(err, aviation.ErrNoTAF) {
+			break
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		text := "Unable to fetch a TAF for " + strings.Join(stations, ", ") + "."
+		if errors.Is(err, aviation.ErrNoTAF) {
+			text = "No TAF is issued for " + strings.Join(stations, ", ") + "."
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		}, nil
+	}
+
+	taf, err := aviation.DecodeTAF(raw, time.Now())
+	if err != nil {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Raw TAF (could not be decoded): " + raw}},
+		}, nil
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatTAF(taf)}},
+		StructuredContent: taf,
+	}, nil
+}
+
+// aviationStations returns the requested station, or the stations nearest
+// to the requested location. The error is set only when ctx is done.
+func aviationStations(ctx context.Context, args dtos.AviationParams) ([]string, *mcp.CallToolResultFor[any], error) {
+	if args.Station != "" {
+		return []string{strings.ToUpper(strings.TrimSpace(args.Station))}, nil, nil
+	}
+
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return nil, res, nil
+	}
+	stations, err := nws.NearestStations(ctx, lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unable to find an observation station near %.4f, %.4f.", lat, lon)}},
+		}, nil
+	}
+	return stations, nil, nil
+}
+
+func formatMETAR(m *aviation.METAR) string {
+	lines := []string{
+		fmt.Sprintf("%s %s observed %s", m.Station, m.Type, m.Observed.Format("Jan 2 15:04Z")),
+		"Flight category: " + defaultString(m.FlightCategory, "Unknown"),
+	}
+	lines = append(lines, formatAviationConditions(m.Conditions)...)
+	if m.Temperature != nil {
+		temp := fmt.Sprintf("Temperature: %.0f°C (%.0f°F)", *m.Temperature, weathercalc.CelsiusToFahrenheit(*m.Temperature))
+		if m.DewPoint != nil {
+			temp += fmt.Sprintf(", dew point %.0f°C (%.0f°F)", *m.DewPoint, weathercalc.CelsiusToFahrenheit(*m.DewPoint))
+		}
+		lines = append(lines, temp)
+	}
+	if m.Altimeter != nil {
+		lines = append(lines, fmt.Sprintf("Altimeter: %.2f inHg", *m.Altimeter))
+	}
+	if m.Remarks != "" {
+		lines = append(lines, "Remarks: "+m.Remarks)
+	}
+	lines = append(lines, "Raw: "+m.Raw)
+	return strings.Join(lines, "\n")
+}
+
+func formatTAF(t *aviation.TAF) string {
+	header := fmt.Sprintf("%s TAF issued %s, valid %s to %s", t.Station, t.Issued.Format("Jan 2 15:04Z"), t.ValidFrom.Format("Jan 2 15Z"), t.ValidTo.Format("Jan 2 15Z"))
+	if t.Amended {
+		header += " (amended)"
+	}
+	lines := []string{header}
+	for _, g := range t.Groups {
+		lines = append(lines, "- "+formatChangeGroup(g))
+		for _, c := range formatAviationConditions(g.Conditions) {
+			lines = append(lines, "   "+c)
+		}
+		if g.FlightCategory != "" {
+			lines = append(lines, "   Flight category: "+g.FlightCategory)
+		}
+	}
+	lines = append(lines, "Raw: "+t.Raw)
+	return strings.Join(lines, "\n")
+}
+
+func formatChangeGroup(g aviation.ChangeGroup) string {
+	span := g.From.Format("Jan 2 15:04Z") + " to " + g.To.Format("Jan 2 15:04Z")
+	switch g.Type {
+	case aviation.GroupBase:
+		return "Initially, " + span + ":"
+	case aviation.GroupFrom:
+		return "From " + span + ":"
+	case aviation.GroupTempo:
+		return "Temporarily, " + span + ":"
+	case aviation.GroupBecmg:
+		return "Becoming, " + span + ":"
+	case aviation.GroupProb:
+		kind := "chance"
+		if g.Tempo {
+			kind = "chance of temporary"
+		}
+		return fmt.Sprintf("%d%% %s, %s:", g.Probability, kind, span)
+	}
+	return span + ":"
+}
+
+func formatAviationConditions(c aviation.Conditions) []string {
+	var lines []string
+	if w := c.Wind; w != nil {
+		dir := "variable"
+		if w.Direction != nil {
+			dir = fmt.Sprintf("%03d°", *w.Direction)
+		}
+		wind := fmt.Sprintf("Wind: %s at %d %s", dir, w.Speed, strings.ToLower(w.Unit))
+		if w.Speed == 0 && w.Gust == nil {
+			wind = "Wind: calm"
+		}
+		if w.Gust != nil {
+			wind += fmt.Sprintf(" gusting %d", *w.Gust)
+		}
+		if w.VariableFrom != nil && w.VariableTo != nil {
+			wind += fmt.Sprintf(" (varying %03d°–%03d°)", *w.VariableFrom, *w.VariableTo)
+		}
+		lines = append(lines, wind)
+	}
+	if v := c.Visibility; v != nil {
+		vis := fmt.Sprintf("%g SM", math.Round(v.StatuteMiles*100)/100)
+		switch {
+		case v.GreaterThan:
+			vis = "more than " + vis
+		case v.LessThan:
+			vis = "less than " + vis
+		}
+		lines = append(lines, "Visibility: "+vis)
+	}
+	if len(c.Weather) > 0 {
+		var wx []string
+		for _, w := range c.Weather {
+			wx = append(wx, w.Description)
+		}
+		lines = append(lines, "Weather: "+strings.Join(wx, ", "))
+	}
+	if c.CAVOK {
+		lines = append(lines, "Sky: ceiling and visibility OK")
+	} else if c.SkyClear && len(c.Clouds) == 0 {
+		lines = append(lines, "Sky: clear")
+	}
+	if len(c.Clouds) > 0 {
+		var layers []string
+		for _, l := range c.Clouds {
+			layers = append(layers, formatCloud(l))
+		}
+		lines = append(lines, "Clouds: "+strings.Join(layers, ", "))
+	}
+	if c.Ceiling != nil {
+		lines = append(lines, fmt.Sprintf("Ceiling: %d ft", *c.Ceiling))
+	}
+	return lines
+}
+
+var cloudCover = map[string]string{
+	"FEW": "few", "SCT": "scattered", "BKN": "broken", "OVC": "overcast", "VV": "vertical visibility",
+}
+
+func formatCloud(l aviation.Cloud) string {
+	s := cloudCover[l.Cover]
+	if l.Height != nil {
+		s += fmt.Sprintf(" at %d ft", *l.Height)
+	}
+	switch l.Type {
+	case "CB":
+		s += " (cumulonimbus)"
+	case "TCU":
+		s += " (towering cumulus)"
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"weather/server/dtos"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAviationCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, tool := range map[string]mcp.ToolHandlerFor[dtos.AviationParams, any]{"get_metar": GetMETAR, "get_taf": GetTAF} {
+		res, err := tool(ctx, nil, &mcp.CallToolParamsFor[dtos.AviationParams]{
+			Arguments: dtos.AviationParams{Latitude: 44.9778, Longitude: -93.2650},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: result %+v, error %v; want the context's error", name, res, err)
+		}
+	}
+}
+
+func TestAviationMissingLocation(t *testing.T) {
+	res, err := GetMETAR(context.Background(), nil, &mcp.CallToolParamsFor[dtos.AviationParams]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "place or latitude/longitude") {
+		t.Errorf("missing location: %q", text)
+	}
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/pool"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	maxBatchLocations   = 10
+	maxBatchWorkers     = 4
+	defaultBatchPeriods = 3
+	maxBatchPeriods     = 14
+)
+
+// GetForecastsBatch fetches forecasts for several locations concurrently. A
+// small pool of workers shares the providers' limits, and each location
+// reports its own failure without failing the batch.
+func GetForecastsBatch(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.BatchForecastParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	switch n := len(args.Locations); {
+	case n == 0:
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Provide at least one location."}},
+		}, nil
+	case n > maxBatchLocations:
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Too many locations (%d). A batch can hold at most %d; split it into several calls.", n, maxBatchLocations)}},
+		}, nil
+	}
+	if args.Provider != "" && !slices.Contains(providers.Names(), args.Provider) {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown provider %q. Available providers: %s.", args.Provider, strings.Join(providers.Names(), ", "))}},
+		}, nil
+	}
+	periods := args.Periods
+	if periods <= 0 {
+		periods = defaultBatchPeriods
+	}
+	periods = min(periods, maxBatchPeriods)
+
+	prog := newProgress(session, params, len(args.Locations))
+	batch := &dtos.BatchForecast{Locations: make([]dtos.LocationForecast, len(args.Locations))}
+
+	err := pool.Run(ctx, len(args.Locations), maxBatchWorkers,
+		func(i int) {
+			batch.Locations[i] = forecastLocation(ctx, args.Locations[i], args.Provider, periods)
+		},
+		func(i int) {
+			prog.Step(ctx, "forecast "+locationLabel(batch.Locations[i]))
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, loc := range batch.Locations {
+		if loc.Error != "" {
+			batch.Failed++
+		}
+	}
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatBatch(batch)}},
+		StructuredContent: batch,
+	}, nil
+}
+
+// forecastLocation resolves and forecasts one batch location, recording any
+// failure in the result's Error.
+func forecastLocation(ctx context.Context, l dtos.Location, name string, periods int) dtos.LocationForecast {
+	out := dtos.LocationForecast{Place: l.Place, Latitude: l.Latitude, Longitude: l.Longitude}
+	if missingLocation(l.Latitude, l.Longitude, l.Place) {
+		out.Error = errNoLocation.Error()
+		return out
+	}
+	if l.Place != "" {
+		best, err := lookupPlace(l.Place)
+		if err != nil {
+			out.Error = err.Error()
+			return out
+		}
+		out.Place = best.Label()
+		out.Latitude, out.Longitude = best.Latitude, best.Longitude
+	}
+
+	p, err := providers.Select(name, out.Latitude, out.Longitude)
+	if err != nil {
+		out.Error = fmt.Sprintf("no weather provider covers %.4f, %.4f", out.Latitude, out.Longitude)
+		return out
+	}
+
+	forecast, err := p.Forecast(ctx, out.Latitude, out.Longitude)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Warn("batch forecast failed", "latitude", out.Latitude, "longitude", out.Longitude, "error", err)
+		}
+		out.Error = "unable to fetch forecast data"
+		return out
+	}
+	out.Source = forecast.Source
+	out.FallbackFrom = forecast.FallbackFrom
+	out.Periods = forecast.Periods[:min(periods, len(forecast.Periods))]
+	return out
+}
+
+func locationLabel(l dtos.LocationForecast) string {
+	if l.Place != "" {
+		return l.Place
+	}
+	return fmt.Sprintf("%.4f, %.4f", l.Latitude, l.Longitude)
+}
+
+func formatBatch(b *dtos.BatchForecast) string {
+	var sections []string
+	for _, l := range b.Locations {
+		lines := []string{"== " + locationLabel(l) + " =="}
+		if l.Error != "" {
+			lines = append(lines, "Error: "+l.Error)
+		} else {
+			lines = append(lines, sourceLine(l.Source, l.FallbackFrom))
+			for _, p := range l.Periods {
+				lines = append(lines, formatPeriod(p))
+			}
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	if b.Failed > 0 {
+		sections = append(sections, fmt.Sprintf("%d of %d locations failed.", b.Failed, len(b.Locations)))
+	}
+	return strings.Join(sections, "\n\n")
+}
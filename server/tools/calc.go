@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/weathercalc"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ComputeWeatherIndex derives the heat index, wind chill, dew point or
+// humidity, and apparent temperature from the given readings using the NWS
+// formulas.
+func ComputeWeatherIndex(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.WeatherIndexParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	toF, ok := temperatureUnit(args.Unit)
+	if !ok {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown temperature unit %q. Use 'F' or 'C'.", args.Unit)}},
+		}, nil
+	}
+	toMph, ok := windUnit(args.WindUnit)
+	if !ok {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown wind unit %q. Use 'mph', 'kt', 'km/h' or 'm/s'.", args.WindUnit)}},
+		}, nil
+	}
+	if rh := args.RelativeHumidity; rh != nil && (*rh <= 0 || *rh > 100) {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Relative humidity must be between 0 and 100 percent."}},
+		}, nil
+	}
+
+	temp := toF(args.Temperature)
+	rh := args.RelativeHumidity
+	var dew *float64
+	if args.DewPoint != nil {
+		d := toF(*args.DewPoint)
+		dew = &d
+	}
+	rh, dew = fillHumidity(temp, rh, dew)
+
+	var wind float64
+	result := dtos.WeatherIndexResult{
+		Temperature:      bothScales(temp),
+		RelativeHumidity: rh,
+	}
+	if args.WindSpeed != nil {
+		wind = toMph(*args.WindSpeed)
+		result.WindSpeedMph = &wind
+	}
+	if dew != nil {
+		t := bothScales(*dew)
+		result.DewPoint = &t
+	}
+
+	heatIndex, windChill, apparent := indices(temp, rh, wind)
+	if heatIndex != nil {
+		t := bothScales(*heatIndex)
+		result.HeatIndex = &t
+	}
+	if windChill != nil {
+		t := bothScales(*windChill)
+		result.WindChill = &t
+	}
+	result.ApparentTemperature = bothScales(*apparent)
+
+	switch {
+	case temp >= 80 && rh == nil:
+		result.Notes = append(result.Notes, "Heat index needs relative humidity or dew point.")
+	case temp > 50 && temp < 80 && rh != nil:
+		result.Notes = append(result.Notes, "Heat index only applies at 80°F (26.7°C) and above.")
+	}
+	switch {
+	case temp <= 50 && args.WindSpeed == nil:
+		result.Notes = append(result.Notes, "Wind chill needs a wind speed.")
+	case temp <= 50 && wind < 3:
+		result.Notes = append(result.Notes, "Wind chill only applies with wind of 3 mph or more.")
+	case temp > 50 && args.WindSpeed != nil:
+		result.Notes = append(result.Notes, "Wind chill only applies at 50°F (10°C) and below.")
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatWeatherIndex(result)}},
+		StructuredContent: result,
+	}, nil
+}
+
+// temperatureUnit returns the conversion to °F for a unit name.
+func temperatureUnit(unit string) (func(float64) float64, bool) {
+	switch strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(unit, "°"))) {
+	case "", "F", "FAHRENHEIT":
+		return func(v float64) float64 { return v }, true
+	case "C", "CELSIUS":
+		return weathercalc.CelsiusToFahrenheit, true
+	}
+	return nil, false
+}
+
+// windUnit returns the conversion to mph for a wind speed unit name.
+func windUnit(unit string) (func(float64) float64, bool) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "", "mph":
+		return func(v float64) float64 { return v }, true
+	case "kt", "kts", "knots":
+		return weathercalc.KnotsToMph, true
+	case "km/h", "kmh", "kph":
+		return weathercalc.KmhToMph, true
+	case "m/s", "mps":
+		return weathercalc.MpsToMph, true
+	}
+	return nil, false
+}
+
+func bothScales(f float64) dtos.Temperature {
+	return dtos.Temperature{
+		F: math.Round(f*10) / 10,
+		C: math.Round(weathercalc.FahrenheitToCelsius(f)*10) / 10,
+	}
+}
+
+func formatWeatherIndex(r dtos.WeatherIndexResult) string {
+	lines := []string{"Temperature: " + formatTemperature(r.Temperature)}
+	if r.RelativeHumidity != nil {
+		lines = append(lines, fmt.Sprintf("Relative humidity: %.0f%%", *r.RelativeHumidity))
+	}
+	if r.DewPoint != nil {
+		lines = append(lines, "Dew point: "+formatTemperature(*r.DewPoint))
+	}
+	if r.WindSpeedMph != nil {
+		lines = append(lines, fmt.Sprintf("Wind: %.0f mph", *r.WindSpeedMph))
+	}
+	if r.HeatIndex != nil {
+		lines = append(lines, "Heat index: "+formatTemperature(*r.HeatIndex))
+	}
+	if r.WindChill != nil {
+		lines = append(lines, "Wind chill: "+formatTemperature(*r.WindChill))
+	}
+	lines = append(lines, "Feels like: "+formatTemperature(r.ApparentTemperature))
+	for _, n := range r.Notes {
+		lines = append(lines, "Note: "+n)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTemperature(t dtos.Temperature) string {
+	return fmt.Sprintf("%.1f°F (%.1f°C)", t.F, t.C)
+}
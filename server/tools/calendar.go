@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/ics"
+	"weather/server/provider"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrInvalidRequest marks BuildCalendar errors caused by the request rather
+// than by upstream data, such as an unknown place.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// requestError is an error in the caller's input; it reads as its cause but
+// matches ErrInvalidRequest.
+type requestError struct{ err error }
+
+func (e *requestError) Error() string        { return e.err.Error() }
+func (e *requestError) Unwrap() error        { return e.err }
+func (e *requestError) Is(target error) bool { return target == ErrInvalidRequest }
+
+// BuildCalendar collects the forecast periods and/or active alerts for a
+// location as calendar events. It backs both export_calendar and the HTTP
+// calendar endpoint, so a calendar imported from one and later subscribed to
+// through the other keeps the same event UIDs.
+func BuildCalendar(ctx context.Context, args dtos.CalendarParams) (*ics.Calendar, error) {
+	include := strings.ToLower(strings.TrimSpace(args.Include))
+	if include == "" {
+		include = "all"
+	}
+	if include != "all" && include != "forecast" && include != "alerts" {
+		return nil, &requestError{fmt.Errorf("include must be 'forecast', 'alerts' or 'all', not %q", args.Include)}
+	}
+
+	if missingLocation(args.Latitude, args.Longitude, args.Place) {
+		return nil, &requestError{errNoLocation}
+	}
+	lat, lon := args.Latitude, args.Longitude
+	label := fmt.Sprintf("%.4f, %.4f", lat, lon)
+	if args.Place != "" {
+		c, err := lookupPlace(args.Place)
+		if err != nil {
+			return nil, &requestError{err}
+		}
+		lat, lon, label = c.Latitude, c.Longitude, c.Label()
+	}
+	p, err := providers.Select(args.Provider, lat, lon)
+	if err != nil {
+		if errors.Is(err, provider.ErrUnknownProvider) {
+			err = fmt.Errorf("unknown provider %q; available providers: %s", args.Provider, strings.Join(providers.Names(), ", "))
+		} else {
+			err = fmt.Errorf("no weather provider covers %.4f, %.4f", lat, lon)
+		}
+		return nil, &requestError{err}
+	}
+
+	cal := &ics.Calendar{Name: "Weather: " + label}
+	if include != "alerts" {
+		forecast, err := p.Forecast(ctx, lat, lon)
+		if err != nil {
+			return nil, fmt.Errorf("fetching forecast: %w", err)
+		}
+		cal.Events = append(cal.Events, ics.ForecastEvents(lat, lon, label, forecast.Periods)...)
+	}
+	if include != "forecast" {
+		// Forecast-only providers have no alerts; the forecast still stands.
+		features, err := p.Alerts(ctx, lat, lon)
+		if err != nil && !errors.Is(err, provider.ErrNotSupported) {
+			return nil, fmt.Errorf("fetching alerts: %w", err)
+		}
+		cal.Events = append(cal.Events, ics.AlertEvents(features)...)
+	}
+	return cal, nil
+}
+
+// ExportCalendar renders a location's forecast periods and active alerts as
+// an iCalendar document that can be imported into calendar apps.
+func ExportCalendar(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.CalendarParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	if args.Place != "" {
+		if _, res := resolvePlace(args.Place); res != nil {
+			return res, nil
+		}
+	}
+
+	prog := newProgress(session, params, 0)
+	cal, err := BuildCalendar(prog.Context(ctx), args)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		text := "Unable to fetch weather data for this calendar."
+		if errors.Is(err, ErrInvalidRequest) {
+			text = "Unable to export calendar: " + err.Error() + "."
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		}, nil
+	}
+
+	alerts := 0
+	for _, e := range cal.Events {
+		if slices.Contains(e.Categories, "Alert") {
+			alerts++
+		}
+	}
+	summary := fmt.Sprintf("%s: %d forecast periods and %d alerts as iCalendar events. Event UIDs are stable, so importing an updated export replaces earlier events. When the server runs over HTTP, calendar apps can subscribe to /calendar.ics?place=... (or lat=...&lon=...), with optional provider and include.", cal.Name, len(cal.Events)-alerts, alerts)
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: summary},
+			&mcp.TextContent{Text: string(cal.Marshal(time.Now()))},
+		},
+	}, nil
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"weather/server/chart"
+	"weather/server/dtos"
+	"weather/server/nws"
+	"weather/server/tz"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultChartHours = 48
+	maxChartHours     = 156
+	chartWidth        = 800
+	chartHeight       = 560
+)
+
+// GetForecastChart renders the hourly temperature, precipitation chance and
+// wind forecast for a location as PNG and/or SVG images.
+func GetForecastChart(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.ChartParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	format := strings.ToLower(strings.TrimSpace(args.Format))
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" && format != "both" {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown format %q. Use 'png', 'svg' or 'both'.", args.Format)}},
+		}, nil
+	}
+
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+	p, res := selectProvider(args.Provider, lat, lon)
+	if res != nil {
+		return res, nil
+	}
+
+	hours := args.Hours
+	if hours <= 0 {
+		hours = defaultChartHours
+	}
+	hours = min(hours, maxChartHours)
+
+	prog := newProgress(session, params, 0)
+	hourly, err := p.Hourly(prog.Context(ctx), lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch hourly forecast data for this location."}},
+		}, nil
+	}
+	deriveHourly(hourly.Periods)
+
+	// Label times in the location's timezone when NWS knows it, otherwise
+	// in the offset the provider reported.
+	var zone string
+	if point, err := nws.ResolvePoint(ctx, lat, lon); err == nil {
+		zone = point.TimeZone
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	c, ok := forecastChart(hourly.Periods, time.Now().Truncate(time.Hour), hours, zone)
+	if !ok {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "The hourly forecast has no data for the coming hours."}},
+		}, nil
+	}
+	c.Title = fmt.Sprintf("%.4f, %.4f", lat, lon)
+	if args.Place != "" {
+		c.Title = args.Place
+	}
+
+	content := []mcp.Content{&mcp.TextContent{Text: chartSummary(c, hourly.Source, hourly.FallbackFrom, args.Sparkline)}}
+	if format != "svg" {
+		img, err := c.PNG()
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, &mcp.ImageContent{Data: img, MIMEType: chart.PNGMediaType})
+	}
+	if format != "png" {
+		content = append(content, &mcp.ImageContent{Data: c.SVG(), MIMEType: chart.SVGMediaType})
+	}
+	return &mcp.CallToolResultFor[any]{Content: content}, nil
+}
+
+// forecastChart lays out the hours starting at from as temperature,
+// precipitation and wind panels. Missing hours are gaps. It reports false
+// when the forecast has no data in that window.
+func forecastChart(periods []dtos.HourlyPeriod, from time.Time, hours int, zone string) (*chart.Chart, bool) {
+	series := func() []float64 {
+		s := make([]float64, hours)
+		for i := range s {
+			s[i] = math.NaN()
+		}
+		return s
+	}
+	temp, feels, pop, wind, gust := series(), series(), series(), series(), series()
+	var loc *time.Location
+	var found, hasFeels, hasGust bool
+
+	for _, h := range periods {
+		start, err := time.Parse(time.RFC3339, h.StartTime)
+		if err != nil {
+			continue
+		}
+		i := int(start.Sub(from) / time.Hour)
+		if start.Before(from) || i >= hours {
+			continue
+		}
+		if loc == nil {
+			loc = start.Location()
+		}
+		found = true
+		temp[i] = h.Temperature
+		if h.ApparentTemperature != nil {
+			feels[i] = *h.ApparentTemperature
+			hasFeels = hasFeels || math.Round(*h.ApparentTemperature) != math.Round(h.Temperature)
+		}
+		if h.ProbabilityOfPrecipitation != nil {
+			pop[i] = *h.ProbabilityOfPrecipitation
+		}
+		wind[i] = h.WindSpeed
+		if h.WindGust != nil {
+			gust[i] = *h.WindGust
+			hasGust = true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	if zone != "" {
+		loc = tz.Load(zone)
+	}
+
+	tempPanel := chart.Panel{Title: "Temperature", Unit: "°F", Series: []chart.Series{{Name: "Temp", Values: temp, Color: chart.Red}}}
+	if hasFeels {
+		tempPanel.Series = append(tempPanel.Series, chart.Series{Name: "Feels like", Values: feels, Color: chart.Orange, Dashed: true})
+	}
+	windPanel := chart.Panel{Title: "Wind", Unit: "mph", FromZero: true, Series: []chart.Series{{Name: "Wind", Values: wind, Color: chart.Green}}}
+	if hasGust {
+		windPanel.Series = append(windPanel.Series, chart.Series{Name: "Gust", Values: gust, Color: chart.LightGreen, Dashed: true})
+	}
+	return &chart.Chart{
+		Start:    from,
+		Hours:    hours,
+		Location: loc,
+		Width:    chartWidth,
+		Height:   chartHeight,
+		Panels: []chart.Panel{
+			tempPanel,
+			{Title: "Precipitation chance", Unit: "%", Min: 0, Max: 100, Series: []chart.Series{{Name: "Chance", Values: pop, Color: chart.Blue, Bars: true}}},
+			windPanel,
+		},
+	}, true
+}
+
+// chartSummary describes the charted range of each series, with a sparkline
+// per series when requested.
+func chartSummary(c *chart.Chart, source string, fallbackFrom []string, sparklines bool) string {
+	end := c.Start.Add(time.Duration(c.Hours) * time.Hour)
+	lines := []string{
+		fmt.Sprintf("Forecast chart for %s, %s to %s", c.Title, c.Start.In(c.Location).Format("Mon 3 PM"), end.In(c.Location).Format("Mon 3 PM MST")),
+		sourceLine(source, fallbackFrom),
+	}
+	for _, p := range c.Panels {
+		for _, s := range p.Series {
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, v := range s.Values {
+				if !math.IsNaN(v) {
+					lo, hi = math.Min(lo, v), math.Max(hi, v)
+				}
+			}
+			if math.IsInf(lo, 0) {
+				continue
+			}
+			name := p.Title
+			if len(p.Series) > 1 {
+				name = s.Name
+			}
+			unit := p.Unit
+			if unit == "mph" {
+				unit = " " + unit
+			}
+			line := fmt.Sprintf("%s: %.0f to %.0f%s", name, lo, hi, unit)
+			if sparklines {
+				line += "  " + chart.Sparkline(s.Values)
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
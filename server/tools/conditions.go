@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/geo"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetCurrentConditions fetches the latest observed conditions near a location.
+func GetCurrentConditions(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.ConditionsParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+
+	p, res := selectProvider(args.Provider, lat, lon)
+	if res != nil {
+		return res, nil
+	}
+
+	prog := newProgress(session, params, 0)
+
+	cond, err := p.Current(prog.Context(ctx), lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch current conditions for this location."}},
+		}, nil
+	}
+	deriveConditions(&cond.CurrentConditions)
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: sourceLine(cond.Source, cond.FallbackFrom) + "\n" + formatConditions(cond.CurrentConditions)}},
+		StructuredContent: cond,
+	}, nil
+}
+
+func formatConditions(c dtos.CurrentConditions) string {
+	lines := []string{
+		"Observed: " + defaultString(c.Timestamp, "Unknown"),
+		"Conditions: " + defaultString(c.Description, "Unknown"),
+	}
+	if c.Station != "" {
+		lines = append(lines, "Station: "+c.Station)
+	}
+	if c.Temperature != nil {
+		lines = append(lines, fmt.Sprintf("Temperature: %.0f°F", *c.Temperature))
+		if c.HeatIndex != nil {
+			lines = append(lines, fmt.Sprintf("Heat index: %.0f°F", *c.HeatIndex))
+		}
+		if c.WindChill != nil {
+			lines = append(lines, fmt.Sprintf("Wind chill: %.0f°F", *c.WindChill))
+		}
+	}
+	if c.DewPoint != nil {
+		lines = append(lines, fmt.Sprintf("Dew point: %.0f°F", *c.DewPoint))
+	}
+	if c.RelativeHumidity != nil {
+		lines = append(lines, fmt.Sprintf("Humidity: %.0f%%", *c.RelativeHumidity))
+	}
+	if c.WindSpeed != nil {
+		wind := fmt.Sprintf("Wind: %.0f mph", *c.WindSpeed)
+		if c.WindDirection != nil {
+			wind += " from " + geo.CompassPoint(*c.WindDirection)
+		}
+		if c.WindGust != nil {
+			wind += fmt.Sprintf(", gusting %.0f mph", *c.WindGust)
+		}
+		lines = append(lines, wind)
+	}
+	if c.Visibility != nil {
+		lines = append(lines, fmt.Sprintf("Visibility: %.1f mi", *c.Visibility))
+	}
+	if c.Pressure != nil {
+		lines = append(lines, fmt.Sprintf("Pressure: %.1f hPa", *c.Pressure))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"weather/server/dtos"
+	"weather/server/weathercalc"
+)
+
+// deriveHourly fills in the dew point or humidity when only one is known,
+// and the heat index, wind chill and apparent temperature of each hour.
+func deriveHourly(periods []dtos.HourlyPeriod) {
+	for i := range periods {
+		p := &periods[i]
+		p.RelativeHumidity, p.DewPoint = fillHumidity(p.Temperature, p.RelativeHumidity, p.DewPoint)
+		p.HeatIndex, p.WindChill, p.ApparentTemperature = indices(p.Temperature, p.RelativeHumidity, p.WindSpeed)
+	}
+}
+
+// deriveConditions is deriveHourly for an observation.
+func deriveConditions(c *dtos.CurrentConditions) {
+	if c.Temperature == nil {
+		return
+	}
+	var wind float64
+	if c.WindSpeed != nil {
+		wind = *c.WindSpeed
+	}
+	c.RelativeHumidity, c.DewPoint = fillHumidity(*c.Temperature, c.RelativeHumidity, c.DewPoint)
+	c.HeatIndex, c.WindChill, c.ApparentTemperature = indices(*c.Temperature, c.RelativeHumidity, wind)
+}
+
+func fillHumidity(temp float64, rh, dew *float64) (*float64, *float64) {
+	switch {
+	case rh != nil && dew == nil && *rh > 0:
+		d := weathercalc.DewPoint(temp, *rh)
+		dew = &d
+	case rh == nil && dew != nil:
+		r := weathercalc.RelativeHumidity(temp, *dew)
+		rh = &r
+	}
+	return rh, dew
+}
+
+// indices returns the heat index (80°F and above), wind chill (50°F and
+// below with wind) and apparent temperature, leaving out values that do not
+// apply.
+func indices(temp float64, rh *float64, wind float64) (heatIndex, windChill, apparent *float64) {
+	if rh != nil && temp >= 80 {
+		hi := weathercalc.HeatIndex(temp, *rh)
+		heatIndex = &hi
+	}
+	if wc, ok := weathercalc.WindChill(temp, wind); ok {
+		windChill = &wc
+	}
+
+	a := temp
+	switch {
+	case windChill != nil:
+		a = *windChill
+	case heatIndex != nil:
+		a = *heatIndex
+	}
+	apparent = &a
+	return heatIndex, windChill, apparent
+}
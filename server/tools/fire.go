@@ -0,0 +1,246 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/nws"
+	"weather/server/tz"
+	"weather/server/vtec"
+	"weather/server/weathercalc"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultFireHours = 24
+	maxFireHours     = 72
+)
+
+// GetFireWeather builds a fire weather briefing for a location: active Red
+// Flag Warnings and Fire Weather Watches, the critical gridpoint values
+// (humidity, gusts, Haines index, mixing height) and the fire weather zone
+// forecast.
+func GetFireWeather(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.FireWeatherParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+
+	hours := args.Hours
+	if hours <= 0 {
+		hours = defaultFireHours
+	}
+	hours = min(hours, maxFireHours)
+
+	prog := newProgress(session, params, 4)
+	log := logger.FromContext(ctx)
+
+	point, err := nws.ResolvePoint(ctx, lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to look up this location. Fire weather forecasts are only available from NWS for the US."}},
+		}, nil
+	}
+	prog.Step(ctx, "resolved gridpoint")
+
+	briefing := &dtos.FireWeatherBriefing{
+		Latitude:        lat,
+		Longitude:       lon,
+		FireWeatherZone: nws.ZoneID(point.FireWeatherZone),
+		Office:          point.CWA,
+		TimeZone:        point.TimeZone,
+		Hours:           hours,
+	}
+	loc := tz.Load(point.TimeZone)
+
+	features, err := nws.FetchAlerts(ctx, nws.GetPointAlertsURL(lat, lon))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn("failed to fetch fire weather alerts", "error", err)
+	}
+	for _, f := range features {
+		if isFireAlert(f) {
+			briefing.Alerts = append(briefing.Alerts, dtos.AlertResult{
+				ID:       f.ID,
+				Event:    f.Event,
+				AreaDesc: f.AreaDesc,
+				Severity: f.Severity,
+				VTEC:     vtec.ParseAll(f.Parameters["VTEC"]),
+			})
+		}
+	}
+	prog.Step(ctx, "checked fire alerts")
+
+	grid, err := nws.Gridpoint(ctx, point.ForecastGridDataURL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn("failed to fetch gridpoint data", "url", point.ForecastGridDataURL, "error", err)
+	} else {
+		from := time.Now()
+		to := from.Add(time.Duration(hours) * time.Hour)
+		g := grid.Properties
+		briefing.MinRelativeHumidity = gridExtreme(g.RelativeHumidity, from, to, false, loc)
+		briefing.MaxWindGust = gridExtreme(g.WindGust, from, to, true, loc)
+		briefing.MaxHainesIndex = gridExtreme(g.HainesIndex, from, to, true, loc)
+		briefing.MaxMixingHeight = gridExtreme(g.MixingHeight, from, to, true, loc)
+	}
+	prog.Step(ctx, "fetched gridpoint data")
+
+	if briefing.FireWeatherZone != "" {
+		briefing.Forecast, err = nws.FireWeatherForecast(ctx, briefing.FireWeatherZone)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Warn("failed to fetch fire weather zone forecast", "zone", briefing.FireWeatherZone, "error", err)
+		}
+	}
+	prog.Step(ctx, "fetched fire weather forecast")
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatFireWeather(briefing)}},
+		StructuredContent: briefing,
+	}, nil
+}
+
+// isFireAlert reports whether an alert is a Red Flag Warning or Fire Weather
+// Watch (VTEC phenomenon FW).
+func isFireAlert(f dtos.Feature) bool {
+	if f.Event == "Red Flag Warning" || f.Event == "Fire Weather Watch" {
+		return true
+	}
+	for _, v := range vtec.ParseAll(f.Parameters["VTEC"]) {
+		if v.Phenomenon == "FW" {
+			return true
+		}
+	}
+	return false
+}
+
+// gridExtreme finds the lowest or highest value of a grid layer among the
+// intervals overlapping [from, to), converted to the units tools report.
+func gridExtreme(layer dtos.GridLayer, from, to time.Time, highest bool, loc *time.Location) *dtos.FireWeatherExtreme {
+	var best *dtos.FireWeatherExtreme
+	var bestValue float64
+	for _, iv := range nws.Intervals(layer) {
+		if !iv.End.After(from) || !iv.Start.Before(to) {
+			continue
+		}
+		if best != nil && (highest && iv.Value <= bestValue || !highest && iv.Value >= bestValue) {
+			continue
+		}
+		start := iv.Start
+		if start.Before(from) {
+			start = from
+		}
+		value, unit := gridUnits(layer.UnitCode, iv.Value)
+		bestValue = iv.Value
+		best = &dtos.FireWeatherExtreme{
+			Value: value,
+			Unit:  unit,
+			Time:  start.In(loc).Format("Mon Jan 2 3:04 PM MST"),
+		}
+	}
+	return best
+}
+
+// gridUnits converts a grid value to the units tools report: mph for
+// speeds and feet for heights.
+func gridUnits(unitCode string, v float64) (float64, string) {
+	switch strings.TrimPrefix(unitCode, "wmoUnit:") {
+	case "km_h-1":
+		return weathercalc.KmhToMph(v), "mph"
+	case "m_s-1":
+		return weathercalc.MpsToMph(v), "mph"
+	case "m":
+		return weathercalc.MetersToFeet(v), "ft"
+	case "percent":
+		return v, "%"
+	case "degC":
+		return weathercalc.CelsiusToFahrenheit(v), "°F"
+	}
+	return v, ""
+}
+
+func formatFireWeather(b *dtos.FireWeatherBriefing) string {
+	lines := []string{
+		fmt.Sprintf("Fire weather for %.4f, %.4f (fire weather zone %s, office %s)", b.Latitude, b.Longitude, defaultString(b.FireWeatherZone, "unknown"), defaultString(b.Office, "unknown")),
+	}
+
+	if len(b.Alerts) == 0 {
+		lines = append(lines, "Fire alerts: none active")
+	} else {
+		for _, a := range b.Alerts {
+			alert := "Fire alert: " + a.Event
+			for _, v := range a.VTEC {
+				if v.End != nil {
+					alert += " until " + v.End.In(tz.Load(b.TimeZone)).Format("Mon Jan 2 3:04 PM MST")
+					break
+				}
+			}
+			lines = append(lines, alert)
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf("Next %d hours (gridpoint data):", b.Hours))
+	lines = append(lines,
+		"- Minimum relative humidity: "+formatExtreme(b.MinRelativeHumidity, "%.0f%s"),
+		"- Maximum wind gust: "+formatExtreme(b.MaxWindGust, "%.0f %s"),
+		"- Maximum Haines index: "+formatExtreme(b.MaxHainesIndex, "%.0f%s")+hainesLabel(b.MaxHainesIndex),
+		"- Maximum mixing height: "+formatExtreme(b.MaxMixingHeight, "%.0f %s"),
+	)
+
+	if f := b.Forecast; f != nil {
+		source := "Fire weather forecast: " + f.Source
+		if f.Issued != "" {
+			source += ", issued " + f.Issued
+		}
+		lines = append(lines, source)
+		for _, h := range f.Headlines {
+			lines = append(lines, "..."+h+"...")
+		}
+		for _, p := range f.Periods {
+			lines = append(lines, "- "+p.Name+": "+p.DetailedForecast)
+		}
+	} else {
+		lines = append(lines, "Fire weather forecast: not available")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatExtreme(e *dtos.FireWeatherExtreme, format string) string {
+	if e == nil {
+		return "not available"
+	}
+	return fmt.Sprintf(format, e.Value, e.Unit) + " at " + e.Time
+}
+
+// hainesLabel describes the Haines index: the potential for a wildfire to
+// grow large or behave erratically given atmospheric stability and dryness.
+func hainesLabel(e *dtos.FireWeatherExtreme) string {
+	if e == nil {
+		return ""
+	}
+	switch {
+	case e.Value >= 6:
+		return " (high potential for large fire growth)"
+	case e.Value >= 5:
+		return " (moderate potential)"
+	case e.Value >= 4:
+		return " (low potential)"
+	default:
+		return " (very low potential)"
+	}
+}
@@ -2,48 +2,40 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"weather/server/dtos"
-	"weather/server/nws"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// GetForecast fetches the forecast for a location from the provider covering it.
+// It reports progress after each upstream call when the caller supplied a progress token.
 func GetForecast(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.ForecastParams]) (*mcp.CallToolResultFor[any], error) {
-	pointsURL := nws.GetForecastURL(params.Arguments.Latitude, params.Arguments.Longitude)
-
-	pointsBody, err := nws.MakeNWSRequest(pointsURL)
-	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch forecast data for this location."}},
-		}, nil
+	lat, lon, res := resolveLocation(params.Arguments.Latitude, params.Arguments.Longitude, params.Arguments.Place)
+	if res != nil {
+		return res, nil
 	}
 
-	pointsData := dtos.PointsData{}
-	if err := json.Unmarshal(pointsBody, &pointsData); err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to parse forecast point data."}},
-		}, nil
+	p, res := selectProvider(params.Arguments.Provider, lat, lon)
+	if res != nil {
+		return res, nil
 	}
 
-	forecastBody, err := nws.MakeNWSRequest(pointsData.Properties.ForecastURL)
-	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch detailed forecast."}},
-		}, nil
-	}
+	prog := newProgress(session, params, 0)
 
-	forecastData := dtos.ForecastData{}
-	if err := json.Unmarshal(forecastBody, &forecastData); err != nil {
+	forecast, err := p.Forecast(prog.Context(ctx), lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to parse forecast data."}},
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch forecast data for this location."}},
 		}, nil
 	}
 
-	var forecasts []string
-	for i, period := range forecastData.Properties.Periods {
+	forecasts := []string{sourceLine(forecast.Source, forecast.FallbackFrom)}
+	for i, period := range forecast.Periods {
 		if i >= 3 {
 			break
 		}
@@ -51,7 +43,8 @@ func GetForecast(ctx context.Context, session *mcp.ServerSession, params *mcp.Ca
 	}
 
 	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{&mcp.TextContent{Text: strings.Join(forecasts, "\n")}},
+		Content:           []mcp.Content{&mcp.TextContent{Text: strings.Join(forecasts, "\n")}},
+		StructuredContent: forecast,
 	}, nil
 }
 
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/gazetteer"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultGeocodeLimit = 5
+
+// Geocode resolves a place name, county or ZIP code to candidate coordinates
+// using the embedded offline gazetteer.
+func Geocode(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.GeocodeParams]) (*mcp.CallToolResultFor[any], error) {
+	limit := params.Arguments.Limit
+	if limit <= 0 {
+		limit = defaultGeocodeLimit
+	}
+
+	cands := gazetteer.Lookup(params.Arguments.Query, limit)
+	if len(cands) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No matching places found."}},
+		}, nil
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatCandidates(cands)}},
+		StructuredContent: map[string]any{"candidates": cands},
+	}, nil
+}
+
+// resolvePlace turns a place argument into coordinates. When the place cannot
+// be resolved unambiguously, it returns a tool result listing the candidates
+// so the model can retry with a more specific place or explicit coordinates.
+func resolvePlace(place string) (gazetteer.Candidate, *mcp.CallToolResultFor[any]) {
+	best, cands, ok := gazetteer.Resolve(place)
+	if ok {
+		return best, nil
+	}
+
+	text := fmt.Sprintf("Could not find a place matching %q.", place)
+	if len(cands) > 0 {
+		text = fmt.Sprintf("%q is ambiguous. Candidates:\n%s\nRetry with a more specific place or with latitude/longitude.", place, formatCandidates(cands))
+	}
+	return gazetteer.Candidate{}, &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+		StructuredContent: map[string]any{"candidates": cands},
+	}
+}
+
+// lookupPlace resolves a place for tools that report failures per item
+// rather than as the whole tool result.
+func lookupPlace(place string) (gazetteer.Candidate, error) {
+	best, cands, ok := gazetteer.Resolve(place)
+	if ok {
+		return best, nil
+	}
+	if len(cands) == 0 {
+		return gazetteer.Candidate{}, fmt.Errorf("could not find a place matching %q", place)
+	}
+	labels := make([]string, len(cands))
+	for i, c := range cands {
+		labels[i] = c.Label()
+	}
+	return gazetteer.Candidate{}, fmt.Errorf("%q is ambiguous (%s); use a more specific place or coordinates", place, strings.Join(labels, "; "))
+}
+
+// errNoLocation is reported for a location with neither a place nor
+// coordinates. Latitude and longitude are optional in the schemas, so an
+// omitted location would otherwise silently become 0, 0.
+var errNoLocation = errors.New("give a place or latitude/longitude")
+
+// missingLocation reports whether neither a place nor coordinates were given.
+func missingLocation(lat, lon float64, place string) bool {
+	return place == "" && lat == 0 && lon == 0
+}
+
+// resolveLocation returns the coordinates to use for a tool call that accepts
+// either latitude/longitude or a place.
+func resolveLocation(lat, lon float64, place string) (float64, float64, *mcp.CallToolResultFor[any]) {
+	if missingLocation(lat, lon, place) {
+		return 0, 0, &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Give a place or latitude/longitude."}},
+		}
+	}
+	if place == "" {
+		return lat, lon, nil
+	}
+	c, res := resolvePlace(place)
+	if res != nil {
+		return 0, 0, res
+	}
+	return c.Latitude, c.Longitude, nil
+}
+
+func formatCandidates(cands []gazetteer.Candidate) string {
+	lines := make([]string, 0, len(cands))
+	for _, c := range cands {
+		lines = append(lines, fmt.Sprintf("- %s [%s]: %.4f, %.4f (match %.0f%%)", c.Label(), c.Kind, c.Latitude, c.Longitude, c.Score*100))
+	}
+	return strings.Join(lines, "\n")
+}
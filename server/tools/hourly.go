@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/provider"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultHourlyHours = 12
+	maxHourlyHours     = 156
+)
+
+// GetHourlyForecast fetches the hour-by-hour forecast for a location.
+func GetHourlyForecast(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.HourlyParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+
+	hours := args.Hours
+	if hours <= 0 {
+		hours = defaultHourlyHours
+	}
+	hours = min(hours, maxHourlyHours)
+
+	prog := newProgress(session, params, 0)
+
+	if args.Ensemble {
+		return ensembleHourly(prog.Context(ctx), lat, lon, hours)
+	}
+
+	p, res := selectProvider(args.Provider, lat, lon)
+	if res != nil {
+		return res, nil
+	}
+
+	hourly, err := p.Hourly(prog.Context(ctx), lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch hourly forecast for this location."}},
+		}, nil
+	}
+
+	periods := hourly.Periods[:min(hours, len(hourly.Periods))]
+	deriveHourly(periods)
+	lines := []string{sourceLine(hourly.Source, hourly.FallbackFrom)}
+	for _, h := range periods {
+		lines = append(lines, formatHour(h))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: strings.Join(lines, "\n")}},
+		StructuredContent: map[string]any{"source": hourly.Source, "fallbackFrom": hourly.FallbackFrom, "periods": periods},
+	}, nil
+}
+
+func formatHour(h dtos.HourlyPeriod) string {
+	return fmt.Sprintf("- %s: %s", h.StartTime, hourSummary(h))
+}
+
+// hourSummary describes an hour's weather, e.g. "Sunny, 75°F, wind S 5 mph".
+func hourSummary(h dtos.HourlyPeriod) string {
+	parts := []string{fmt.Sprintf("%.0f°F", h.Temperature)}
+	if a := h.ApparentTemperature; a != nil && math.Round(*a) != math.Round(h.Temperature) {
+		parts = append(parts, fmt.Sprintf("feels like %.0f°F", *a))
+	}
+	if h.ProbabilityOfPrecipitation != nil {
+		parts = append(parts, fmt.Sprintf("precip %.0f%%", *h.ProbabilityOfPrecipitation))
+	}
+	if h.RelativeHumidity != nil {
+		parts = append(parts, fmt.Sprintf("RH %.0f%%", *h.RelativeHumidity))
+	}
+	wind := fmt.Sprintf("wind %s %.0f mph", defaultString(h.WindDirection, ""), h.WindSpeed)
+	if h.WindGust != nil {
+		wind += fmt.Sprintf(" gusting %.0f", *h.WindGust)
+	}
+	parts = append(parts, wind)
+
+	return defaultString(h.ShortForecast, "Unknown") + ", " + strings.Join(parts, ", ")
+}
+
+// ensembleHourly merges the hourly forecasts of all providers covering the
+// location, starting at the current hour.
+func ensembleHourly(ctx context.Context, lat, lon float64, hours int) (*mcp.CallToolResultFor[any], error) {
+	members := providers.Covering(lat, lon)
+	if len(members) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("No weather provider covers %.4f, %.4f.", lat, lon)}},
+		}, nil
+	}
+
+	ens, err := provider.Ensemble(ctx, members, lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch hourly forecast for this location."}},
+		}, nil
+	}
+
+	now := time.Now().Truncate(time.Hour)
+	ens.Hours = slices.DeleteFunc(ens.Hours, func(h provider.EnsembleHour) bool { return h.Start().Before(now) })
+	ens.Hours = ens.Hours[:min(hours, len(ens.Hours))]
+
+	lines := []string{"Ensemble of " + strings.Join(ens.Sources, ", ")}
+	for name, reason := range ens.Failed {
+		lines = append(lines, fmt.Sprintf("(%s unavailable: %s)", name, reason))
+	}
+	for _, h := range ens.Hours {
+		lines = append(lines, formatEnsembleHour(h))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: strings.Join(lines, "\n")}},
+		StructuredContent: ens,
+	}, nil
+}
+
+func formatEnsembleHour(h provider.EnsembleHour) string {
+	t := h.Temperature
+	line := fmt.Sprintf("- %s: %.0f°F (%.0f–%.0f°F, spread %.0f°F, %d providers)", h.StartTime, t.Mean, t.Min, t.Max, t.Spread, len(t.Members))
+	if pop := h.ProbabilityOfPrecipitation; pop != nil {
+		line += fmt.Sprintf(", precip %.0f%% (%.0f–%.0f%%)", pop.Mean, pop.Min, pop.Max)
+	}
+	return line
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/geo"
+	"weather/server/nws"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LocatePoint reports the NWS forecast zone, county, fire weather zone,
+// forecast office and timezone for a location.
+func LocatePoint(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.LocateParams]) (*mcp.CallToolResultFor[any], error) {
+	lat, lon, res := resolveLocation(params.Arguments.Latitude, params.Arguments.Longitude, params.Arguments.Place)
+	if res != nil {
+		return res, nil
+	}
+
+	point, err := nws.ResolvePoint(ctx, lat, lon)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to look up this location. NWS only covers the US and its territories."}},
+		}, nil
+	}
+
+	loc := dtos.PointLocation{
+		Latitude:        lat,
+		Longitude:       lon,
+		ForecastZone:    nws.ZoneID(point.ForecastZone),
+		County:          nws.ZoneID(point.County),
+		FireWeatherZone: nws.ZoneID(point.FireWeatherZone),
+		Office:          point.CWA,
+		TimeZone:        point.TimeZone,
+		NearestCity:     point.RelativeLocation.Properties.City,
+		NearestState:    point.RelativeLocation.Properties.State,
+		BearingDeg:      point.RelativeLocation.Properties.Bearing.Value,
+	}
+	if d := point.RelativeLocation.Properties.Distance.Value; d != nil {
+		km := *d / 1000
+		loc.DistanceKm = &km
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatLocation(loc)}},
+		StructuredContent: loc,
+	}, nil
+}
+
+func formatLocation(loc dtos.PointLocation) string {
+	near := defaultString(loc.NearestCity, "Unknown")
+	if loc.NearestState != "" {
+		near += ", " + loc.NearestState
+	}
+	if loc.DistanceKm != nil && loc.BearingDeg != nil {
+		near = fmt.Sprintf("%.1f km %s of %s", *loc.DistanceKm, geo.CompassPoint(*loc.BearingDeg), near)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Location: %.4f, %.4f", loc.Latitude, loc.Longitude),
+		"Near: " + near,
+		"Forecast zone: " + defaultString(loc.ForecastZone, "Unknown"),
+		"County: " + defaultString(loc.County, "Unknown"),
+		"Fire weather zone: " + defaultString(loc.FireWeatherZone, "Unknown"),
+		"Forecast office (WFO): " + defaultString(loc.Office, "Unknown"),
+		"Timezone: " + defaultString(loc.TimeZone, "Unknown"),
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"weather/server/dtos"
+	"weather/server/logger"
+	"weather/server/nws"
+	"weather/server/ugc"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var marineZonePattern = regexp.MustCompile(`^[A-Z]{2}Z\d{3}$`)
+
+// GetMarineForecast fetches the coastal, offshore or Great Lakes forecast for
+// a marine zone, along with any active alerts for it.
+func GetMarineForecast(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.MarineParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	zone := strings.ToUpper(strings.TrimSpace(args.Zone))
+	prog := newProgress(session, params, 0)
+
+	if zone == "" {
+		if args.Latitude == 0 && args.Longitude == 0 {
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: "Provide a marine zone code (e.g. PZZ530) or coordinates on the water."}},
+			}, nil
+		}
+		id, err := nws.ZoneAtPoint(ctx, "marine", args.Latitude, args.Longitude)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("No marine zone found at %.4f, %.4f. Marine forecasts cover coastal waters, offshore waters and the Great Lakes.", args.Latitude, args.Longitude)}},
+			}, nil
+		}
+		zone = id
+		prog.Step(ctx, "found marine zone "+zone)
+	}
+
+	if a, ok := ugc.Decode(zone); !marineZonePattern.MatchString(zone) || !ok || a.Kind != ugc.KindMarine {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%q is not a marine zone. Marine zones look like PZZ530 (coastal Pacific) or ANZ335 (Atlantic); use get_forecast for land locations.", zone)}},
+		}, nil
+	}
+
+	forecast, err := nws.MarineForecast(ctx, zone)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to fetch a marine forecast for " + zone + "."}},
+		}, nil
+	}
+	prog.Step(ctx, "fetched marine forecast")
+
+	features, err := nws.FetchAlerts(ctx, nws.GetZoneAlertsURL(zone))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.FromContext(ctx).Warn("failed to fetch marine alerts", "zone", zone, "error", err)
+	}
+	for _, f := range features {
+		forecast.Alerts = append(forecast.Alerts, dtos.AlertResult{
+			ID:       f.ID,
+			Event:    f.Event,
+			AreaDesc: f.AreaDesc,
+			Severity: f.Severity,
+		})
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatMarineForecast(forecast)}},
+		StructuredContent: forecast,
+	}, nil
+}
+
+func formatMarineForecast(f *dtos.MarineForecast) string {
+	lines := []string{"Marine forecast for " + f.Zone + ": " + defaultString(f.Name, "Unknown zone")}
+	source := "Source: " + f.Source
+	if f.Issued != "" {
+		source += ", issued " + f.Issued
+	}
+	lines = append(lines, source)
+
+	for _, a := range f.Alerts {
+		lines = append(lines, "Active alert: "+a.Event)
+	}
+	for _, h := range f.Headlines {
+		lines = append(lines, "..."+h+"...")
+	}
+	for _, p := range f.Periods {
+		lines = append(lines, "- "+p.Name+": "+p.DetailedForecast)
+	}
+	if len(f.Periods) == 0 {
+		lines = append(lines, "No forecast periods available.")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"weather/server/provider"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progress reports the advancement of a multi-step tool call back to the caller.
+// Notifications are only sent when the caller attached a progress token to the
+// request; otherwise every method is a no-op.
+type progress struct {
+	session *mcp.ServerSession
+	token   any
+	total   float64
+	done    float64
+}
+
+// newProgress creates a progress reporter for a tool call made of total steps.
+// A total of zero means the number of steps is not known in advance.
+func newProgress(session *mcp.ServerSession, params interface{ GetProgressToken() any }, total int) *progress {
+	return &progress{
+		session: session,
+		token:   params.GetProgressToken(),
+		total:   float64(total),
+	}
+}
+
+// Step marks one more step as completed and notifies the caller with message.
+func (p *progress) Step(ctx context.Context, message string) {
+	p.done++
+	if p.session == nil || p.token == nil {
+		return
+	}
+
+	err := p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      p.done,
+		Total:         p.total,
+		Message:       message,
+	})
+	if err != nil {
+		slog.Warn("failed to send progress notification", "error", err)
+	}
+}
+
+// Context returns a context whose provider calls report each upstream step
+// through p.
+func (p *progress) Context(ctx context.Context) context.Context {
+	return provider.WithProgress(ctx, func(message string) { p.Step(ctx, message) })
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"weather/server/provider"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// providers routes forecast requests to a weather backend.
+var providers = provider.NewRouter(provider.NewNWS(), provider.NewOpenMeteo(""))
+
+// SetProviders replaces the weather providers used by the tools.
+func SetProviders(r *provider.Router) {
+	providers = r
+}
+
+// selectProvider picks the provider for a tool call. When none fits, it
+// returns a tool result explaining why.
+func selectProvider(name string, lat, lon float64) (provider.Provider, *mcp.CallToolResultFor[any]) {
+	p, err := providers.Select(name, lat, lon)
+	if err == nil {
+		return p, nil
+	}
+
+	text := fmt.Sprintf("No weather provider covers %.4f, %.4f.", lat, lon)
+	if errors.Is(err, provider.ErrUnknownProvider) {
+		text = fmt.Sprintf("Unknown provider %q. Available providers: %s.", name, strings.Join(providers.Names(), ", "))
+	}
+	return nil, &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}
+}
+
+// sourceLine names the provider that served a result, noting any providers
+// that were skipped on the way.
+func sourceLine(source string, fallbackFrom []string) string {
+	if len(fallbackFrom) == 0 {
+		return "Source: " + source
+	}
+	return fmt.Sprintf("Source: %s (fallback; %s unavailable)", source, strings.Join(fallbackFrom, ", "))
+}
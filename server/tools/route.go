@@ -0,0 +1,397 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/geo"
+	"weather/server/logger"
+	"weather/server/nws"
+	"weather/server/pool"
+	"weather/server/tz"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultRouteSpeed    = 55
+	defaultRouteInterval = 50
+	maxRouteStops        = 20
+	maxRouteWorkers      = 4
+)
+
+// GetRouteWeather builds a weather timeline for a trip: it samples points
+// along the route, estimates when the traveller reaches each one, and
+// reports the hourly forecast valid then and the active alerts covering it.
+func GetRouteWeather(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.RouteWeatherParams]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+	path, err := routePath(args.Points, args.LineString)
+	if err != nil {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Invalid route: " + err.Error() + "."}},
+		}, nil
+	}
+
+	speed := args.SpeedMph
+	if speed <= 0 {
+		speed = defaultRouteSpeed
+	}
+	distance := geo.PathMiles(path)
+	interval := args.IntervalMiles
+	if interval <= 0 {
+		interval = defaultRouteInterval
+	}
+	interval = max(interval, distance/(maxRouteStops-1))
+
+	start := tz.Load("")
+	if point, err := nws.ResolvePoint(ctx, path[0][1], path[0][0]); err == nil {
+		start = tz.Load(point.TimeZone)
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	departure := time.Now().In(start)
+	if args.Departure != "" {
+		departure, err = parseLocalTime(args.Departure, start)
+		if err != nil {
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid departure %q. Use RFC 3339 (2025-06-20T06:00:00-05:00) or local time (2025-06-20T06:00).", args.Departure)}},
+			}, nil
+		}
+	}
+	travel := func(miles float64) time.Duration {
+		return time.Duration(miles / speed * float64(time.Hour))
+	}
+
+	samples := thinSamples(geo.SamplePath(path, interval), maxRouteStops)
+	route := &dtos.RouteWeather{
+		DistanceMiles: roundMiles(distance),
+		SpeedMph:      speed,
+		Departure:     departure.Format(time.RFC3339),
+		Stops:         make([]dtos.RouteStop, len(samples)),
+	}
+	features := make([][]dtos.Feature, len(samples))
+	states := make([]string, len(samples))
+	prog := newProgress(session, params, len(samples))
+
+	err = pool.Run(ctx, len(samples), maxRouteWorkers,
+		func(i int) {
+			s := samples[i]
+			route.Stops[i], features[i], states[i] = routeStop(ctx, s, departure.Add(travel(s.Miles)))
+		},
+		func(i int) {
+			prog.Step(ctx, fmt.Sprintf("forecast for mile %.0f", samples[i].Miles))
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	crossings, err := routeCrossings(ctx, path, states, func(miles float64) time.Time { return departure.Add(travel(miles)) })
+	if err != nil {
+		return nil, err
+	}
+
+	route.Arrival = route.Stops[len(route.Stops)-1].Arrival
+	route.Alerts = routeAlerts(route.Stops, features, crossings)
+
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatRoute(route)}},
+		StructuredContent: route,
+	}, nil
+}
+
+// routePath builds the route from waypoints or a GeoJSON LineString.
+func routePath(points []dtos.Location, line *dtos.LineString) ([]geo.Position, error) {
+	var path []geo.Position
+	switch {
+	case line != nil:
+		if line.Type != "LineString" {
+			return nil, fmt.Errorf("lineString must have type LineString, not %q", line.Type)
+		}
+		for _, c := range line.Coordinates {
+			if len(c) < 2 {
+				return nil, fmt.Errorf("each position needs a longitude and latitude")
+			}
+			path = append(path, geo.Position{c[0], c[1]})
+		}
+	default:
+		for i, p := range points {
+			if missingLocation(p.Latitude, p.Longitude, p.Place) {
+				return nil, fmt.Errorf("point %d: %w", i+1, errNoLocation)
+			}
+			lat, lon := p.Latitude, p.Longitude
+			if p.Place != "" {
+				c, err := lookupPlace(p.Place)
+				if err != nil {
+					return nil, err
+				}
+				lat, lon = c.Latitude, c.Longitude
+			}
+			path = append(path, geo.Position{lon, lat})
+		}
+	}
+
+	if len(path) < 2 {
+		return nil, fmt.Errorf("give at least two points or a lineString")
+	}
+	for _, p := range path {
+		if p[1] < -90 || p[1] > 90 || p[0] < -180 || p[0] > 180 {
+			return nil, fmt.Errorf("%.4f, %.4f is not a valid coordinate", p[1], p[0])
+		}
+	}
+	if geo.PathMiles(path) == 0 {
+		return nil, fmt.Errorf("the route has no length")
+	}
+	return path, nil
+}
+
+// thinSamples keeps at most n samples, spread evenly and always including
+// the first and last.
+func thinSamples(samples []geo.Sample, n int) []geo.Sample {
+	if len(samples) <= n {
+		return samples
+	}
+	out := make([]geo.Sample, n)
+	for i := range out {
+		out[i] = samples[i*(len(samples)-1)/(n-1)]
+	}
+	return out
+}
+
+// routeStop fetches the forecast valid at arrival and the active alerts for
+// one point along the route, and returns the state the point is in. Failures
+// are recorded on the stop.
+func routeStop(ctx context.Context, s geo.Sample, arrival time.Time) (dtos.RouteStop, []dtos.Feature, string) {
+	log := logger.FromContext(ctx)
+	stop := dtos.RouteStop{
+		Miles:     roundMiles(s.Miles),
+		Latitude:  s.Lat,
+		Longitude: s.Lon,
+		Arrival:   arrival.Format(time.RFC3339),
+	}
+
+	var (
+		features []dtos.Feature
+		state    string
+	)
+	if point, err := nws.ResolvePoint(ctx, s.Lat, s.Lon); err == nil {
+		rel := point.RelativeLocation.Properties
+		state = rel.State
+		if rel.City != "" {
+			stop.Near = rel.City + ", " + rel.State
+		}
+		stop.TimeZone = point.TimeZone
+		stop.Arrival = arrival.In(tz.Load(point.TimeZone)).Format(time.RFC3339)
+
+		features, err = nws.FetchAlerts(ctx, nws.GetPointAlertsURL(s.Lat, s.Lon))
+		if err != nil && ctx.Err() == nil {
+			log.Warn("failed to fetch route alerts", "latitude", s.Lat, "longitude", s.Lon, "error", err)
+		}
+	}
+	features = slices.DeleteFunc(features, func(f dtos.Feature) bool { return !activeAt(f, arrival) })
+	for _, f := range features {
+		stop.Alerts = append(stop.Alerts, f.Event)
+	}
+
+	p, err := providers.Select("", s.Lat, s.Lon)
+	if err != nil {
+		stop.Error = "no weather provider covers this point"
+		return stop, features, state
+	}
+	hourly, err := p.Hourly(ctx, s.Lat, s.Lon)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn("route forecast failed", "latitude", s.Lat, "longitude", s.Lon, "error", err)
+		}
+		stop.Error = "unable to fetch forecast data"
+		return stop, features, state
+	}
+	stop.Source = hourly.Source
+	for i, h := range hourly.Periods {
+		t, err := time.Parse(time.RFC3339, h.StartTime)
+		if err == nil && !arrival.Before(t) && arrival.Before(t.Add(time.Hour)) {
+			deriveHourly(hourly.Periods[i : i+1])
+			stop.Forecast = &hourly.Periods[i]
+			break
+		}
+	}
+	if stop.Forecast == nil {
+		stop.Error = "arrival is outside the hourly forecast range"
+	}
+	return stop, features, state
+}
+
+// activeAt reports whether an alert is in effect at t.
+func activeAt(f dtos.Feature, t time.Time) bool {
+	return activeDuring(f, t, t)
+}
+
+// activeDuring reports whether an alert is in effect at any time from from
+// to to: it begins (onset, or effective when there is no onset) no later
+// than to and has not ended (ends, or expires when there is no end) by from.
+func activeDuring(f dtos.Feature, from, to time.Time) bool {
+	begins := defaultString(f.Onset, f.Effective)
+	if b, err := time.Parse(time.RFC3339, begins); err == nil && to.Before(b) {
+		return false
+	}
+	ends := defaultString(f.Ends, f.Expires)
+	if e, err := time.Parse(time.RFC3339, ends); err == nil && !from.Before(e) {
+		return false
+	}
+	return true
+}
+
+// routeCrossing is an alert polygon the route passes through while the
+// alert is in effect.
+type routeCrossing struct {
+	feature dtos.Feature
+	spans   []geo.Span
+}
+
+// routeCrossings fetches the active alerts for the states along the route
+// and intersects their polygons with the route itself, so that alerts
+// covering only the stretch between two stops, such as storm-based
+// warnings, are not missed. at gives the time the traveller reaches a mile.
+func routeCrossings(ctx context.Context, path []geo.Position, states []string, at func(miles float64) time.Time) ([]routeCrossing, error) {
+	log := logger.FromContext(ctx)
+	var out []routeCrossing
+	for _, state := range slices.Compact(slices.Sorted(slices.Values(states))) {
+		if state == "" {
+			continue
+		}
+		features, err := nws.FetchAlerts(ctx, nws.GetAlertsURL(state))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Warn("failed to fetch route alerts", "state", state, "error", err)
+			continue
+		}
+		for _, f := range features {
+			if f.Geometry == nil {
+				continue
+			}
+			shape, err := geo.ParseShape(f.Geometry.Type, f.Geometry.Coordinates)
+			if err != nil {
+				log.Warn("skipping alert with invalid geometry", "id", f.ID, "error", err)
+				continue
+			}
+			spans := slices.DeleteFunc(shape.PathSpans(path), func(s geo.Span) bool {
+				return !activeDuring(f, at(s.From), at(s.To))
+			})
+			if len(spans) > 0 {
+				out = append(out, routeCrossing{feature: f, spans: spans})
+			}
+		}
+	}
+	return out, nil
+}
+
+// routeAlerts merges the alerts found at each stop with those whose polygons
+// the route crosses, recording the miles at which each one applies: the
+// stops it covers and where the route enters and leaves it.
+func routeAlerts(stops []dtos.RouteStop, features [][]dtos.Feature, crossings []routeCrossing) []dtos.RouteAlert {
+	out := []dtos.RouteAlert{}
+	index := map[string]int{}
+	add := func(f dtos.Feature, timeZone string, miles ...float64) {
+		j, ok := index[f.ID]
+		if !ok {
+			j = len(out)
+			index[f.ID] = j
+			out = append(out, dtos.RouteAlert{
+				ID:       f.ID,
+				Event:    f.Event,
+				Severity: f.Severity,
+				Headline: f.Headline,
+				Ends:     defaultString(f.Ends, f.Expires),
+				TimeZone: timeZone,
+			})
+		}
+		out[j].Miles = append(out[j].Miles, miles...)
+	}
+
+	for i, fs := range features {
+		for _, f := range fs {
+			add(f, stops[i].TimeZone, stops[i].Miles)
+		}
+	}
+	for _, c := range crossings {
+		for _, s := range c.spans {
+			add(c.feature, stopBefore(stops, s.From).TimeZone, roundMiles(s.From), roundMiles(s.To))
+		}
+	}
+	for i := range out {
+		slices.Sort(out[i].Miles)
+		out[i].Miles = slices.Compact(out[i].Miles)
+	}
+	return out
+}
+
+// stopBefore returns the last stop at or before miles.
+func stopBefore(stops []dtos.RouteStop, miles float64) dtos.RouteStop {
+	i, _ := slices.BinarySearchFunc(stops, miles, func(s dtos.RouteStop, m float64) int {
+		return cmp.Compare(s.Miles, m)
+	})
+	if i < len(stops) && stops[i].Miles == miles {
+		return stops[i]
+	}
+	return stops[max(i-1, 0)]
+}
+
+func roundMiles(m float64) float64 {
+	return math.Round(m)
+}
+
+func formatRoute(r *dtos.RouteWeather) string {
+	clock := func(s, zone string) string {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.In(tz.Load(zone)).Format("Mon 3:04 PM MST")
+	}
+
+	first, last := r.Stops[0], r.Stops[len(r.Stops)-1]
+	lines := []string{fmt.Sprintf("Route: %.0f miles at %.0f mph, departing %s, arriving %s",
+		r.DistanceMiles, r.SpeedMph, clock(r.Departure, first.TimeZone), clock(r.Arrival, last.TimeZone))}
+
+	if len(r.Alerts) == 0 {
+		lines = append(lines, "Alerts along the route: none in effect when you pass")
+	} else {
+		lines = append(lines, "Alerts along the route:")
+		for _, a := range r.Alerts {
+			where := fmt.Sprintf("mile %.0f", a.Miles[0])
+			if n := len(a.Miles); n > 1 {
+				where = fmt.Sprintf("miles %.0f-%.0f", a.Miles[0], a.Miles[n-1])
+			}
+			line := fmt.Sprintf("- %s (%s) at %s", a.Event, defaultString(a.Severity, "Unknown"), where)
+			if a.Ends != "" {
+				line += ", until " + clock(a.Ends, a.TimeZone)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, "Timeline:")
+	for _, s := range r.Stops {
+		place := fmt.Sprintf("%.4f, %.4f", s.Latitude, s.Longitude)
+		if s.Near != "" {
+			place = "near " + s.Near
+		}
+		line := fmt.Sprintf("- Mile %.0f, %s, %s: ", s.Miles, place, clock(s.Arrival, s.TimeZone))
+		if s.Forecast != nil {
+			line += hourSummary(*s.Forecast)
+		} else {
+			line += s.Error
+		}
+		if len(s.Alerts) > 0 {
+			line += " [" + strings.Join(s.Alerts, "; ") + "]"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
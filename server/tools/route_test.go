@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+	"weather/server/dtos"
+	"weather/server/geo"
+)
+
+func TestThinSamples(t *testing.T) {
+	var samples []geo.Sample
+	for i := range 21 {
+		samples = append(samples, geo.Sample{Miles: float64(i * 10)})
+	}
+	got := thinSamples(samples, maxRouteStops)
+	if len(got) != maxRouteStops {
+		t.Fatalf("got %d samples, want %d", len(got), maxRouteStops)
+	}
+	if got[0].Miles != 0 || got[len(got)-1].Miles != 200 {
+		t.Errorf("samples run from %v to %v, want 0 to 200", got[0].Miles, got[len(got)-1].Miles)
+	}
+	if short := thinSamples(samples[:5], maxRouteStops); len(short) != 5 {
+		t.Errorf("thinSamples dropped samples from a short route: %d left", len(short))
+	}
+}
+
+func TestRouteAlertsMergesCrossings(t *testing.T) {
+	stops := []dtos.RouteStop{
+		{Miles: 0, TimeZone: "America/Chicago"},
+		{Miles: 50, TimeZone: "America/Chicago"},
+		{Miles: 100, TimeZone: "America/Denver"},
+	}
+	heat := dtos.Feature{ID: "heat", AlertProperties: dtos.AlertProperties{Event: "Heat Advisory"}}
+	storm := dtos.Feature{ID: "storm", AlertProperties: dtos.AlertProperties{Event: "Severe Thunderstorm Warning"}}
+	features := [][]dtos.Feature{{heat}, {heat}, nil}
+	crossings := []routeCrossing{
+		// The storm lies between the second and third stops.
+		{feature: storm, spans: []geo.Span{{From: 62.4, To: 71.6}}},
+		// The heat advisory polygon also contains the first two stops.
+		{feature: heat, spans: []geo.Span{{From: 0, To: 55}}},
+	}
+
+	got := routeAlerts(stops, features, crossings)
+	if len(got) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(got))
+	}
+	if got[0].ID != "heat" || !slices.Equal(got[0].Miles, []float64{0, 50, 55}) {
+		t.Errorf("heat = %s at %v, want miles 0, 50, 55", got[0].ID, got[0].Miles)
+	}
+	if got[1].ID != "storm" || !slices.Equal(got[1].Miles, []float64{62, 72}) || got[1].TimeZone != "America/Chicago" {
+		t.Errorf("storm = %s at %v in %s, want miles 62-72 in America/Chicago", got[1].ID, got[1].Miles, got[1].TimeZone)
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// The SDK only gives streamable HTTP sessions an ID; SSE and stdio sessions
+// all report "". Watches need an owner that tells connections apart, so
+// every session is given a random ID of its own here.
+var (
+	sessionsMu sync.Mutex
+	sessionIDs = map[*mcp.ServerSession]string{}
+	sessionsBy = map[string]*mcp.ServerSession{}
+)
+
+// SessionID returns the ID of a connected session, assigning one the first
+// time the session is seen. It returns "" for a nil session.
+func SessionID(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if id, ok := sessionIDs[session]; ok {
+		return id
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	id := "s-" + hex.EncodeToString(b)
+	sessionIDs[session] = id
+	sessionsBy[id] = session
+	return id
+}
+
+// SessionByID returns the connected session with the given ID, or nil.
+func SessionByID(id string) *mcp.ServerSession {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	return sessionsBy[id]
+}
+
+// ForgetSession drops the ID of a session that has ended, and the watches
+// that lasted only as long as it.
+func ForgetSession(session *mcp.ServerSession) {
+	sessionsMu.Lock()
+	id, ok := sessionIDs[session]
+	delete(sessionsBy, id)
+	delete(sessionIDs, session)
+	sessionsMu.Unlock()
+	if ok {
+		expireSessionWatches(id)
+	}
+}
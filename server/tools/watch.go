@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"weather/server/dtos"
+	"weather/server/watch"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	watchesMu      sync.RWMutex
+	watchStore     *watch.Store
+	watchScheduler *watch.Scheduler
+)
+
+// SetWatches sets the store the watch tools manage and the scheduler that
+// evaluates it. Until it is called the watch tools report that watches are
+// unavailable.
+func SetWatches(store *watch.Store, scheduler *watch.Scheduler) {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+	watchStore, watchScheduler = store, scheduler
+}
+
+// currentWatches returns the store and scheduler set by SetWatches. Sessions end,
+// and expire their watches, concurrently with it.
+func currentWatches() (*watch.Store, *watch.Scheduler) {
+	watchesMu.RLock()
+	defer watchesMu.RUnlock()
+	return watchStore, watchScheduler
+}
+
+func watchesUnavailable() *mcp.CallToolResultFor[any] {
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: "Weather watches are not enabled on this server."}},
+	}
+}
+
+// CreateWatch registers a threshold or alert watch for a location. The
+// calling session is notified when it fires, as is the webhook if one is
+// given.
+func CreateWatch(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.CreateWatchParams]) (*mcp.CallToolResultFor[any], error) {
+	store, scheduler := currentWatches()
+	if store == nil {
+		return watchesUnavailable(), nil
+	}
+	args := params.Arguments
+	caller, res := watchCaller(session, args.OwnerToken)
+	if res != nil {
+		return res, nil
+	}
+	lat, lon, res := resolveLocation(args.Latitude, args.Longitude, args.Place)
+	if res != nil {
+		return res, nil
+	}
+
+	w := watch.Watch{
+		Name:        strings.TrimSpace(args.Name),
+		Latitude:    lat,
+		Longitude:   lon,
+		WebhookURL:  args.WebhookURL,
+		AlertEvents: args.AlertEvents,
+		SessionID:   caller.SessionID,
+		Owner:       caller.Owner,
+	}
+	switch {
+	case len(args.AlertEvents) > 0 && args.Element != "":
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Give either alertEvents or element/operator/value, not both."}},
+		}, nil
+	case len(args.AlertEvents) > 0:
+		w.Kind = watch.KindAlert
+	default:
+		if args.Value == nil {
+			return &mcp.CallToolResultFor[any]{
+				Content: []mcp.Content{&mcp.TextContent{Text: "A threshold watch needs element, operator and value (e.g. gust > 40); an alert watch needs alertEvents."}},
+			}, nil
+		}
+		w.Kind = watch.KindThreshold
+		w.Element = strings.ToLower(strings.TrimSpace(args.Element))
+		w.Operator = strings.TrimSpace(args.Operator)
+		w.Value = *args.Value
+		w.WithinHours = args.WithinHours
+	}
+
+	w, err := store.Add(w)
+	if err != nil {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to create watch: " + err.Error()}},
+		}, nil
+	}
+	if scheduler != nil {
+		scheduler.Wake()
+	}
+
+	text := fmt.Sprintf("Created watch %s: %s at %.4f, %.4f.", w.ID, w.Condition(), w.Latitude, w.Longitude)
+	if w.WebhookURL != "" {
+		text += "\nFirings are POSTed to " + w.WebhookURL + " with an Idempotency-Key header."
+	}
+	text += "\nThis session receives firings as log notifications (logger \"watch\") while it is connected."
+	if w.Owner != "" {
+		text += "\nGive the same ownerToken to list or delete it from any session."
+	} else {
+		text += "\nThe watch is deleted when this session ends; create it with an ownerToken to keep it."
+	}
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+		StructuredContent: w,
+	}, nil
+}
+
+// ListWatches lists the calling session's watches with their current state.
+func ListWatches(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.ListWatchesParams]) (*mcp.CallToolResultFor[any], error) {
+	store, _ := currentWatches()
+	if store == nil {
+		return watchesUnavailable(), nil
+	}
+	caller, res := watchCaller(session, params.Arguments.OwnerToken)
+	if res != nil {
+		return res, nil
+	}
+	watches := store.Owned(caller)
+	if len(watches) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No watches."}},
+		}, nil
+	}
+	return &mcp.CallToolResultFor[any]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: formatWatches(watches)}},
+		StructuredContent: map[string]any{"watches": watches},
+	}, nil
+}
+
+// DeleteWatch removes one of the calling session's watches, including any
+// deliveries still pending.
+func DeleteWatch(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[dtos.WatchIDParams]) (*mcp.CallToolResultFor[any], error) {
+	store, _ := currentWatches()
+	if store == nil {
+		return watchesUnavailable(), nil
+	}
+	caller, res := watchCaller(session, params.Arguments.OwnerToken)
+	if res != nil {
+		return res, nil
+	}
+	id := strings.TrimSpace(params.Arguments.ID)
+	err := store.Delete(id, caller)
+	switch {
+	case errors.Is(err, watch.ErrNotFound):
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("No watch with ID %q.", id)}},
+		}, nil
+	case err != nil:
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Unable to delete watch: " + err.Error()}},
+		}, nil
+	}
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: "Deleted watch " + id + "."}},
+	}, nil
+}
+
+// watchCaller identifies the session managing watches and the owner of the
+// token it gave.
+func watchCaller(session *mcp.ServerSession, token string) (watch.Caller, *mcp.CallToolResultFor[any]) {
+	owner, err := watch.OwnerOf(strings.TrimSpace(token))
+	if err != nil {
+		return watch.Caller{}, &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Invalid ownerToken: " + err.Error() + "."}},
+		}
+	}
+	return watch.Caller{SessionID: SessionID(session), Owner: owner}, nil
+}
+
+// expireSessionWatches removes the watches of a session that has ended,
+// except those created with an owner token.
+func expireSessionWatches(sessionID string) {
+	store, _ := currentWatches()
+	if store == nil || sessionID == "" {
+		return
+	}
+	if _, err := store.ExpireSession(sessionID); err != nil {
+		slog.Warn("removing an ended session's watches", "session", sessionID, "error", err)
+	}
+}
+
+func formatWatches(watches []watch.Watch) string {
+	lines := make([]string, 0, len(watches))
+	for _, w := range watches {
+		line := fmt.Sprintf("- %s", w.ID)
+		if w.Name != "" {
+			line += " (" + w.Name + ")"
+		}
+		line += fmt.Sprintf(": %s at %.4f, %.4f", w.Condition(), w.Latitude, w.Longitude)
+		switch {
+		case w.LastChecked.IsZero():
+			line += "; not checked yet"
+		case w.LastError != "":
+			line += "; last check failed: " + w.LastError
+		default:
+			line += "; checked " + w.LastChecked.Format(time.RFC3339)
+		}
+		if w.Triggered {
+			line += "; condition met"
+		}
+		if len(w.Pending) > 0 {
+			line += fmt.Sprintf("; %d webhook deliveries pending", len(w.Pending))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,21 @@
+// Package tz loads IANA timezones. It embeds the timezone database so that
+// names resolve on hosts without one, such as minimal containers.
+package tz
+
+import (
+	"time"
+	_ "time/tzdata"
+)
+
+// Load returns the named IANA timezone, such as "America/Chicago" from a
+// /points lookup, falling back to UTC when it is empty or unknown.
+func Load(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
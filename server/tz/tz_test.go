@@ -0,0 +1,25 @@
+package tz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"America/Chicago", "America/Chicago"},
+		{"Pacific/Pago_Pago", "Pacific/Pago_Pago"},
+		{"", "UTC"},
+		{"Mars/Olympus_Mons", "UTC"},
+	}
+	for _, tt := range tests {
+		if got := Load(tt.name).String(); got != tt.want {
+			t.Errorf("Load(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	// The embedded database knows daylight saving time.
+	summer := time.Date(2026, 7, 1, 12, 0, 0, 0, Load("America/Chicago"))
+	if name, _ := summer.Zone(); name != "CDT" {
+		t.Errorf("Chicago in July is %s, want CDT", name)
+	}
+}
@@ -0,0 +1,38 @@
+package ugc
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"log/slog"
+	"sync"
+)
+
+// countiesCSV lists every county and county equivalent by five-digit FIPS
+// code, from the Census Bureau's county FIPS list, including Connecticut's
+// planning regions alongside its former counties. It only names counties:
+// centroids come from the gazetteer or the zone-county table.
+//
+//go:embed data/counties.csv
+var countiesCSV []byte
+
+// censusCounties returns the embedded county list by FIPS code.
+var censusCounties = sync.OnceValue(func() map[string]place {
+	records, err := csv.NewReader(bytes.NewReader(countiesCSV)).ReadAll()
+	if err != nil {
+		slog.Error("failed to load county list", "error", err)
+		return nil
+	}
+	counties := make(map[string]place, len(records))
+	for _, r := range records[1:] {
+		counties[r[2]] = place{name: r[0], state: r[1]}
+	}
+	return counties
+})
+
+// censusCounty returns the name and state of the county with the given
+// five-digit FIPS code.
+func censusCounty(fips string) (place, bool) {
+	p, ok := censusCounties()[fips]
+	return p, ok
+}
@@ -0,0 +1,84 @@
+package ugc
+
+// stateFIPS maps two-digit state FIPS codes, as used in SAME codes, to USPS
+// state codes.
+var stateFIPS = map[string]string{
+	"01": "AL", "02": "AK", "04": "AZ", "05": "AR", "06": "CA", "08": "CO",
+	"09": "CT", "10": "DE", "11": "DC", "12": "FL", "13": "GA", "15": "HI",
+	"16": "ID", "17": "IL", "18": "IN", "19": "IA", "20": "KS", "21": "KY",
+	"22": "LA", "23": "ME", "24": "MD", "25": "MA", "26": "MI", "27": "MN",
+	"28": "MS", "29": "MO", "30": "MT", "31": "NE", "32": "NV", "33": "NH",
+	"34": "NJ", "35": "NM", "36": "NY", "37": "NC", "38": "ND", "39": "OH",
+	"40": "OK", "41": "OR", "42": "PA", "44": "RI", "45": "SC", "46": "SD",
+	"47": "TN", "48": "TX", "49": "UT", "50": "VT", "51": "VA", "53": "WA",
+	"54": "WV", "55": "WI", "56": "WY", "60": "AS", "66": "GU", "69": "MP",
+	"72": "PR", "78": "VI",
+}
+
+// stateNames maps USPS state codes to state names.
+var stateNames = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas",
+	"CA": "California", "CO": "Colorado", "CT": "Connecticut", "DE": "Delaware",
+	"DC": "District of Columbia", "FL": "Florida", "GA": "Georgia", "HI": "Hawaii",
+	"ID": "Idaho", "IL": "Illinois", "IN": "Indiana", "IA": "Iowa",
+	"KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine",
+	"MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+	"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska",
+	"NV": "Nevada", "NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico",
+	"NY": "New York", "NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio",
+	"OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island",
+	"SC": "South Carolina", "SD": "South Dakota", "TN": "Tennessee", "TX": "Texas",
+	"UT": "Utah", "VT": "Vermont", "VA": "Virginia", "WA": "Washington",
+	"WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+	"AS": "American Samoa", "GU": "Guam", "MP": "Northern Mariana Islands",
+	"PR": "Puerto Rico", "VI": "U.S. Virgin Islands",
+}
+
+// marineAreas maps the two-letter marine area codes used in marine UGC zones
+// (e.g. the "PZ" in PZZ530) to the body of water they cover.
+var marineAreas = map[string]string{
+	"AM": "Western Atlantic and Caribbean",
+	"AN": "Western North Atlantic",
+	"GM": "Gulf of Mexico",
+	"LC": "Lake St. Clair",
+	"LE": "Lake Erie",
+	"LH": "Lake Huron",
+	"LM": "Lake Michigan",
+	"LO": "Lake Ontario",
+	"LS": "Lake Superior",
+	"PH": "Central Pacific (Hawaii)",
+	"PK": "North Pacific (Alaska)",
+	"PM": "Western Pacific (Marianas)",
+	"PS": "South Pacific (American Samoa)",
+	"PZ": "Eastern North Pacific",
+	"SL": "St. Lawrence River",
+}
+
+// marineRegions maps the marine region codes accepted by the NWS alerts API
+// to the waters they group.
+var marineRegions = map[string]string{
+	"AL": "Alaska waters",
+	"AT": "Atlantic Ocean",
+	"GL": "Great Lakes",
+	"GM": "Gulf of Mexico",
+	"PA": "Eastern Pacific Ocean",
+	"PI": "Central and Western Pacific",
+}
+
+// StateName returns the name of a US state or territory by USPS code.
+func StateName(code string) (string, bool) {
+	name, ok := stateNames[code]
+	return name, ok
+}
+
+// MarineAreaName returns the body of water covered by a marine area code.
+func MarineAreaName(code string) (string, bool) {
+	name, ok := marineAreas[code]
+	return name, ok
+}
+
+// MarineRegionName returns the waters covered by a marine region code.
+func MarineRegionName(code string) (string, bool) {
+	name, ok := marineRegions[code]
+	return name, ok
+}
@@ -0,0 +1,208 @@
+// Package ugc decodes the Universal Geographic Codes (UGC) and SAME FIPS codes
+// found in NWS alert geocode blocks into readable places. Every county is
+// named from the embedded Census county list. Zone names and centroids come
+// from an embedded sample of the NWS zone-county correlation file;
+// LoadZones swaps in the full file for complete zone coverage.
+package ugc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"weather/server/gazetteer"
+)
+
+// Kind identifies what a decoded code refers to.
+type Kind string
+
+const (
+	KindCounty Kind = "county"
+	KindZone   Kind = "zone"
+	KindMarine Kind = "marine"
+	KindState  Kind = "state"
+)
+
+// Area is a decoded UGC or SAME code.
+type Area struct {
+	Code      string   `json:"code"`
+	Kind      Kind     `json:"kind"`
+	Name      string   `json:"name"`
+	State     string   `json:"state,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// Label returns a readable description of the area, e.g. "Travis County, TX".
+func (a Area) Label() string {
+	if a.State == "" || a.Kind == KindState {
+		return a.Name
+	}
+	return a.Name + ", " + a.State
+}
+
+var (
+	ugcPattern   = regexp.MustCompile(`^([A-Z]{2})([CZ])(\d{3})$`)
+	rangePattern = regexp.MustCompile(`^([A-Z]{2}[CZ])?(\d{3})(?:>(\d{3}))?$`)
+	samePattern  = regexp.MustCompile(`^(\d)(\d{2})(\d{3})$`)
+	// expiration is the DDHHMM purge time that ends a raw UGC line.
+	expiration = regexp.MustCompile(`^\d{6}$`)
+)
+
+// Decode decodes a single UGC code such as "TXZ211", "TXC453" or "PZZ530".
+func Decode(code string) (Area, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	m := ugcPattern.FindStringSubmatch(code)
+	if m == nil {
+		return Area{}, false
+	}
+	prefix, typ, num := m[1], m[2], m[3]
+
+	if area, ok := marineAreas[prefix]; ok && typ == "Z" {
+		return Area{Code: code, Kind: KindMarine, Name: fmt.Sprintf("%s marine zone %s", area, num)}, true
+	}
+
+	if _, ok := stateNames[prefix]; !ok {
+		return Area{}, false
+	}
+
+	if typ == "C" {
+		// A UGC county number is the county's FIPS code within its state.
+		if a, ok := county(code, stateCode(prefix)+num); ok {
+			return a, true
+		}
+		return Area{Code: code, Kind: KindCounty, Name: "County " + num, State: prefix}, true
+	}
+	if z, ok := zoneByCode(code); ok {
+		return placeArea(code, KindZone, z), true
+	}
+	return Area{Code: code, Kind: KindZone, Name: "Forecast zone " + num, State: prefix}, true
+}
+
+// DecodeSAME decodes a six-digit SAME code (PSSCCC). A county code of 000
+// refers to the entire state.
+func DecodeSAME(code string) (Area, bool) {
+	code = strings.TrimSpace(code)
+	m := samePattern.FindStringSubmatch(code)
+	if m == nil {
+		return Area{}, false
+	}
+	part, stateCode, countyFIPS := m[1], m[2], m[3]
+
+	state, ok := stateFIPS[stateCode]
+	if !ok {
+		return Area{}, false
+	}
+	if countyFIPS == "000" {
+		return Area{Code: code, Kind: KindState, Name: stateNames[state], State: state}, true
+	}
+
+	area, ok := county(code, stateCode+countyFIPS)
+	if !ok {
+		area = Area{Code: code, Kind: KindCounty, Name: "County " + countyFIPS, State: state}
+	}
+	if part != "0" {
+		// Non-zero subdivisions refer to a part of the county (e.g. 1 = northwest).
+		area.Name = "Part of " + area.Name
+	}
+	return area, true
+}
+
+// Expand expands a raw UGC string into individual codes. It accepts single
+// codes ("TXZ211"), ranges ("TXZ211>215") and full UGC lines such as
+// "TXZ211>213-217-TXC453-051800-", where a bare number inherits the most
+// recent prefix and the trailing purge time is ignored.
+func Expand(s string) []string {
+	var (
+		out    []string
+		prefix string
+	)
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(s)), "-") {
+		part = strings.TrimSpace(part)
+		if part == "" || expiration.MatchString(part) {
+			continue
+		}
+		m := rangePattern.FindStringSubmatch(part)
+		if m == nil {
+			continue
+		}
+		if m[1] != "" {
+			prefix = m[1]
+		}
+		if prefix == "" {
+			continue
+		}
+
+		from, _ := strconv.Atoi(m[2])
+		to := from
+		if m[3] != "" {
+			to, _ = strconv.Atoi(m[3])
+		}
+		for n := from; n <= to; n++ {
+			out = append(out, fmt.Sprintf("%s%03d", prefix, n))
+		}
+	}
+	return out
+}
+
+// DecodeAll decodes UGC and SAME codes, expanding UGC ranges and dropping
+// duplicate places (a county usually appears in both lists).
+func DecodeAll(ugcCodes, sameCodes []string) []Area {
+	var (
+		out  []Area
+		seen = make(map[string]bool)
+	)
+	add := func(a Area) {
+		key := string(a.Kind) + "|" + a.Label()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, a)
+	}
+
+	for _, raw := range ugcCodes {
+		for _, code := range Expand(raw) {
+			if a, ok := Decode(code); ok {
+				add(a)
+			}
+		}
+	}
+	for _, code := range sameCodes {
+		if a, ok := DecodeSAME(code); ok {
+			add(a)
+		}
+	}
+	return out
+}
+
+// county decodes a county by its five-digit FIPS code, preferring the
+// gazetteer, then the zone-county table, and finally the Census county
+// list, which has a name but no centroid.
+func county(code, fips string) (Area, bool) {
+	if c, ok := gazetteer.CountyByFIPS(fips); ok {
+		return placeArea(code, KindCounty, place{name: c.Name, state: c.State, latitude: c.Latitude, longitude: c.Longitude}), true
+	}
+	if c, ok := countyByFIPS(fips); ok {
+		return placeArea(code, KindCounty, c), true
+	}
+	if c, ok := censusCounty(fips); ok {
+		return Area{Code: code, Kind: KindCounty, Name: c.name, State: c.state}, true
+	}
+	return Area{}, false
+}
+
+// stateCode returns the two-digit FIPS code of a USPS state code.
+func stateCode(state string) string {
+	for fips, st := range stateFIPS {
+		if st == state {
+			return fips
+		}
+	}
+	return ""
+}
+
+func placeArea(code string, kind Kind, p place) Area {
+	lat, lon := p.latitude, p.longitude
+	return Area{Code: code, Kind: kind, Name: p.name, State: p.state, Latitude: &lat, Longitude: &lon}
+}
@@ -0,0 +1,119 @@
+package ugc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		code      string
+		kind      Kind
+		label     string
+		hasCenter bool
+	}{
+		{"TXZ192", KindZone, "Travis, TX", true},
+		{"NYZ072", KindZone, "New York (Manhattan), NY", true},
+		{"TXC453", KindCounty, "Travis County, TX", true},
+		// Bronx County is in the zone table but not the gazetteer sample.
+		{"NYC005", KindCounty, "Bronx County, NY", true},
+		// Loving County is only in the Census county list.
+		{"TXC301", KindCounty, "Loving County, TX", false},
+		{"TXZ999", KindZone, "Forecast zone 999, TX", false},
+		{"TXC999", KindCounty, "County 999, TX", false},
+		{"PZZ530", KindMarine, "Eastern North Pacific marine zone 530", false},
+	}
+	for _, tt := range tests {
+		a, ok := Decode(tt.code)
+		if !ok {
+			t.Errorf("Decode(%s) failed", tt.code)
+			continue
+		}
+		if a.Kind != tt.kind || a.Label() != tt.label {
+			t.Errorf("Decode(%s) = %s %q, want %s %q", tt.code, a.Kind, a.Label(), tt.kind, tt.label)
+		}
+		if (a.Latitude != nil) != tt.hasCenter {
+			t.Errorf("Decode(%s) centroid = %v, want %v", tt.code, a.Latitude != nil, tt.hasCenter)
+		}
+	}
+}
+
+func TestDecodeSAME(t *testing.T) {
+	tests := []struct {
+		code  string
+		label string
+	}{
+		{"048453", "Travis County, TX"},
+		{"036005", "Bronx County, NY"},
+		{"148453", "Part of Travis County, TX"},
+		{"048000", "Texas"},
+		{"048999", "County 999, TX"},
+		{"051760", "Richmond city, VA"},
+		{"022051", "Jefferson Parish, LA"},
+		{"072097", "Mayagüez Municipio, PR"},
+		{"009110", "Capitol Planning Region, CT"},
+	}
+	for _, tt := range tests {
+		a, ok := DecodeSAME(tt.code)
+		if !ok || a.Label() != tt.label {
+			t.Errorf("DecodeSAME(%s) = %q, %v; want %q", tt.code, a.Label(), ok, tt.label)
+		}
+	}
+}
+
+func TestReadZones(t *testing.T) {
+	// A county split across two zones, and a parish.
+	data := `TX|211|EWX|Northern Travis|TXZ211|Travis|48453|C|nc|30.5|-97.8
+TX|212|EWX|Southern Travis|TXZ212|Travis|48453|C|sc|30.1|-97.8
+LA|037|LIX|Orleans|LAZ037|Orleans|22071|C||30.0|-90.0
+`
+	tb, err := readZones(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z := tb.zones["TXZ211"]; z.name != "Northern Travis" || z.state != "TX" || z.latitude != 30.5 {
+		t.Errorf("TXZ211 = %+v", z)
+	}
+	if c := tb.counties["48453"]; c.name != "Travis County" || c.latitude < 30.29 || c.latitude > 30.31 {
+		t.Errorf("48453 = %+v, want Travis County centred between its zones", c)
+	}
+	if c := tb.counties["22071"]; c.name != "Orleans Parish" {
+		t.Errorf("22071 = %+v, want Orleans Parish", c)
+	}
+
+	if _, err := readZones(strings.NewReader("TX|211|EWX\n")); err == nil {
+		t.Error("readZones accepted a short line")
+	}
+}
+
+func TestLoadZonesWhileDecoding(t *testing.T) {
+	old := zoneData()
+	t.Cleanup(func() { setTables(old) })
+	path := filepath.Join(t.TempDir(), "zones.dbx")
+	data := string(zonesSample) + "TX|999|EWX|Test Zone|TXZ999|Travis|48453|C||30.3|-97.7\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run with -race: decodes may overlap the swap.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				Decode("TXZ192")
+			}
+		}()
+	}
+	if err := LoadZones(path); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if a, ok := Decode("TXZ999"); !ok || a.Name != "Test Zone" {
+		t.Errorf("TXZ999 = %+v, %v after LoadZones", a, ok)
+	}
+}
@@ -0,0 +1,163 @@
+package ugc
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// zonesSample is a sample of the NWS zone-county correlation file, covering a
+// few metro zones; counties outside it are still named from the Census list
+// in counties.go. LoadZones swaps in the full file, published at
+// https://www.weather.gov/gis/ZoneCounty under names such as bp05mr24.dbx.
+//
+//go:embed data/zones.dbx
+var zonesSample []byte
+
+// place is a named zone or county with its centroid.
+type place struct {
+	name      string
+	state     string
+	latitude  float64
+	longitude float64
+}
+
+type zoneTables struct {
+	zones    map[string]place // by UGC code, e.g. "TXZ192"
+	counties map[string]place // by five-digit FIPS code
+}
+
+var (
+	zonesOnce sync.Once
+	tablesMu  sync.RWMutex
+	tables    zoneTables
+)
+
+// zoneData returns the zone and county tables, loading the embedded sample
+// on first use.
+func zoneData() zoneTables {
+	zonesOnce.Do(func() {
+		t, err := readZones(bytes.NewReader(zonesSample))
+		if err != nil {
+			slog.Error("failed to load zone data", "error", err)
+		}
+		setTables(t)
+	})
+	tablesMu.RLock()
+	defer tablesMu.RUnlock()
+	return tables
+}
+
+func setTables(t zoneTables) {
+	tablesMu.Lock()
+	defer tablesMu.Unlock()
+	tables = t
+}
+
+// LoadZones replaces the embedded sample with the NWS zone-county
+// correlation file at path. Decodes already running finish with the tables
+// they started with.
+func LoadZones(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	t, err := readZones(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	// Keep a later first decode from loading the sample over these tables.
+	zonesOnce.Do(func() {})
+	setTables(t)
+	return nil
+}
+
+// readZones parses the pipe-separated zone-county correlation format:
+// STATE|ZONE|CWA|NAME|STATE_ZONE|COUNTY|FIPS|TIME_ZONE|FE_AREA|LAT|LON, one
+// line per zone and county it overlaps. A county's centroid is the mean of
+// the centroids of its zones.
+func readZones(r io.Reader) (zoneTables, error) {
+	t := zoneTables{zones: make(map[string]place), counties: make(map[string]place)}
+	type sum struct {
+		place
+		n int
+	}
+	counties := make(map[string]*sum)
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		f := strings.Split(text, "|")
+		if len(f) < 11 {
+			return t, fmt.Errorf("line %d: want 11 fields, got %d", line, len(f))
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(f[9]), 64)
+		if err != nil {
+			return t, fmt.Errorf("line %d: latitude: %w", line, err)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(f[10]), 64)
+		if err != nil {
+			return t, fmt.Errorf("line %d: longitude: %w", line, err)
+		}
+		state := strings.TrimSpace(f[0])
+		code := strings.ToUpper(strings.TrimSpace(f[4]))
+		t.zones[code] = place{name: strings.TrimSpace(f[3]), state: state, latitude: lat, longitude: lon}
+
+		fips := strings.TrimSpace(f[6])
+		c, ok := counties[fips]
+		if !ok {
+			c = &sum{place: place{name: countyName(state, strings.TrimSpace(f[5])), state: state}}
+			counties[fips] = c
+		}
+		c.latitude += lat
+		c.longitude += lon
+		c.n++
+	}
+	if err := sc.Err(); err != nil {
+		return t, err
+	}
+	for fips, c := range counties {
+		c.latitude /= float64(c.n)
+		c.longitude /= float64(c.n)
+		t.counties[fips] = c.place
+	}
+	return t, nil
+}
+
+// countyName turns the bare county name used in the correlation file into
+// the county's full name, e.g. "Travis" into "Travis County".
+func countyName(state, name string) string {
+	switch {
+	case state == "DC" || state == "AK" || strings.HasSuffix(name, " City"):
+		return name
+	case state == "LA":
+		return name + " Parish"
+	default:
+		return name + " County"
+	}
+}
+
+// zoneByCode returns the public forecast zone with the given UGC code.
+func zoneByCode(code string) (place, bool) {
+	p, ok := zoneData().zones[code]
+	return p, ok
+}
+
+// countyByFIPS returns the county with the given five-digit FIPS code from
+// the zone-county table.
+func countyByFIPS(fips string) (place, bool) {
+	p, ok := zoneData().counties[fips]
+	return p, ok
+}
@@ -0,0 +1,89 @@
+package vtec
+
+// actions describes each VTEC action code.
+var actions = map[string]string{
+	"NEW": "issued",
+	"CON": "continued",
+	"EXT": "extended in time",
+	"EXA": "extended in area",
+	"EXB": "extended in time and area",
+	"UPG": "upgraded",
+	"CAN": "cancelled",
+	"EXP": "expired",
+	"COR": "corrected",
+	"ROU": "routine",
+}
+
+// significances maps VTEC significance codes to their names.
+var significances = map[string]string{
+	"W": "Warning",
+	"A": "Watch",
+	"Y": "Advisory",
+	"S": "Statement",
+	"F": "Forecast",
+	"O": "Outlook",
+	"N": "Synopsis",
+}
+
+// phenomena maps VTEC phenomenon codes to hazard names.
+var phenomena = map[string]string{
+	"AF": "Ashfall",
+	"AS": "Air Stagnation",
+	"BH": "Beach Hazard",
+	"BW": "Brisk Wind",
+	"BZ": "Blizzard",
+	"CF": "Coastal Flood",
+	"DF": "Debris Flow",
+	"DS": "Dust Storm",
+	"DU": "Blowing Dust",
+	"EC": "Extreme Cold",
+	"EH": "Excessive Heat",
+	"EW": "Extreme Wind",
+	"FA": "Areal Flood",
+	"FF": "Flash Flood",
+	"FG": "Dense Fog",
+	"FL": "Flood",
+	"FR": "Frost",
+	"FW": "Fire Weather",
+	"FZ": "Freeze",
+	"GL": "Gale",
+	"HF": "Hurricane Force Wind",
+	"HT": "Heat",
+	"HU": "Hurricane",
+	"HW": "High Wind",
+	"HY": "Hydrologic",
+	"HZ": "Hard Freeze",
+	"IS": "Ice Storm",
+	"LE": "Lake Effect Snow",
+	"LO": "Low Water",
+	"LS": "Lakeshore Flood",
+	"LW": "Lake Wind",
+	"MA": "Marine",
+	"MF": "Dense Fog (Marine)",
+	"MH": "Ashfall (Marine)",
+	"MS": "Dense Smoke (Marine)",
+	"RB": "Small Craft for Rough Bar",
+	"RP": "Rip Current",
+	"SC": "Small Craft",
+	"SE": "Hazardous Seas",
+	"SI": "Small Craft for Winds",
+	"SM": "Dense Smoke",
+	"SQ": "Snow Squall",
+	"SR": "Storm",
+	"SS": "Storm Surge",
+	"SU": "High Surf",
+	"SV": "Severe Thunderstorm",
+	"SW": "Small Craft for Hazardous Seas",
+	"TO": "Tornado",
+	"TR": "Tropical Storm",
+	"TS": "Tsunami",
+	"TY": "Typhoon",
+	"UP": "Heavy Freezing Spray",
+	"WC": "Wind Chill",
+	"WI": "Wind",
+	"WS": "Winter Storm",
+	"WW": "Winter Weather",
+	"XH": "Extreme Heat",
+	"ZF": "Freezing Fog",
+	"ZR": "Freezing Rain",
+}
@@ -0,0 +1,170 @@
+// Package vtec parses P-VTEC (Primary Valid Time Event Code) strings carried
+// in NWS alert parameters, e.g. "/O.NEW.KEWX.TO.W.0012.250805T1800Z-250805T1900Z/".
+package vtec
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayout is the VTEC timestamp format (yymmddThhnnZ).
+const timeLayout = "060102T1504Z"
+
+// unspecified is used in place of a time when the event has no defined
+// beginning or end.
+const unspecified = "000000T0000Z"
+
+var pattern = regexp.MustCompile(`/([OTEX])\.([A-Z]{3})\.([A-Z]{4})\.([A-Z]{2})\.([A-Z])\.(\d{4})\.(\d{6}T\d{4}Z)-(\d{6}T\d{4}Z)/`)
+
+// VTEC is a parsed P-VTEC string.
+type VTEC struct {
+	Raw          string     `json:"raw"`
+	Class        string     `json:"class"`
+	Action       string     `json:"action"`
+	Office       string     `json:"office"`
+	Phenomenon   string     `json:"phenomenon"`
+	Significance string     `json:"significance"`
+	ETN          int        `json:"etn"`
+	Begin        *time.Time `json:"begin,omitempty"`
+	End          *time.Time `json:"end,omitempty"`
+}
+
+// Parse parses a single P-VTEC string.
+func Parse(s string) (VTEC, error) {
+	m := pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return VTEC{}, fmt.Errorf("vtec: invalid P-VTEC string %q", s)
+	}
+
+	etn, _ := strconv.Atoi(m[6])
+	v := VTEC{
+		Raw:          m[0],
+		Class:        m[1],
+		Action:       m[2],
+		Office:       m[3],
+		Phenomenon:   m[4],
+		Significance: m[5],
+		ETN:          etn,
+	}
+
+	var err error
+	if v.Begin, err = parseTime(m[7]); err != nil {
+		return VTEC{}, err
+	}
+	if v.End, err = parseTime(m[8]); err != nil {
+		return VTEC{}, err
+	}
+	return v, nil
+}
+
+// ParseAll parses every valid P-VTEC string in ss, skipping invalid ones.
+func ParseAll(ss []string) []VTEC {
+	var out []VTEC
+	for _, s := range ss {
+		if v, err := Parse(s); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == unspecified {
+		return nil, nil
+	}
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return nil, fmt.Errorf("vtec: invalid time %q: %w", s, err)
+	}
+	return &t, nil
+}
+
+// eventGap is how long after the previous message about an event a
+// message without a NEW action may still continue it. Past that, a reused
+// ETN is taken to be a different event.
+const eventGap = 60 * 24 * time.Hour
+
+// Message is a VTEC string with the time the message carrying it was sent.
+// Onset, when known, is when the hazard began; it dates an update whose
+// earlier messages are no longer available.
+type Message struct {
+	VTEC
+	Sent  time.Time
+	Onset time.Time
+}
+
+// EventKey identifies the hazard event a VTEC refers to when it is the only
+// message known about it. ETNs restart every January, so the key includes
+// the year the message was sent; use EventKeys when other messages about
+// the event may have been sent the year before.
+func (v VTEC) EventKey(sent time.Time) string {
+	return EventKeys([]Message{{VTEC: v, Sent: sent}})[0]
+}
+
+// EventKeys returns the event key of each message, in order. All updates of
+// the same event (NEW, CON, EXT, CAN, ...) share a key, which includes the
+// year the event's earliest message was sent: a CON sent on January 1 for
+// an event issued on December 31 keeps the year it was issued in. An update
+// with no earlier message takes the year of its onset instead, if that is
+// earlier and within the event gap.
+func EventKeys(msgs []Message) []string {
+	order := make([]int, len(msgs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return msgs[a].Sent.Compare(msgs[b].Sent) })
+
+	type last struct {
+		year int
+		sent time.Time
+	}
+	var (
+		keys = make([]string, len(msgs))
+		seen = make(map[string]last)
+	)
+	for _, i := range order {
+		m := msgs[i]
+		base := fmt.Sprintf("%s.%s.%s.%04d", m.Office, m.Phenomenon, m.Significance, m.ETN)
+		year := m.Sent.UTC().Year()
+		if m.Sent.IsZero() && m.Begin != nil {
+			year = m.Begin.Year()
+		}
+		if prev, ok := seen[base]; ok && m.Action != "NEW" && m.Sent.Sub(prev.sent) <= eventGap {
+			year = prev.year
+		} else if !ok && m.Action != "NEW" && !m.Onset.IsZero() && m.Sent.Sub(m.Onset) <= eventGap {
+			year = min(year, m.Onset.UTC().Year())
+		}
+		seen[base] = last{year: year, sent: m.Sent}
+		keys[i] = fmt.Sprintf("%d.%s", year, base)
+	}
+	return keys
+}
+
+// Name returns the hazard name, e.g. "Tornado Warning".
+func (v VTEC) Name() string {
+	name, ok := phenomena[v.Phenomenon]
+	if !ok {
+		name = v.Phenomenon
+	}
+	if sig, ok := significances[v.Significance]; ok {
+		name += " " + sig
+	}
+	return name
+}
+
+// ActionDescription describes the action in plain words, e.g. "extended in time".
+func (v VTEC) ActionDescription() string {
+	if d, ok := actions[v.Action]; ok {
+		return d
+	}
+	return v.Action
+}
+
+// IsTerminal reports whether the action ends the event.
+func (v VTEC) IsTerminal() bool {
+	return v.Action == "CAN" || v.Action == "EXP" || v.Action == "UPG"
+}
@@ -0,0 +1,196 @@
+package vtec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in         string
+		action     string
+		etn        int
+		begin, end string // RFC 3339, or "" when unspecified
+		wantErr    bool
+	}{
+		{"/O.NEW.KEWX.TO.W.0012.250805T1800Z-250805T1900Z/", "NEW", 12, "2025-08-05T18:00:00Z", "2025-08-05T19:00:00Z", false},
+		// Once an event is in effect its begin time is left unspecified.
+		{"/O.CON.KEWX.SV.W.0012.000000T0000Z-250805T2300Z/", "CON", 12, "", "2025-08-05T23:00:00Z", false},
+		// Surrounding text and whitespace are ignored.
+		{"  text /O.EXP.KLOX.EH.W.0004.000000T0000Z-250806T0300Z/ more ", "EXP", 4, "", "2025-08-06T03:00:00Z", false},
+		{"/O.UFN.KOUN.FF.W.0001.000000T0000Z-000000T0000Z/", "UFN", 1, "", "", false},
+		{"/O.NEW.KEWX.TO.W.12.250805T1800Z-250805T1900Z/", "", 0, "", "", true},
+		{"/O.NEW.KEWX.TO.W.0012.251305T1800Z-250805T1900Z/", "", 0, "", "", true},
+		{"", "", 0, "", "", true},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if v.Action != tt.action || v.ETN != tt.etn {
+			t.Errorf("Parse(%q) = %s #%d, want %s #%d", tt.in, v.Action, v.ETN, tt.action, tt.etn)
+		}
+		if got := formatTime(v.Begin); got != tt.begin {
+			t.Errorf("Parse(%q) begin = %q, want %q", tt.in, got, tt.begin)
+		}
+		if got := formatTime(v.End); got != tt.end {
+			t.Errorf("Parse(%q) end = %q, want %q", tt.in, got, tt.end)
+		}
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func TestParseAll(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want []string // actions
+	}{
+		{nil, nil},
+		{[]string{"/O.NEW.KEWX.TO.W.0012.250805T1800Z-250805T1900Z/"}, []string{"NEW"}},
+		// Invalid strings are skipped; the valid ones keep their order.
+		{[]string{
+			"/O.CAN.KEWX.SV.W.0011.000000T0000Z-250805T2300Z/",
+			"not vtec",
+			"/O.UPG.KEWX.TO.A.0400.000000T0000Z-250805T2300Z/",
+		}, []string{"CAN", "UPG"}},
+	}
+	for _, tt := range tests {
+		got := ParseAll(tt.in)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseAll(%q) = %d strings, want %d", tt.in, len(got), len(tt.want))
+			continue
+		}
+		for i, v := range got {
+			if v.Action != tt.want[i] {
+				t.Errorf("ParseAll(%q)[%d] action = %s, want %s", tt.in, i, v.Action, tt.want[i])
+			}
+		}
+	}
+}
+
+func message(t *testing.T, sent, s string) Message {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, err := time.Parse(time.RFC3339, sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Message{VTEC: v, Sent: at}
+}
+
+func TestEventKey(t *testing.T) {
+	tests := []struct {
+		sent, vtec, want string
+	}{
+		{"2025-08-05T13:00:00-05:00", "/O.NEW.KEWX.TO.W.0012.250805T1800Z-250805T1900Z/", "2025.KEWX.TO.W.0012"},
+		{"2025-08-05T14:00:00-05:00", "/O.CON.KEWX.TO.W.0012.000000T0000Z-250805T1900Z/", "2025.KEWX.TO.W.0012"},
+		// Issued on December 31 for a hazard beginning on January 1: the
+		// ETN belongs to the year it was issued in.
+		{"2025-12-31T20:00:00Z", "/O.NEW.KBOU.WS.A.0090.260101T0600Z-260102T0000Z/", "2025.KBOU.WS.A.0090"},
+		// The year is taken in UTC.
+		{"2025-12-31T19:00:00-05:00", "/O.NEW.KBOX.WW.Y.0001.260101T0000Z-260101T1200Z/", "2026.KBOX.WW.Y.0001"},
+	}
+	for _, tt := range tests {
+		m := message(t, tt.sent, tt.vtec)
+		if got := m.EventKey(m.Sent); got != tt.want {
+			t.Errorf("EventKey(%s) for %s = %q, want %q", tt.sent, tt.vtec, got, tt.want)
+		}
+	}
+}
+
+func TestEventKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		msgs  [][2]string // sent, VTEC
+		onset string      // of every message, or ""
+		want  []string
+	}{
+		{
+			name: "continued across New Year",
+			msgs: [][2]string{
+				{"2026-01-01T02:00:00Z", "/O.CON.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/"},
+				{"2025-12-31T20:00:00Z", "/O.NEW.KBOU.WS.W.0090.251231T2200Z-260101T1800Z/"},
+			},
+			want: []string{"2025.KBOU.WS.W.0090", "2025.KBOU.WS.W.0090"},
+		},
+		{
+			name: "continued across New Year without the NEW message",
+			msgs: [][2]string{
+				{"2025-12-31T23:00:00Z", "/O.CON.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/"},
+				{"2026-01-01T05:00:00Z", "/O.EXT.KBOU.WS.W.0090.000000T0000Z-260102T0000Z/"},
+				{"2026-01-01T23:00:00Z", "/O.CAN.KBOU.WS.W.0090.000000T0000Z-260102T0000Z/"},
+			},
+			want: []string{"2025.KBOU.WS.W.0090", "2025.KBOU.WS.W.0090", "2025.KBOU.WS.W.0090"},
+		},
+		{
+			name: "continued across New Year with only the onset known",
+			msgs: [][2]string{
+				{"2026-01-01T02:00:00Z", "/O.CON.KBOU.WS.W.0090.000000T0000Z-260101T1800Z/"},
+			},
+			onset: "2025-12-31T22:00:00Z",
+			want:  []string{"2025.KBOU.WS.W.0090"},
+		},
+		{
+			name: "onset of a new event ignored",
+			msgs: [][2]string{
+				{"2026-01-01T02:00:00Z", "/O.NEW.KBOU.WS.W.0001.260101T0200Z-260101T1800Z/"},
+			},
+			onset: "2025-12-31T22:00:00Z",
+			want:  []string{"2026.KBOU.WS.W.0001"},
+		},
+		{
+			name: "ETN reused the next year",
+			msgs: [][2]string{
+				{"2025-01-03T12:00:00Z", "/O.NEW.KEWX.FG.Y.0001.250103T1200Z-250103T1800Z/"},
+				{"2026-01-02T12:00:00Z", "/O.NEW.KEWX.FG.Y.0001.260102T1200Z-260102T1800Z/"},
+				{"2026-01-02T15:00:00Z", "/O.CON.KEWX.FG.Y.0001.000000T0000Z-260102T1800Z/"},
+			},
+			want: []string{"2025.KEWX.FG.Y.0001", "2026.KEWX.FG.Y.0001", "2026.KEWX.FG.Y.0001"},
+		},
+		{
+			name: "long after the last message",
+			msgs: [][2]string{
+				{"2025-10-01T12:00:00Z", "/O.CON.KEWX.FL.W.0001.000000T0000Z-251002T1200Z/"},
+				{"2026-03-01T12:00:00Z", "/O.CON.KEWX.FL.W.0001.000000T0000Z-260302T1200Z/"},
+			},
+			want: []string{"2025.KEWX.FL.W.0001", "2026.KEWX.FL.W.0001"},
+		},
+		{
+			name: "different offices",
+			msgs: [][2]string{
+				{"2025-12-31T20:00:00Z", "/O.NEW.KBOU.WS.W.0090.251231T2200Z-260101T1800Z/"},
+				{"2026-01-01T02:00:00Z", "/O.CON.KGJT.WS.W.0090.000000T0000Z-260101T1800Z/"},
+			},
+			want: []string{"2025.KBOU.WS.W.0090", "2026.KGJT.WS.W.0090"},
+		},
+	}
+	for _, tt := range tests {
+		msgs := make([]Message, len(tt.msgs))
+		for i, m := range tt.msgs {
+			msgs[i] = message(t, m[0], m[1])
+			if tt.onset != "" {
+				msgs[i].Onset, _ = time.Parse(time.RFC3339, tt.onset)
+			}
+		}
+		got := EventKeys(msgs)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: key %d = %q, want %q", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// errBlockedAddress is returned for webhook hosts on private, loopback or
+// link-local networks that are not allowlisted. Webhook URLs come from MCP
+// clients, so without it a watch could be aimed at the server's own network.
+var errBlockedAddress = errors.New("watch: webhook address is private, loopback or link-local")
+
+var (
+	allowMu sync.RWMutex
+	allowed []netip.Prefix
+)
+
+// ParseAllowlist parses addresses and CIDR ranges such as "127.0.0.1" or
+// "10.0.0.0/8" for SetWebhookAllowlist. Blank entries are skipped.
+func ParseAllowlist(entries []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("watch: %q is not an IP address or CIDR range", entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// SetWebhookAllowlist sets the private, loopback and link-local ranges
+// webhooks may still be sent to, e.g. a receiver on the same host. Public
+// addresses are always allowed.
+func SetWebhookAllowlist(prefixes []netip.Prefix) {
+	allowMu.Lock()
+	defer allowMu.Unlock()
+	allowed = prefixes
+}
+
+// checkAddr reports whether webhooks may be sent to addr.
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsInterfaceLocalMulticast() && !addr.IsUnspecified() {
+		return nil
+	}
+	allowMu.RLock()
+	defer allowMu.RUnlock()
+	for _, p := range allowed {
+		if p.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errBlockedAddress, addr)
+}
+
+// checkHost rejects a webhook host that is, or names, a blocked address.
+// Other names are checked when they are resolved, by webhookClient.
+func checkHost(host string) error {
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return checkAddr(addr)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return checkAddr(netip.AddrFrom4([4]byte{127, 0, 0, 1}))
+	}
+	return nil
+}
+
+// webhookClient returns an HTTP client that refuses to connect to blocked
+// addresses. The check runs on the resolved address of every connection,
+// redirects included, so a public name that resolves to a private address
+// is caught too. Proxies are not used, as they would hide the address.
+func webhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return checkAddr(ap.Addr())
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// allowLoopback allowlists loopback addresses until the test ends.
+func allowLoopback(t *testing.T) {
+	t.Helper()
+	allow, err := ParseAllowlist([]string{"127.0.0.0/8", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetWebhookAllowlist(allow)
+	t.Cleanup(func() { SetWebhookAllowlist(nil) })
+}
+
+func TestValidateWebhookHost(t *testing.T) {
+	tests := []struct {
+		url   string
+		allow []string
+		ok    bool
+	}{
+		{"https://hooks.example.com/weather", nil, true},
+		{"https://203.0.113.7/hook", nil, true},
+		{"http://127.0.0.1:8080/hook", nil, false},
+		{"http://localhost:8080/hook", nil, false},
+		{"http://app.localhost/hook", nil, false},
+		{"http://[::1]/hook", nil, false},
+		{"http://[::ffff:127.0.0.1]/hook", nil, false},
+		{"http://10.1.2.3/hook", nil, false},
+		{"http://192.168.1.20/hook", nil, false},
+		{"http://169.254.169.254/latest/meta-data/", nil, false},
+		{"http://[fe80::1]/hook", nil, false},
+		{"http://0.0.0.0/hook", nil, false},
+		{"http://10.1.2.3/hook", []string{"10.0.0.0/8"}, true},
+		{"http://localhost:8080/hook", []string{"127.0.0.1"}, true},
+		{"http://192.168.1.20/hook", []string{"10.0.0.0/8"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			allow, err := ParseAllowlist(tt.allow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			SetWebhookAllowlist(allow)
+			t.Cleanup(func() { SetWebhookAllowlist(nil) })
+
+			w := Watch{Kind: KindAlert, AlertEvents: []string{"Tornado Warning"}, WebhookURL: tt.url}
+			if err := w.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+
+	if _, err := ParseAllowlist([]string{"10.0.0.0/8", "intranet"}); err == nil {
+		t.Error("ParseAllowlist accepted a host name")
+	}
+}
+
+func TestWebhookClientBlocksResolvedAddress(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	// A URL that got past Validate, e.g. a name that now resolves to
+	// loopback, is still refused when the connection is made.
+	e := Event{Key: "k", WatchID: "w-1", TriggeredAt: time.Now()}
+	err := postWebhook(context.Background(), webhookClient(), srv.URL, e)
+	var perm *permanentError
+	if !errors.As(err, &perm) || !errors.Is(err, errBlockedAddress) || hits != 0 {
+		t.Errorf("postWebhook to loopback: error = %v, %d requests received", err, hits)
+	}
+
+	allowLoopback(t)
+	if err := postWebhook(context.Background(), webhookClient(), srv.URL, e); err != nil || hits != 1 {
+		t.Errorf("postWebhook to allowlisted loopback: error = %v, %d requests received", err, hits)
+	}
+}
@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+	maxPending     = 20
+)
+
+// permanentError is a webhook failure that retrying will not fix, such as a
+// 404; the event is dropped instead of being kept for the next check.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// postWebhook POSTs the event as JSON with its key in the Idempotency-Key
+// header. It makes a single attempt: network errors, 429 and 5xx responses
+// are retried on the next check; blocked addresses and other non-2xx
+// responses fail permanently.
+func postWebhook(ctx context.Context, client *http.Client, url string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return &permanentError{err}
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", e.Key)
+	req.Header.Set("User-Agent", "weather-mcp-watch/1.0")
+
+	resp, err := client.Do(req)
+	if errors.Is(err, errBlockedAddress) {
+		return &permanentError{err}
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+	}
+	return &permanentError{fmt.Errorf("webhook: unexpected status %s", resp.Status)}
+}
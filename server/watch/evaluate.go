@@ -0,0 +1,155 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+	"weather/server/nws"
+	"weather/server/provider"
+	"weather/server/weathercalc"
+)
+
+// Source is the weather data watches are evaluated against.
+type Source interface {
+	Hourly(ctx context.Context, lat, lon float64) (*provider.Hourly, error)
+	Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error)
+}
+
+// evaluate checks w against current data and updates its state, appending
+// an event to w.Pending for each new firing. Errors leave the state as it
+// was so a failed fetch neither fires nor re-arms the watch.
+func evaluate(ctx context.Context, w *Watch, src Source, now time.Time) error {
+	switch w.Kind {
+	case KindThreshold:
+		hourly, err := src.Hourly(ctx, w.Latitude, w.Longitude)
+		if err != nil {
+			return err
+		}
+		if w.Element == "gust" && hourly.Source == "nws" {
+			fillGusts(ctx, w.Latitude, w.Longitude, hourly.Periods)
+		}
+		evaluateThreshold(w, hourly.Periods, now)
+	case KindAlert:
+		alerts, err := src.Alerts(ctx, w.Latitude, w.Longitude)
+		if err != nil {
+			return err
+		}
+		evaluateAlerts(w, alerts, now)
+	}
+	return nil
+}
+
+// fillGusts adds gusts from the NWS forecast grid to hourly periods, which
+// the NWS hourly forecast does not include. It is best effort: on failure
+// gust watches fall back to the sustained wind.
+func fillGusts(ctx context.Context, lat, lon float64, periods []dtos.HourlyPeriod) {
+	point, err := nws.ResolvePoint(ctx, lat, lon)
+	if err != nil || point.ForecastGridDataURL == "" {
+		return
+	}
+	grid, err := nws.Gridpoint(ctx, point.ForecastGridDataURL)
+	if err != nil {
+		return
+	}
+	layer := grid.Properties.WindGust
+	intervals := nws.Intervals(layer)
+	for i := range periods {
+		start, err := time.Parse(time.RFC3339, periods[i].StartTime)
+		if err != nil {
+			continue
+		}
+		for _, iv := range intervals {
+			if start.Before(iv.Start) || !start.Before(iv.End) {
+				continue
+			}
+			gust := iv.Value
+			switch strings.TrimPrefix(layer.UnitCode, "wmoUnit:") {
+			case "km_h-1":
+				gust = weathercalc.KmhToMph(gust)
+			case "m_s-1":
+				gust = weathercalc.MpsToMph(gust)
+			}
+			periods[i].WindGust = &gust
+			break
+		}
+	}
+}
+
+// evaluateThreshold fires when the first forecast hour meeting the condition
+// appears after a check in which none did. The key is tied to when the
+// condition started being met, so one episode is one delivery.
+func evaluateThreshold(w *Watch, periods []dtos.HourlyPeriod, now time.Time) {
+	until := now.Add(time.Duration(w.WithinHours) * time.Hour)
+	for _, h := range periods {
+		start, err := time.Parse(time.RFC3339, h.StartTime)
+		if err != nil || !start.Add(time.Hour).After(now) || !start.Before(until) {
+			continue
+		}
+		v, ok := elementValue(w.Element, h)
+		if !ok || !compare(v, w.Operator, w.Value) {
+			continue
+		}
+		if w.Triggered {
+			return
+		}
+		w.Triggered = true
+		v = math.Round(v*10) / 10
+		w.Pending = append(w.Pending, Event{
+			Key:         eventKey(w.ID, now.UTC().Format(time.RFC3339)),
+			WatchID:     w.ID,
+			WatchName:   w.Name,
+			Kind:        w.Kind,
+			Latitude:    w.Latitude,
+			Longitude:   w.Longitude,
+			Summary:     fmt.Sprintf("%s: forecast %s %.0f%s at %s (watch: %s)", w.label(), w.Element, v, elementUnit(w.Element), h.StartTime, w.Condition()),
+			TriggeredAt: now.UTC(),
+			ValidAt:     h.StartTime,
+			Value:       &v,
+		})
+		return
+	}
+	w.Triggered = false
+}
+
+// evaluateAlerts fires once per matching alert. SeenAlerts is trimmed to the
+// alerts still active so the list does not grow without bound.
+func evaluateAlerts(w *Watch, alerts []dtos.Feature, now time.Time) {
+	var active []string
+	for _, a := range alerts {
+		if !slices.ContainsFunc(w.AlertEvents, func(e string) bool { return strings.EqualFold(e, a.Event) }) {
+			continue
+		}
+		active = append(active, a.ID)
+		if slices.Contains(w.SeenAlerts, a.ID) {
+			continue
+		}
+		summary := a.Headline
+		if summary == "" {
+			summary = a.Event + " for " + a.AreaDesc
+		}
+		w.Pending = append(w.Pending, Event{
+			Key:         eventKey(w.ID, a.ID),
+			WatchID:     w.ID,
+			WatchName:   w.Name,
+			Kind:        w.Kind,
+			Latitude:    w.Latitude,
+			Longitude:   w.Longitude,
+			Summary:     w.label() + ": " + summary,
+			TriggeredAt: now.UTC(),
+			ValidAt:     a.Onset,
+			AlertID:     a.ID,
+		})
+	}
+	w.SeenAlerts = active
+}
+
+func (w *Watch) label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.ID
+}
@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers an event to the MCP session with the given ID, the one
+// that created the watch. Sessions come and go, so notification is best
+// effort and never retried.
+type Notifier func(ctx context.Context, sessionID string, e Event) error
+
+// Scheduler re-evaluates every watch in a store on an interval and delivers
+// the events they produce. Each check makes one webhook attempt per event;
+// events whose delivery fails are kept on the watch and retried on the next
+// check with the same idempotency key.
+type Scheduler struct {
+	store    *Store
+	source   Source
+	interval time.Duration
+	notify   Notifier
+	client   *http.Client
+	wake     chan struct{}
+}
+
+// NewScheduler creates a scheduler checking the watches in store against
+// source every interval. notify may be nil.
+func NewScheduler(store *Store, source Source, interval time.Duration, notify Notifier) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		source:   source,
+		interval: interval,
+		notify:   notify,
+		client:   webhookClient(),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Run checks the watches immediately, then every interval and whenever Wake
+// is called, until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// Wake asks a running scheduler to check the watches now, e.g. after one was
+// created. It does not block.
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Check evaluates every watch once and delivers pending events.
+func (s *Scheduler) Check(ctx context.Context) {
+	for _, w := range s.store.List() {
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+		err := evaluate(ctx, &w, s.source, now)
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		s.deliver(ctx, &w)
+
+		err = s.store.Update(w.ID, func(stored *Watch) {
+			stored.LastChecked = now.UTC()
+			stored.LastError = ""
+			if err != nil {
+				stored.LastError = err.Error()
+			}
+			stored.Triggered = w.Triggered
+			stored.SeenAlerts = w.SeenAlerts
+			stored.Pending = w.Pending
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			slog.Warn("saving watch state", "watch", w.ID, "error", err)
+		}
+	}
+}
+
+// deliver sends each pending event, leaving in w.Pending only those whose
+// webhook delivery should be retried later. The MCP notification goes out
+// on the first attempt only.
+func (s *Scheduler) deliver(ctx context.Context, w *Watch) {
+	var retry []Event
+	for _, e := range w.Pending {
+		if e.Attempts == 0 && s.notify != nil {
+			if err := s.notify(ctx, w.SessionID, e); err != nil {
+				slog.Debug("watch notification not delivered", "watch", w.ID, "key", e.Key, "error", err)
+			}
+		}
+		e.Attempts++
+		if w.WebhookURL == "" {
+			continue
+		}
+		err := postWebhook(ctx, s.client, w.WebhookURL, e)
+		var perm *permanentError
+		switch {
+		case err == nil:
+		case errors.As(err, &perm):
+			slog.Warn("dropping watch event after webhook rejected it", "watch", w.ID, "key", e.Key, "error", err)
+		default:
+			slog.Warn("watch webhook failed, will retry", "watch", w.ID, "key", e.Key, "error", err)
+			retry = append(retry, e)
+		}
+	}
+	// Keep the newest events if a webhook has been down for a long time.
+	if len(retry) > maxPending {
+		retry = retry[len(retry)-maxPending:]
+	}
+	w.Pending = retry
+}
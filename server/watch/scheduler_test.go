@@ -0,0 +1,212 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"weather/server/dtos"
+	"weather/server/provider"
+)
+
+// fakeSource serves a fixed hourly forecast and set of alerts.
+type fakeSource struct {
+	periods []dtos.HourlyPeriod
+	alerts  []dtos.Feature
+}
+
+func (f *fakeSource) Hourly(ctx context.Context, lat, lon float64) (*provider.Hourly, error) {
+	return &provider.Hourly{Source: "openmeteo", Periods: f.periods}, nil
+}
+
+func (f *fakeSource) Alerts(ctx context.Context, lat, lon float64) ([]dtos.Feature, error) {
+	return f.alerts, nil
+}
+
+// hours returns hourly periods starting this hour with the given
+// temperatures.
+func hours(temps ...float64) []dtos.HourlyPeriod {
+	start := time.Now().Truncate(time.Hour)
+	var periods []dtos.HourlyPeriod
+	for i, t := range temps {
+		periods = append(periods, dtos.HourlyPeriod{
+			StartTime:   start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			Temperature: t,
+		})
+	}
+	return periods
+}
+
+// webhook records the events POSTed to it and answers with the next status
+// in statuses, then 200 once they run out.
+type webhook struct {
+	mu       sync.Mutex
+	statuses []int
+	keys     []string
+	events   []Event
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var e Event
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.keys = append(h.keys, r.Header.Get("Idempotency-Key"))
+	h.events = append(h.events, e)
+	if len(h.statuses) > 0 {
+		w.WriteHeader(h.statuses[0])
+		h.statuses = h.statuses[1:]
+	}
+}
+
+func (h *webhook) received() []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Event(nil), h.events...)
+}
+
+func (h *webhook) idempotencyKeys() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.keys...)
+}
+
+// newTestScheduler stores w with a webhook served by hook and returns a
+// scheduler over src, counting MCP notifications in notified. The test
+// server listens on loopback, so it is allowlisted for the test.
+func newTestScheduler(t *testing.T, w Watch, src Source, hook *webhook, notified *int) (*Scheduler, *Store) {
+	t.Helper()
+	srv := httptest.NewServer(hook)
+	t.Cleanup(srv.Close)
+	allowLoopback(t)
+
+	store, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WebhookURL = srv.URL + "/hook"
+	if _, err := store.Add(w); err != nil {
+		t.Fatal(err)
+	}
+	notify := func(ctx context.Context, sessionID string, e Event) error {
+		*notified++
+		return nil
+	}
+	return NewScheduler(store, src, time.Hour, notify), store
+}
+
+func TestThresholdFiresOncePerEpisode(t *testing.T) {
+	src := &fakeSource{periods: hours(95, 101, 99)}
+	hook := &webhook{}
+	var notified int
+	s, store := newTestScheduler(t, Watch{Kind: KindThreshold, Element: "temperature", Operator: ">", Value: 100}, src, hook, &notified)
+	ctx := context.Background()
+
+	s.Check(ctx)
+	s.Check(ctx)
+	got := hook.received()
+	if len(got) != 1 || notified != 1 {
+		t.Fatalf("after two checks: %d webhook events, %d notifications, want 1 each", len(got), notified)
+	}
+	if got[0].Value == nil || *got[0].Value != 101 || got[0].ValidAt != src.periods[1].StartTime {
+		t.Errorf("event = %+v, want 101 at %s", got[0], src.periods[1].StartTime)
+	}
+
+	// The condition clears, re-arming the watch, then is met again.
+	src.periods = hours(95, 96)
+	s.Check(ctx)
+	if w := store.List()[0]; w.Triggered {
+		t.Error("watch still triggered after the condition cleared")
+	}
+	src.periods = hours(102)
+	s.Check(ctx)
+	if got := hook.received(); len(got) != 2 || notified != 2 {
+		t.Errorf("after re-arming: %d webhook events, %d notifications, want 2 each", len(got), notified)
+	}
+}
+
+func TestWebhookRetriedOnNextCheck(t *testing.T) {
+	src := &fakeSource{periods: hours(101)}
+	hook := &webhook{statuses: []int{http.StatusServiceUnavailable}}
+	var notified int
+	s, store := newTestScheduler(t, Watch{Kind: KindThreshold, Element: "temperature", Operator: ">", Value: 100}, src, hook, &notified)
+	ctx := context.Background()
+
+	// One attempt per check: the 503 leaves the event pending.
+	s.Check(ctx)
+	if n := len(hook.received()); n != 1 {
+		t.Fatalf("first check made %d webhook attempts, want 1", n)
+	}
+	w := store.List()[0]
+	if len(w.Pending) != 1 || w.Pending[0].Attempts != 1 {
+		t.Fatalf("pending after a 503 = %+v, want one event after one attempt", w.Pending)
+	}
+
+	s.Check(ctx)
+	if keys := hook.idempotencyKeys(); len(keys) != 2 || keys[0] != keys[1] || keys[0] != w.Pending[0].Key {
+		t.Errorf("idempotency keys = %v, want the pending event's key twice", keys)
+	}
+	if w := store.List()[0]; len(w.Pending) != 0 {
+		t.Errorf("pending after delivery = %+v", w.Pending)
+	}
+	if notified != 1 {
+		t.Errorf("notified %d times, want once for the first attempt only", notified)
+	}
+}
+
+func TestWebhookRejectedIsDropped(t *testing.T) {
+	src := &fakeSource{periods: hours(101)}
+	hook := &webhook{statuses: []int{http.StatusNotFound}}
+	var notified int
+	s, store := newTestScheduler(t, Watch{Kind: KindThreshold, Element: "temperature", Operator: ">", Value: 100}, src, hook, &notified)
+
+	s.Check(context.Background())
+	s.Check(context.Background())
+	if n := len(hook.received()); n != 1 {
+		t.Errorf("made %d webhook attempts after a 404, want 1", n)
+	}
+	if w := store.List()[0]; len(w.Pending) != 0 {
+		t.Errorf("pending after a 404 = %+v", w.Pending)
+	}
+}
+
+func TestAlertWatchFiresOncePerAlert(t *testing.T) {
+	alert := func(id, event string) dtos.Feature {
+		return dtos.Feature{ID: id, AlertProperties: dtos.AlertProperties{Event: event, AreaDesc: "Travis"}}
+	}
+	src := &fakeSource{alerts: []dtos.Feature{
+		alert("a1", "Red Flag Warning"),
+		alert("a2", "Heat Advisory"),
+		alert("a3", "red flag warning"),
+	}}
+	hook := &webhook{}
+	var notified int
+	s, store := newTestScheduler(t, Watch{Kind: KindAlert, AlertEvents: []string{"Red Flag Warning"}}, src, hook, &notified)
+	ctx := context.Background()
+
+	s.Check(ctx)
+	s.Check(ctx)
+	got := hook.received()
+	if len(got) != 2 || got[0].AlertID != "a1" || got[1].AlertID != "a3" {
+		t.Fatalf("webhook events = %+v, want a1 and a3 once each", got)
+	}
+	if got[0].Key == got[1].Key {
+		t.Error("two alerts share an idempotency key")
+	}
+
+	// a1 expires, so it is no longer remembered.
+	src.alerts = src.alerts[1:]
+	s.Check(ctx)
+	if w := store.List()[0]; len(w.SeenAlerts) != 1 || w.SeenAlerts[0] != "a3" {
+		t.Errorf("SeenAlerts = %v, want [a3]", w.SeenAlerts)
+	}
+	if n := len(hook.received()); n != 2 || notified != 2 {
+		t.Errorf("%d webhook events, %d notifications after a1 expired, want 2 each", n, notified)
+	}
+}
@@ -0,0 +1,190 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxPerOwner bounds the watches one session or owner token holds.
+	MaxPerOwner = 20
+	// MaxWatches bounds the number of watches a store holds.
+	MaxWatches = 1000
+)
+
+var (
+	// ErrNotFound is returned for an unknown watch ID.
+	ErrNotFound = errors.New("watch: not found")
+	// ErrFull is returned when the owner already holds MaxPerOwner watches
+	// or the store MaxWatches.
+	ErrFull = errors.New("watch: too many watches")
+)
+
+// Store holds watches in memory and, when it has a path, persists them to a
+// JSON file after every change so they survive restarts.
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	watches []Watch
+}
+
+// Open loads the store at path, creating it on first write. An empty path
+// keeps watches in memory only.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("watch: reading store: %w", err)
+	}
+	var file struct {
+		Watches []Watch `json:"watches"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("watch: decoding %s: %w", path, err)
+	}
+	s.watches = file.Watches
+	return s, nil
+}
+
+// Add validates w, assigns its ID and creation time, and stores it.
+func (s *Store) Add(w Watch) (Watch, error) {
+	if err := w.Validate(); err != nil {
+		return Watch{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owned := 0
+	for _, other := range s.watches {
+		if other.ownerKey() == w.ownerKey() {
+			owned++
+		}
+	}
+	if owned >= MaxPerOwner || len(s.watches) >= MaxWatches {
+		return Watch{}, ErrFull
+	}
+	w.ID = newID()
+	w.CreatedAt = time.Now().UTC()
+	s.watches = append(s.watches, w)
+	return w, s.save()
+}
+
+// List returns a copy of all watches.
+func (s *Store) List() []Watch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.watches)
+}
+
+// Owned returns a copy of the watches c may manage.
+func (s *Store) Owned(c Caller) []Watch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var owned []Watch
+	for _, w := range s.watches {
+		if w.ownedBy(c) {
+			owned = append(owned, w)
+		}
+	}
+	return owned
+}
+
+// Delete removes a watch c may manage. Anyone else's watch is reported as
+// ErrNotFound, as if it did not exist.
+func (s *Store) Delete(id string, c Caller) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 || !s.watches[i].ownedBy(c) {
+		return ErrNotFound
+	}
+	s.watches = slices.Delete(s.watches, i, i+1)
+	return s.save()
+}
+
+// Remove deletes watches by ID whoever owns them, for administration. It
+// returns the IDs it did not find.
+func (s *Store) Remove(ids ...string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var missing []string
+	for _, id := range ids {
+		i := s.index(id)
+		if i < 0 {
+			missing = append(missing, id)
+			continue
+		}
+		s.watches = slices.Delete(s.watches, i, i+1)
+	}
+	return missing, s.save()
+}
+
+// ExpireSession removes the watches that belonged to a session that has
+// ended, keeping those created with an owner token. An empty sessionID
+// removes every watch without an owner token, as after a restart, when no
+// earlier session can come back to manage them.
+func (s *Store) ExpireSession(sessionID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.watches)
+	s.watches = slices.DeleteFunc(s.watches, func(w Watch) bool {
+		return w.Owner == "" && (sessionID == "" || w.SessionID == sessionID)
+	})
+	if removed := n - len(s.watches); removed > 0 {
+		return removed, s.save()
+	}
+	return 0, nil
+}
+
+// Update applies fn to the stored watch with the given ID and saves the
+// result. It returns ErrNotFound if the watch was deleted meanwhile.
+func (s *Store) Update(id string, fn func(*Watch)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	fn(&s.watches[i])
+	return s.save()
+}
+
+func (s *Store) index(id string) int {
+	return slices.IndexFunc(s.watches, func(w Watch) bool { return w.ID == id })
+}
+
+// save writes the watches to a temporary file and renames it into place, so
+// a crash never leaves a half-written store. s.mu must be held.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(struct {
+		Watches []Watch `json:"watches"`
+	}{s.watches}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("watch: saving store: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("watch: saving store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("watch: saving store: %w", err)
+	}
+	return nil
+}
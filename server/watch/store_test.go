@@ -0,0 +1,82 @@
+package watch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStoreScopesToCaller(t *testing.T) {
+	store, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := OwnerOf("correct-horse-battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(sessionID, owner string) Watch {
+		w, err := store.Add(Watch{Kind: KindAlert, AlertEvents: []string{"Tornado Warning"}, SessionID: sessionID, Owner: owner})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+	mine, theirs, kept := add("a", ""), add("b", ""), add("b", owner)
+
+	if owned := store.Owned(Caller{SessionID: "a"}); len(owned) != 1 || owned[0].ID != mine.ID {
+		t.Errorf("Owned(a) = %+v, want only %s", owned, mine.ID)
+	}
+	// The owner token reaches the watch from any session.
+	if owned := store.Owned(Caller{SessionID: "c", Owner: owner}); len(owned) != 1 || owned[0].ID != kept.ID {
+		t.Errorf("Owned(token) = %+v, want only %s", owned, kept.ID)
+	}
+	if err := store.Delete(theirs.ID, Caller{SessionID: "a"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting another session's watch: error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(mine.ID, Caller{SessionID: "a"}); err != nil {
+		t.Errorf("deleting own watch: %v", err)
+	}
+
+	// Session b ends: its session watch goes, the one with a token stays.
+	if n, err := store.ExpireSession("b"); err != nil || n != 1 {
+		t.Errorf("ExpireSession(b) = %d, %v, want 1 removed", n, err)
+	}
+	if all := store.List(); len(all) != 1 || all[0].ID != kept.ID {
+		t.Errorf("List after expiry = %+v, want only %s", all, kept.ID)
+	}
+	if missing, err := store.Remove(kept.ID, "w-missing"); err != nil || len(missing) != 1 || len(store.List()) != 0 {
+		t.Errorf("Remove = %v, %v with %d left", missing, err, len(store.List()))
+	}
+}
+
+func TestStoreCapsEachOwner(t *testing.T) {
+	store, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range MaxPerOwner {
+		if _, err := store.Add(Watch{Kind: KindAlert, AlertEvents: []string{"Tornado Warning"}, SessionID: "a"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Add(Watch{Kind: KindAlert, AlertEvents: []string{"Tornado Warning"}, SessionID: "a"}); !errors.Is(err, ErrFull) {
+		t.Errorf("watch %d for one session: error = %v, want ErrFull", MaxPerOwner+1, err)
+	}
+	if _, err := store.Add(Watch{Kind: KindAlert, AlertEvents: []string{"Tornado Warning"}, SessionID: "b"}); err != nil {
+		t.Errorf("another session's first watch: %v", err)
+	}
+}
+
+func TestOwnerOf(t *testing.T) {
+	if owner, err := OwnerOf(""); owner != "" || err != nil {
+		t.Errorf("OwnerOf(\"\") = %q, %v", owner, err)
+	}
+	if _, err := OwnerOf("short"); err == nil {
+		t.Error("OwnerOf accepted a short token")
+	}
+	a, _ := OwnerOf("correct-horse-battery")
+	b, _ := OwnerOf("correct-horse-battery")
+	if a == "" || a != b || a == "correct-horse-battery" {
+		t.Errorf("OwnerOf = %q and %q, want the same hash", a, b)
+	}
+}
@@ -0,0 +1,235 @@
+// Package watch implements weather watches: conditions on a location's
+// forecast or alerts that are re-evaluated on a schedule and, when they are
+// met, delivered to a webhook and to the MCP session that created them.
+package watch
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"weather/server/dtos"
+)
+
+// Watch kinds.
+const (
+	KindThreshold = "threshold"
+	KindAlert     = "alert"
+)
+
+// Elements are the hourly forecast values a threshold watch can test.
+var Elements = []string{"temperature", "dew_point", "humidity", "wind", "gust", "precipitation_chance"}
+
+// Operators are the comparisons a threshold watch can use.
+var Operators = []string{">", ">=", "<", "<="}
+
+const (
+	defaultWithinHours = 24
+	maxWithinHours     = 156
+)
+
+// Watch is a condition on the weather at a location. A threshold watch fires
+// when any forecast hour in the next WithinHours has Element Operator Value,
+// e.g. gust > 40; it fires again only after the condition has cleared. An
+// alert watch fires once for each active alert whose event is in
+// AlertEvents.
+//
+// A watch belongs to the session that created it and lasts as long as that
+// session, unless it was created with an owner token: then it has an Owner,
+// survives restarts, and any session giving the same token manages it.
+type Watch struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Kind        string    `json:"kind"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Element     string    `json:"element,omitempty"`
+	Operator    string    `json:"operator,omitempty"`
+	Value       float64   `json:"value,omitempty"`
+	WithinHours int       `json:"withinHours,omitempty"`
+	AlertEvents []string  `json:"alertEvents,omitempty"`
+	WebhookURL  string    `json:"webhookUrl,omitempty"`
+	SessionID   string    `json:"sessionId,omitempty"`
+	Owner       string    `json:"owner,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// Evaluation state.
+	LastChecked time.Time `json:"lastChecked,omitzero"`
+	LastError   string    `json:"lastError,omitempty"`
+	Triggered   bool      `json:"triggered,omitempty"`
+	SeenAlerts  []string  `json:"seenAlerts,omitempty"`
+	Pending     []Event   `json:"pending,omitempty"`
+}
+
+// Event is a watch firing, delivered with an idempotency key that stays the
+// same across retries so receivers can drop duplicates.
+type Event struct {
+	Key         string    `json:"idempotencyKey"`
+	WatchID     string    `json:"watchId"`
+	WatchName   string    `json:"watchName,omitempty"`
+	Kind        string    `json:"kind"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Summary     string    `json:"summary"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+	ValidAt     string    `json:"validAt,omitempty"`
+	Value       *float64  `json:"value,omitempty"`
+	AlertID     string    `json:"alertId,omitempty"`
+	Attempts    int       `json:"attempts,omitempty"`
+}
+
+// minTokenLength is the shortest owner token accepted, so tokens cannot be
+// guessed.
+const minTokenLength = 16
+
+// Caller is who manages watches: a session, and the owner derived from the
+// token it gave, if any.
+type Caller struct {
+	SessionID string
+	Owner     string
+}
+
+// OwnerOf derives the owner of watches created with token, so the token
+// itself is never stored. An empty token has no owner.
+func OwnerOf(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	if len(token) < minTokenLength {
+		return "", fmt.Errorf("an owner token must be at least %d characters", minTokenLength)
+	}
+	sum := sha256.Sum256([]byte("owner|" + token))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// ownedBy reports whether c may list and delete w.
+func (w *Watch) ownedBy(c Caller) bool {
+	return (w.Owner != "" && w.Owner == c.Owner) || (w.SessionID != "" && w.SessionID == c.SessionID)
+}
+
+// ownerKey groups the watches that count towards one owner's limit.
+func (w *Watch) ownerKey() string {
+	if w.Owner != "" {
+		return "owner:" + w.Owner
+	}
+	return "session:" + w.SessionID
+}
+
+// Validate checks a new watch and fills in its defaults.
+func (w *Watch) Validate() error {
+	if w.Latitude < -90 || w.Latitude > 90 || w.Longitude < -180 || w.Longitude > 180 {
+		return fmt.Errorf("%.4f, %.4f is not a valid coordinate", w.Latitude, w.Longitude)
+	}
+	switch w.Kind {
+	case KindThreshold:
+		if !slices.Contains(Elements, w.Element) {
+			return fmt.Errorf("element must be one of %s", strings.Join(Elements, ", "))
+		}
+		if !slices.Contains(Operators, w.Operator) {
+			return fmt.Errorf("operator must be one of %s", strings.Join(Operators, " "))
+		}
+		if w.WithinHours <= 0 {
+			w.WithinHours = defaultWithinHours
+		}
+		w.WithinHours = min(w.WithinHours, maxWithinHours)
+	case KindAlert:
+		if len(w.AlertEvents) == 0 {
+			return errors.New("an alert watch needs at least one alert event, e.g. \"Red Flag Warning\"")
+		}
+	default:
+		return fmt.Errorf("kind must be %q or %q", KindThreshold, KindAlert)
+	}
+	if w.WebhookURL != "" {
+		u, err := url.Parse(w.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook URL %q must be an absolute http or https URL", w.WebhookURL)
+		}
+		if err := checkHost(u.Hostname()); err != nil {
+			return fmt.Errorf("webhook URL %q is not allowed: its host is on a private, loopback or link-local network", w.WebhookURL)
+		}
+	}
+	return nil
+}
+
+// Condition describes what the watch looks for, e.g. "gust > 40 mph within
+// 24h" or "alerts: Red Flag Warning".
+func (w *Watch) Condition() string {
+	if w.Kind == KindAlert {
+		return "alerts: " + strings.Join(w.AlertEvents, ", ")
+	}
+	return fmt.Sprintf("%s %s %g%s within %dh", w.Element, w.Operator, w.Value, elementUnit(w.Element), w.WithinHours)
+}
+
+// elementValue returns the value of a forecast element in an hour. Without a
+// gust forecast the sustained wind stands in as a lower bound for gusts.
+func elementValue(element string, h dtos.HourlyPeriod) (float64, bool) {
+	switch element {
+	case "temperature":
+		return h.Temperature, true
+	case "wind":
+		return h.WindSpeed, true
+	case "gust":
+		if h.WindGust != nil {
+			return *h.WindGust, true
+		}
+		return h.WindSpeed, true
+	case "dew_point":
+		return deref(h.DewPoint)
+	case "humidity":
+		return deref(h.RelativeHumidity)
+	case "precipitation_chance":
+		return deref(h.ProbabilityOfPrecipitation)
+	}
+	return 0, false
+}
+
+func elementUnit(element string) string {
+	switch element {
+	case "temperature", "dew_point":
+		return "°F"
+	case "wind", "gust":
+		return " mph"
+	case "humidity", "precipitation_chance":
+		return "%"
+	}
+	return ""
+}
+
+func deref(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+func compare(v float64, op string, threshold float64) bool {
+	switch op {
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	}
+	return false
+}
+
+// newID returns a random watch ID such as "w-3f9a1c2b7d4e".
+func newID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "w-" + hex.EncodeToString(b)
+}
+
+// eventKey derives a stable idempotency key from a watch and what fired it.
+func eventKey(watchID, discriminator string) string {
+	sum := sha256.Sum256([]byte(watchID + "|" + discriminator))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,83 @@
+// Package weathercalc implements the NWS formulas for derived weather values
+// (heat index, wind chill, dew point, apparent temperature) and the unit
+// conversions between the units NWS, METARs and other providers report in.
+//
+// Temperatures are in °F, wind speeds in mph and humidity in percent unless a
+// function says otherwise.
+package weathercalc
+
+import "math"
+
+// HeatIndex returns the NWS heat index for a temperature and relative
+// humidity. It uses Steadman's simple formula below 80°F and the Rothfusz
+// regression, with the NWS low- and high-humidity adjustments, above it.
+func HeatIndex(tempF, rh float64) float64 {
+	simple := 0.5 * (tempF + 61 + (tempF-68)*1.2 + rh*0.094)
+	if (simple+tempF)/2 < 80 {
+		return simple
+	}
+
+	t, r := tempF, rh
+	hi := -42.379 + 2.04901523*t + 10.14333127*r - 0.22475541*t*r -
+		0.00683783*t*t - 0.05481717*r*r + 0.00122874*t*t*r +
+		0.00085282*t*r*r - 0.00000199*t*t*r*r
+
+	switch {
+	case r < 13 && t >= 80 && t <= 112:
+		hi -= (13 - r) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case r > 85 && t >= 80 && t <= 87:
+		hi += (r - 85) / 10 * (87 - t) / 5
+	}
+	return hi
+}
+
+// WindChill returns the NWS (2001) wind chill temperature. The formula is
+// only defined at or below 50°F with wind of at least 3 mph; ok is false
+// otherwise.
+func WindChill(tempF, windMph float64) (chill float64, ok bool) {
+	if tempF > 50 || windMph < 3 {
+		return tempF, false
+	}
+	v := math.Pow(windMph, 0.16)
+	return 35.74 + 0.6215*tempF - 35.75*v + 0.4275*tempF*v, true
+}
+
+// Magnus coefficients (Alduchov and Eskridge, 1996), valid from -40°C to 50°C.
+const (
+	magnusA = 17.625
+	magnusB = 243.04 // °C
+)
+
+// DewPoint returns the dew point for a temperature and relative humidity
+// using the Magnus approximation. Perfectly dry air has no dew point, so it
+// returns NaN when rh is 0 or less.
+func DewPoint(tempF, rh float64) float64 {
+	if rh <= 0 {
+		return math.NaN()
+	}
+	t := FahrenheitToCelsius(tempF)
+	gamma := math.Log(rh/100) + magnusA*t/(magnusB+t)
+	return CelsiusToFahrenheit(magnusB * gamma / (magnusA - gamma))
+}
+
+// RelativeHumidity returns the relative humidity for a temperature and dew
+// point, the inverse of DewPoint.
+func RelativeHumidity(tempF, dewPointF float64) float64 {
+	t := FahrenheitToCelsius(tempF)
+	td := FahrenheitToCelsius(dewPointF)
+	rh := 100 * math.Exp(magnusA*td/(magnusB+td)-magnusA*t/(magnusB+t))
+	return math.Min(rh, 100)
+}
+
+// ApparentTemperature returns the "feels like" temperature as NWS reports
+// it: the wind chill when it applies, the heat index at 80°F and above, and
+// the air temperature otherwise.
+func ApparentTemperature(tempF, rh, windMph float64) float64 {
+	if chill, ok := WindChill(tempF, windMph); ok {
+		return chill
+	}
+	if tempF >= 80 {
+		return HeatIndex(tempF, rh)
+	}
+	return tempF
+}
@@ -0,0 +1,82 @@
+package weathercalc
+
+import (
+	"math"
+	"testing"
+)
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestHeatIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		temp, rh float64
+		want     float64
+	}{
+		// Values from the NWS heat index chart.
+		{"chart", 96, 65, 121},
+		{"chart", 90, 40, 91},
+		// Below 80°F Steadman's simple formula is used.
+		{"simple", 70, 50, 69.05},
+		// Dry air: the regression less (13-10)/4 * sqrt((17-5)/17) = 0.63.
+		{"low humidity", 100, 10, 94.12},
+		// Humid air below 87°F: the regression plus (90-85)/10 * (87-84)/5.
+		{"high humidity", 84, 90, 98.34},
+		{"high humidity", 86, 100, 111.85},
+	}
+	for _, tt := range tests {
+		if got := HeatIndex(tt.temp, tt.rh); !near(got, tt.want, 0.5) {
+			t.Errorf("%s: HeatIndex(%v, %v) = %.2f, want %v", tt.name, tt.temp, tt.rh, got, tt.want)
+		}
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	tests := []struct {
+		temp, wind float64
+		want       float64
+		ok         bool
+	}{
+		// Values from the NWS wind chill chart.
+		{0, 15, -19, true},
+		{-20, 30, -53, true},
+		// The formula's limits are inclusive.
+		{50, 3, 49.5, true},
+		{50.1, 10, 50.1, false},
+		{30, 2.9, 30, false},
+	}
+	for _, tt := range tests {
+		got, ok := WindChill(tt.temp, tt.wind)
+		if ok != tt.ok || !near(got, tt.want, 0.5) {
+			t.Errorf("WindChill(%v, %v) = %.2f, %v; want %v, %v", tt.temp, tt.wind, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDewPointRoundTrip(t *testing.T) {
+	for _, temp := range []float64{-20, 32, 77, 104} {
+		for _, rh := range []float64{5, 30, 50, 85, 100} {
+			dew := DewPoint(temp, rh)
+			if got := RelativeHumidity(temp, dew); !near(got, rh, 0.01) {
+				t.Errorf("RelativeHumidity(%v, DewPoint(%v, %v) = %.2f) = %.3f", temp, temp, rh, dew, got)
+			}
+		}
+	}
+	if got := DewPoint(77, 50); !near(got, 56.9, 0.1) {
+		t.Errorf("DewPoint(77, 50) = %.2f, want 56.9", got)
+	}
+	if got := DewPoint(90, 100); !near(got, 90, 1e-9) {
+		t.Errorf("DewPoint at saturation = %v, want the temperature", got)
+	}
+	for _, rh := range []float64{0, -5} {
+		if got := DewPoint(50, rh); !math.IsNaN(got) {
+			t.Errorf("DewPoint(50, %v) = %v, want NaN", rh, got)
+		}
+	}
+	// A dew point above the temperature is supersaturation, reported as 100%.
+	if got := RelativeHumidity(60, 65); got != 100 {
+		t.Errorf("RelativeHumidity(60, 65) = %v, want 100", got)
+	}
+}
@@ -0,0 +1,27 @@
+package weathercalc
+
+// Temperature conversions.
+
+func FahrenheitToCelsius(f float64) float64 { return (f - 32) * 5 / 9 }
+func CelsiusToFahrenheit(c float64) float64 { return c*9/5 + 32 }
+
+// Speed conversions.
+
+func KnotsToMph(kt float64) float64  { return kt * 1.150779 }
+func MphToKnots(mph float64) float64 { return mph / 1.150779 }
+func KmhToMph(kmh float64) float64   { return kmh / 1.609344 }
+func MphToKmh(mph float64) float64   { return mph * 1.609344 }
+func MpsToMph(mps float64) float64   { return mps * 2.236936 }
+func MphToMps(mph float64) float64   { return mph / 2.236936 }
+
+// Distance conversions.
+
+func MetersToMiles(m float64) float64  { return m / 1609.344 }
+func MilesToMeters(mi float64) float64 { return mi * 1609.344 }
+func MetersToFeet(m float64) float64   { return m * 3.28084 }
+
+// Pressure conversions.
+
+func PascalsToHPa(pa float64) float64 { return pa / 100 }
+func HPaToInHg(hpa float64) float64   { return hpa * 0.02953 }
+func InHgToHPa(inHg float64) float64  { return inHg / 0.02953 }